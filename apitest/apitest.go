@@ -0,0 +1,104 @@
+// Package apitest serves routes in tests and checks their responses the way clients of the API see them.
+//
+// Services are usually served over the in-memory store of package memstore:
+//
+//	store := memstore.New()
+//	handler := apitest.Handler(t, "/api/v1/link", func(api *gin.RouterGroup) {
+//		links.NewService(store, store).SetupLinkRoutes(api)
+//	})
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/middlewares"
+)
+
+// UnknownID is a well-formed ID which no resource has.
+const UnknownID = "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f"
+
+// Handler serves the routes set up on the group at prefix, reporting errors the way the API does.
+func Handler(t testing.TB, prefix string, setup func(api *gin.RouterGroup)) http.Handler {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middlewares.ErrorHandler())
+	setup(engine.Group(prefix))
+
+	return engine
+}
+
+// Serve sends a request with the body encoded as JSON, unless it is already a string,
+// and the headers given as name and value pairs.
+func Serve(t testing.TB, handler http.Handler, method, path string, body any, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var raw []byte
+	switch body := body.(type) {
+	case nil:
+	case string:
+		raw = []byte(body)
+	default:
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+	if raw != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+// Decode unmarshals the JSON body of the response, failing the test if it can't.
+func Decode[T any](t testing.TB, res *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var v T
+	if err := json.Unmarshal(res.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decoding %s: %v", res.Body, err)
+	}
+	return v
+}
+
+// ExpectProblem checks the status and the error code of a problem details response.
+func ExpectProblem(t testing.TB, res *httptest.ResponseRecorder, status int, code string) errs.Problem {
+	t.Helper()
+
+	if res.Code != status {
+		t.Fatalf("got status %d, want %d: %s", res.Code, status, res.Body)
+	}
+	problem := Decode[errs.Problem](t, res)
+	if problem.Code != code {
+		t.Errorf("got error code %q, want %q", problem.Code, code)
+	}
+	return problem
+}
+
+// ErrorCode returns the code the API reports err with, such as the code of a constraint violation
+// translated by a store. It is empty for errors without a code.
+func ErrorCode(err error) string {
+	var httpErr *errs.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.ErrorCode()
+	}
+	code, _ := errs.Code(err)
+	return code
+}
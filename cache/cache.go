@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores values by key for a limited time.
+// Implementations must be safe for concurrent use. A shared backend that fails
+// should report a miss rather than an error, so that callers fall back to the source.
+type Cache[V any] interface {
+	Get(ctx context.Context, key string) (V, bool)
+	Set(ctx context.Context, key string, value V, ttl time.Duration)
+	Delete(ctx context.Context, keys ...string)
+	Clear(ctx context.Context)
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestLRU returns a cache whose clock only moves when the returned function is called.
+func newTestLRU(capacity int) (*LRU[string], func(time.Duration)) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	c := NewLRU[string](capacity)
+	c.now = func() time.Time { return now }
+	return c, func(d time.Duration) { now = now.Add(d) }
+}
+
+// expectEntries reads the keys in order, expecting a miss for the empty values.
+// Reading an entry makes it the most recently used.
+func expectEntries(t *testing.T, c *LRU[string], pairs ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+	for i := 0; i < len(pairs); i += 2 {
+		key, value := pairs[i], pairs[i+1]
+		got, ok := c.Get(ctx, key)
+		if value == "" && ok {
+			t.Errorf("Get(%q): got %q, want a miss", key, got)
+		}
+		if value != "" && (!ok || got != value) {
+			t.Errorf("Get(%q): got %q, %t, want %q", key, got, ok, value)
+		}
+	}
+}
+
+func TestLRUEviction(t *testing.T) {
+	c, _ := newTestLRU(3)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", "1", time.Minute)
+	c.Set(ctx, "b", "2", time.Minute)
+	c.Set(ctx, "c", "3", time.Minute)
+
+	// Reading a makes b the least recently used entry
+	expectEntries(t, c, "a", "1")
+	c.Set(ctx, "d", "4", time.Minute)
+	expectEntries(t, c, "b", "", "c", "3", "d", "4", "a", "1")
+
+	// Updating an entry uses it without growing the cache, leaving c the least recently used
+	c.Set(ctx, "d", "5", time.Minute)
+	c.Set(ctx, "e", "6", time.Minute)
+	expectEntries(t, c, "c", "", "a", "1", "d", "5", "e", "6")
+
+	if got := c.Len(); got != 3 {
+		t.Errorf("Len: got %d, want 3", got)
+	}
+
+	c.Delete(ctx, "a", "missing")
+	expectEntries(t, c, "a", "")
+	if got := c.Len(); got != 2 {
+		t.Errorf("Len after Delete: got %d, want 2", got)
+	}
+
+	c.Clear(ctx)
+	expectEntries(t, c, "d", "", "e", "")
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len after Clear: got %d, want 0", got)
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	c, advance := newTestLRU(3)
+	ctx := context.Background()
+
+	c.Set(ctx, "short", "1", time.Second)
+	c.Set(ctx, "long", "2", time.Minute)
+
+	advance(time.Second - time.Nanosecond)
+	expectEntries(t, c, "short", "1", "long", "2")
+
+	// Entries expire once their TTL has passed, and are dropped when read
+	advance(time.Nanosecond)
+	expectEntries(t, c, "short", "", "long", "2")
+	if got := c.Len(); got != 1 {
+		t.Errorf("Len after the expired read: got %d, want 1", got)
+	}
+
+	// Setting an entry again restarts its TTL
+	advance(50 * time.Second)
+	c.Set(ctx, "long", "3", time.Minute)
+	advance(30 * time.Second)
+	expectEntries(t, c, "long", "3")
+
+	// Expired entries still count until they are read or evicted
+	c.Set(ctx, "expired", "4", time.Second)
+	advance(time.Minute)
+	if got := c.Len(); got != 2 {
+		t.Errorf("Len with expired entries: got %d, want 2", got)
+	}
+	c.Set(ctx, "a", "5", time.Minute)
+	c.Set(ctx, "b", "6", time.Minute)
+	expectEntries(t, c, "long", "", "expired", "", "a", "5", "b", "6")
+}
+
+func TestLRUNegativeEntries(t *testing.T) {
+	c := NewLRU[*string](2)
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// A nil value is a hit, which callers use to remember that nothing was found
+	c.Set(ctx, "missing", nil, time.Second)
+	if value, ok := c.Get(ctx, "missing"); !ok || value != nil {
+		t.Errorf("Get of a negative entry: got %v, %t, want nil, true", value, ok)
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := c.Get(ctx, "missing"); ok {
+		t.Error("Get of an expired negative entry: got a hit")
+	}
+}
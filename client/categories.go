@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// CreateCategory creates a category, under its parent if one is set.
+func (c *Client) CreateCategory(ctx context.Context, category validator.CreateCategoryPayload) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/category/", body: category}, nil)
+	return err
+}
+
+// ListCategories returns every category.
+func (c *Client) ListCategories(ctx context.Context) ([]types.CategoryDTO, error) {
+	var categories []types.CategoryDTO
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/category/"}, &categories)
+	return categories, err
+}
+
+// GetCategory returns the category with the given ID.
+func (c *Client) GetCategory(ctx context.Context, id string) (*types.CategoryDTO, error) {
+	var category types.CategoryDTO
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/category/" + url.PathEscape(id)}, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ListCategoryLinks returns the links of a category.
+func (c *Client) ListCategoryLinks(ctx context.Context, id string) ([]types.LinkDTO, error) {
+	// The server responds with no content when the category has no links
+	links := []types.LinkDTO{}
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/category/" + url.PathEscape(id) + "/links"}, &links)
+	return links, err
+}
+
+// UpdateCategory replaces the details of a category.
+// If version is set, the update only succeeds while the category is still at that version.
+func (c *Client) UpdateCategory(ctx context.Context, id string, category validator.UpdateCategoryPayload, version *int32) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: "/category/" + url.PathEscape(id), header: ifMatch(version), body: category}, nil)
+	return err
+}
+
+// PatchCategory applies a JSON Merge Patch to a category, replacing the fields it sets.
+// If version is set, the patch only succeeds while the category is still at that version.
+func (c *Client) PatchCategory(ctx context.Context, id string, patch map[string]any, version *int32) error {
+	_, err := c.do(ctx, request{
+		method:      http.MethodPatch,
+		path:        "/category/" + url.PathEscape(id),
+		header:      ifMatch(version),
+		body:        patch,
+		contentType: validator.MergePatchMediaType,
+	}, nil)
+	return err
+}
+
+// DeleteCategory moves a category to the trash.
+// If version is set, the category is only deleted while it is still at that version.
+func (c *Client) DeleteCategory(ctx context.Context, id string, version *int32) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/category/" + url.PathEscape(id), header: ifMatch(version)}, nil)
+	return err
+}
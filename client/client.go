@@ -0,0 +1,269 @@
+// Package client is a Go client for the REST API of Refero.
+//
+// Requests are retried when the server is rate limiting or failing, and errors returned
+// by the API are reported as *Error, which match the sentinel errors of the errors package:
+//
+//	c, err := client.New("https://refero.example.com")
+//	link, err := c.GetLink(ctx, id)
+//	if errors.Is(err, errs.ErrLinkNotFound) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/OmprakashD20/refero-api/utils"
+)
+
+// DefaultPageSize is the number of items requested per page by the iterators.
+const DefaultPageSize = 50
+
+// RetryPolicy controls how failed requests are retried. Retries wait for the duration of the
+// Retry-After header when the server sends one, and back off exponentially otherwise.
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy retries three times, waiting up to 5 seconds between attempts.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	noFollow   *http.Client // Returns redirects instead of following them
+	header     http.Header
+	retry      RetryPolicy
+	pageSize   int32
+
+	// sleep waits between retries, tests replace it to avoid waiting
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Option is a functional option for configuring a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client sending the requests, http.DefaultClient by default.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader sets a header sent with every request, such as the API key the server rate limits by.
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.header.Set(name, value)
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried, DefaultRetryPolicy by default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithPageSize sets the number of items the iterators request per page, DefaultPageSize by default.
+// The size must be positive.
+func WithPageSize(size int32) Option {
+	return func(c *Client) {
+		c.pageSize = size
+	}
+}
+
+// New creates a client for the API served at baseURL, such as "https://refero.example.com".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/v1"
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		header:     make(http.Header),
+		retry:      DefaultRetryPolicy,
+		pageSize:   DefaultPageSize,
+		sleep:      sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.pageSize <= 0 {
+		return nil, fmt.Errorf("invalid page size %d: must be positive", c.pageSize)
+	}
+
+	noFollow := *c.httpClient
+	noFollow.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	c.noFollow = &noFollow
+
+	return c, nil
+}
+
+// request describes a call to the API. Path is relative to the versioned API prefix.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        any
+	contentType string
+	noRedirect  bool
+}
+
+// do sends the request, retrying it according to the retry policy, and decodes the response body
+// into out unless it is nil. Responses with an error status are returned as *Error.
+func (c *Client) do(ctx context.Context, req request, out any) (*http.Response, error) {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("encoding request body: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := c.send(ctx, req, body)
+		if err != nil {
+			if ctx.Err() != nil || !idempotent(req.method) || attempt >= c.retry.MaxRetries {
+				return nil, err
+			}
+			if err := c.sleep(ctx, c.backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if res.StatusCode < http.StatusBadRequest {
+			defer res.Body.Close()
+			if out != nil && res.StatusCode != http.StatusNoContent {
+				if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+					return res, fmt.Errorf("decoding response of %s %s: %w", req.method, req.path, err)
+				}
+			}
+			return res, nil
+		}
+
+		apiErr := decodeError(res)
+		if !retryable(req.method, res.StatusCode) || attempt >= c.retry.MaxRetries {
+			return res, apiErr
+		}
+
+		wait := apiErr.RetryAfter
+		if wait <= 0 {
+			wait = c.backoff(attempt)
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return res, err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	u := c.baseURL.JoinPath(req.path)
+	u.RawQuery = req.query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range c.header {
+		httpReq.Header[name] = values
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		contentType := req.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+
+	if req.noRedirect {
+		return c.noFollow.Do(httpReq)
+	}
+	return c.httpClient.Do(httpReq)
+}
+
+// backoff returns how long to wait before the retry following the given attempt,
+// with full jitter so that clients failing together don't retry together.
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retry.InitialBackoff << attempt
+	if wait <= 0 || wait > c.retry.MaxBackoff {
+		wait = c.retry.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return rand.N(wait) + 1
+}
+
+// retryable reports whether a request failing with the status can be sent again. The server
+// didn't handle requests which were rate limited or arrived while it was unavailable, while
+// other server errors may have happened after a write, so those are only retried for idempotent requests.
+func retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	}
+	return status >= http.StatusInternalServerError && idempotent(method)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ifMatch returns the header making a write conditional on the version, if set.
+func ifMatch(version *int32) http.Header {
+	if version == nil {
+		return nil
+	}
+	return http.Header{"If-Match": {utils.FormatETag(*version)}}
+}
@@ -0,0 +1,380 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/middlewares"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+const linkID = "0b7c1f0e-8a1d-4f3e-9d2a-6c5b4a392817"
+
+// testClient is a client of a test server, which records how long it would have waited between retries.
+type testClient struct {
+	*Client
+	mu    sync.Mutex
+	slept []time.Duration
+}
+
+func (c *testClient) waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.slept
+}
+
+// newTestClient serves the routes under the API prefix, reporting errors the way the API does.
+func newTestClient(t *testing.T, routes func(api *gin.RouterGroup), opts ...Option) *testClient {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middlewares.ErrorHandler())
+	routes(engine.Group("/api/v1"))
+
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tc := &testClient{Client: c}
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		tc.mu.Lock()
+		defer tc.mu.Unlock()
+		tc.slept = append(tc.slept, d)
+		return ctx.Err()
+	}
+	return tc
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "refero.example.com", "ftp://refero.example.com", "http://[::1"} {
+		if _, err := New(baseURL); err == nil {
+			t.Errorf("New(%q) should fail", baseURL)
+		}
+	}
+	for _, size := range []int32{0, -1} {
+		if _, err := New("https://refero.example.com", WithPageSize(size)); err == nil {
+			t.Errorf("New with a page size of %d should fail", size)
+		}
+	}
+
+	c, err := New("https://refero.example.com/prefix/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.baseURL.String(), "https://refero.example.com/prefix/api/v1"; got != want {
+		t.Errorf("got base URL %q, want %q", got, want)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	description := "A link for the tests"
+	link := types.LinkDTO{ID: linkID, Url: "https://example.com", Title: "Example", Description: description, ShortUrl: "abc123", Version: 3}
+
+	var created validator.CreateLinkPayload
+	var ifMatch, contentType string
+	c := newTestClient(t, func(api *gin.RouterGroup) {
+		api.POST("/link/", validator.ValidateBody[validator.CreateLinkPayload](), func(c *gin.Context) {
+			created, _ = validator.GetValidatedData[validator.CreateLinkPayload](c, validator.ValidatedBodyKey)
+			c.JSON(http.StatusCreated, nil)
+		})
+		api.GET("/link/:id", func(c *gin.Context) {
+			if c.Param("id") != linkID {
+				c.Error(errs.NotFound(errs.ErrLinkNotFound))
+				return
+			}
+			c.JSON(http.StatusOK, link)
+		})
+		api.GET("/link/r/:shortUrl", func(c *gin.Context) {
+			c.Redirect(http.StatusMovedPermanently, link.Url)
+		})
+		api.PATCH("/link/:id", func(c *gin.Context) {
+			ifMatch, contentType = c.GetHeader("If-Match"), c.ContentType()
+			c.JSON(http.StatusOK, nil)
+		})
+		api.DELETE("/link/:id", func(c *gin.Context) {
+			ifMatch = c.GetHeader("If-Match")
+			c.Error(errs.PreconditionFailed(errs.ErrPreconditionFailed))
+		})
+	})
+	ctx := context.Background()
+
+	payload := validator.CreateLinkPayload{Title: "Example", URL: "https://example.com", Description: &description}
+	if err := c.CreateLink(ctx, payload); err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+	if created.URL != payload.URL || created.Title != payload.Title {
+		t.Errorf("server received %+v, want %+v", created, payload)
+	}
+
+	got, err := c.GetLink(ctx, linkID)
+	if err != nil {
+		t.Fatalf("GetLink: %v", err)
+	}
+	if *got != link {
+		t.Errorf("GetLink: got %+v, want %+v", got, link)
+	}
+
+	location, err := c.ResolveShortURL(ctx, link.ShortUrl)
+	if err != nil {
+		t.Fatalf("ResolveShortURL: %v", err)
+	}
+	if location != link.Url {
+		t.Errorf("ResolveShortURL: got %q, want %q", location, link.Url)
+	}
+
+	version := int32(3)
+	if err := c.PatchLink(ctx, linkID, map[string]any{"title": "Renamed"}, &version); err != nil {
+		t.Fatalf("PatchLink: %v", err)
+	}
+	if ifMatch != `"3"` || contentType != validator.MergePatchMediaType {
+		t.Errorf("PatchLink sent If-Match %s and Content-Type %s", ifMatch, contentType)
+	}
+
+	err = c.DeleteLink(ctx, linkID, &version)
+	if !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Errorf("DeleteLink: got %v, want %v", err, errs.ErrPreconditionFailed)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c := newTestClient(t, func(api *gin.RouterGroup) {
+		api.POST("/category/", validator.ValidateBody[validator.CreateCategoryPayload](), func(c *gin.Context) {
+			c.JSON(http.StatusCreated, nil)
+		})
+		api.GET("/category/:id", func(c *gin.Context) {
+			c.Error(errs.NotFound(errs.ErrCategoryNotFound))
+		})
+		api.GET("/link/:id", func(c *gin.Context) {
+			// Errors of proxies in front of the API aren't problem details
+			c.String(http.StatusBadGateway, "<html>Bad Gateway</html>")
+		})
+	}, WithRetryPolicy(RetryPolicy{}))
+	ctx := context.Background()
+
+	_, err := c.GetCategory(ctx, linkID)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetCategory: got %v, want an *Error", err)
+	}
+	if apiErr.Status != http.StatusNotFound || apiErr.Code != "category_not_found" || apiErr.Instance != "/api/v1/category/"+linkID {
+		t.Errorf("GetCategory: got %+v", apiErr.Problem)
+	}
+	if !errors.Is(err, errs.ErrCategoryNotFound) || errors.Is(err, errs.ErrLinkNotFound) {
+		t.Errorf("GetCategory: %v should only match %v", err, errs.ErrCategoryNotFound)
+	}
+
+	err = c.CreateCategory(ctx, validator.CreateCategoryPayload{Name: "Go"})
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("CreateCategory: got %v, want a validation error", err)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Field != "name" || apiErr.Errors[0].Constraint != "min" {
+		t.Errorf("CreateCategory: got field errors %+v", apiErr.Errors)
+	}
+
+	_, err = c.GetLink(ctx, linkID)
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadGateway || apiErr.Title != "Bad Gateway" {
+		t.Errorf("GetLink: got %v, want a bad gateway error", err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	// Each route fails the given number of times before succeeding
+	failures := map[string]int{}
+	var mu sync.Mutex
+	failing := func(status int, retryAfter string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if key := c.Request.Method + " " + strings.TrimPrefix(c.FullPath(), "/api/v1"); failures[key] > 0 {
+				failures[key]--
+				if retryAfter != "" {
+					c.Header("Retry-After", retryAfter)
+				}
+				c.Error(&errs.HTTPError{StatusCode: status, ErrorMsg: http.StatusText(status)})
+				return
+			}
+			c.JSON(http.StatusOK, []types.CategoryDTO{})
+		}
+	}
+
+	retryAt := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	c := newTestClient(t, func(api *gin.RouterGroup) {
+		api.GET("/unavailable", failing(http.StatusServiceUnavailable, ""))
+		api.GET("/limited", failing(http.StatusTooManyRequests, "7"))
+		api.GET("/limited-until", failing(http.StatusTooManyRequests, retryAt))
+		api.POST("/failing", failing(http.StatusInternalServerError, ""))
+		api.POST("/limited", failing(http.StatusTooManyRequests, "1"))
+	}, WithRetryPolicy(RetryPolicy{MaxRetries: 2, InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		failures int
+		ok       bool
+		check    func(waits []time.Duration) bool
+	}{
+		{"unavailable backs off", http.MethodGet, "/unavailable", 2, true, func(waits []time.Duration) bool {
+			return len(waits) == 2 && waits[0] <= time.Second && waits[1] <= 2*time.Second
+		}},
+		{"gives up after the last retry", http.MethodGet, "/unavailable", 3, false, func(waits []time.Duration) bool {
+			return len(waits) == 2
+		}},
+		{"rate limited waits for Retry-After", http.MethodGet, "/limited", 1, true, func(waits []time.Duration) bool {
+			return len(waits) == 1 && waits[0] == 7*time.Second
+		}},
+		{"rate limited waits until the Retry-After date", http.MethodGet, "/limited-until", 1, true, func(waits []time.Duration) bool {
+			return len(waits) == 1 && waits[0] > 59*time.Minute && waits[0] <= time.Hour
+		}},
+		{"writes are retried when rate limited", http.MethodPost, "/limited", 1, true, func(waits []time.Duration) bool {
+			return len(waits) == 1 && waits[0] == time.Second
+		}},
+		{"writes aren't retried after server errors", http.MethodPost, "/failing", 1, false, func(waits []time.Duration) bool {
+			return len(waits) == 0
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			failures[tt.method+" "+tt.path] = tt.failures
+			mu.Unlock()
+			before := len(c.waits())
+
+			_, err := c.do(ctx, request{method: tt.method, path: tt.path}, nil)
+			if ok := err == nil; ok != tt.ok {
+				t.Errorf("got error %v, want success %v", err, tt.ok)
+			}
+			if waits := c.waits()[before:]; !tt.check(waits) {
+				t.Errorf("unexpected waits between retries: %v", waits)
+			}
+		})
+	}
+}
+
+func TestRetriesStopWithContext(t *testing.T) {
+	c := newTestClient(t, func(api *gin.RouterGroup) {
+		api.GET("/category/", func(c *gin.Context) {
+			c.Header("Retry-After", "3600")
+			c.Error(errs.ServiceUnavailable(errs.ErrFeedUnavailable))
+		})
+	})
+	// Wait for real, the context ends long before the retry
+	c.sleep = sleep
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.ListCategories(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestLinksIterator(t *testing.T) {
+	all := make([]types.LinkDTO, 7)
+	for i := range all {
+		all[i] = types.LinkDTO{ID: strconv.Itoa(i), Title: "Go " + strconv.Itoa(i)}
+	}
+
+	var mu sync.Mutex
+	var queries []string
+	c := newTestClient(t, func(api *gin.RouterGroup) {
+		api.GET("/link/", validator.ValidateQuery[validator.ListLinksQuery](), func(c *gin.Context) {
+			mu.Lock()
+			queries = append(queries, c.Request.URL.RawQuery)
+			mu.Unlock()
+
+			query, _ := validator.GetValidatedData[validator.ListLinksQuery](c, validator.ValidatedQueryKey)
+			start := min(int(query.Offset), len(all))
+			end := min(start+int(query.Limit), len(all))
+			c.JSON(http.StatusOK, all[start:end])
+		})
+	}, WithPageSize(3))
+	ctx := context.Background()
+
+	var got []string
+	for link, err := range c.Links(ctx, "go") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, link.ID)
+	}
+	if len(got) != len(all) {
+		t.Errorf("got links %v, want all %d", got, len(all))
+	}
+	want := []string{"limit=3&search=go", "limit=3&offset=3&search=go", "limit=3&offset=6&search=go"}
+	if !slices.Equal(queries, want) {
+		t.Errorf("got queries %v, want %v", queries, want)
+	}
+
+	// Stopping early doesn't request the next pages
+	queries = nil
+	for range c.Links(ctx, "") {
+		break
+	}
+	if len(queries) != 1 {
+		t.Errorf("got queries %v, want only the first page", queries)
+	}
+}
+
+func TestListCategoryLinks(t *testing.T) {
+	c := newTestClient(t, func(api *gin.RouterGroup) {
+		api.GET("/category/:id/links", func(c *gin.Context) {
+			c.JSON(http.StatusNoContent, nil)
+		})
+	})
+
+	links, err := c.ListCategoryLinks(context.Background(), linkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if links == nil || len(links) != 0 {
+		t.Errorf("got %v, want no links", links)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	var header http.Header
+	var body []byte
+	c := newTestClient(t, func(api *gin.RouterGroup) {
+		api.PUT("/category/:id", func(c *gin.Context) {
+			header = c.Request.Header.Clone()
+			body, _ = io.ReadAll(c.Request.Body)
+			c.JSON(http.StatusOK, nil)
+		})
+	}, WithHeader("X-API-Key", "secret"))
+
+	category := validator.UpdateCategoryPayload{Name: "Golang"}
+	if err := c.UpdateCategory(context.Background(), linkID, category, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Get("X-API-Key") != "secret" || header.Get("Content-Type") != "application/json" || header.Get("If-Match") != "" {
+		t.Errorf("unexpected request headers %v", header)
+	}
+	var sent validator.UpdateCategoryPayload
+	if err := json.Unmarshal(body, &sent); err != nil || sent.Name != category.Name {
+		t.Errorf("got body %s", body)
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+)
+
+// maxErrorBodyBytes bounds how much of an error response is read.
+const maxErrorBodyBytes = 64 << 10
+
+// Error is a response of the API with an error status, decoded from its problem details.
+// It matches the sentinel errors of the errors package with the same code, so that
+// errors.Is(err, errs.ErrLinkNotFound) reports whether a link was not found.
+type Error struct {
+	errs.Problem
+	// RetryAfter is how long the server asked to wait before retrying, if it did
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	msg := e.Detail
+	if msg == "" {
+		msg = e.Title
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("refero: %d %s (%s)", e.Status, msg, e.Code)
+	}
+	return fmt.Sprintf("refero: %d %s", e.Status, msg)
+}
+
+// Is reports whether the target is a sentinel error with the code of the problem.
+func (e *Error) Is(target error) bool {
+	code, ok := errs.Code(target)
+	return ok && code != "" && code == e.Code
+}
+
+// decodeError reads the problem details of an error response. Responses which aren't
+// problem details, such as those of proxies, are described by their status alone.
+func decodeError(res *http.Response) *Error {
+	defer res.Body.Close()
+
+	apiErr := &Error{RetryAfter: retryAfter(res.Header.Get("Retry-After"))}
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mediaType == errs.ProblemContentType || mediaType == "application/json" {
+		// A malformed body leaves the problem empty, and it is then filled from the status
+		json.NewDecoder(io.LimitReader(res.Body, maxErrorBodyBytes)).Decode(&apiErr.Problem)
+	}
+	// Drain the rest of the body, so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(res.Body, maxErrorBodyBytes))
+
+	apiErr.Status = res.StatusCode
+	if apiErr.Title == "" {
+		apiErr.Title = http.StatusText(res.StatusCode)
+	}
+
+	return apiErr
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as a date.
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// CreateLink saves a link. Creating a link to a URL which is already saved adds the categories to the existing link.
+func (c *Client) CreateLink(ctx context.Context, link validator.CreateLinkPayload) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/link/", body: link}, nil)
+	return err
+}
+
+// ListLinks returns every link.
+func (c *Client) ListLinks(ctx context.Context) ([]types.LinkDTO, error) {
+	var links []types.LinkDTO
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/link/"}, &links)
+	return links, err
+}
+
+// SearchLinks returns a page of the links containing the search term in their title, URL or
+// description, newest first. An empty term matches every link.
+func (c *Client) SearchLinks(ctx context.Context, query validator.ListLinksQuery) ([]types.LinkDTO, error) {
+	values := url.Values{}
+	if query.Search != "" {
+		values.Set("search", query.Search)
+	}
+	if query.Limit > 0 {
+		values.Set("limit", strconv.Itoa(int(query.Limit)))
+	}
+	if query.Offset > 0 {
+		values.Set("offset", strconv.Itoa(int(query.Offset)))
+	}
+
+	var links []types.LinkDTO
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/link/", query: values}, &links)
+	return links, err
+}
+
+// Links iterates over the links containing the search term, newest first, requesting them a page at a time.
+// The iteration stops at the first error. Links created while iterating shift the pages,
+// so a link may be yielded twice.
+func (c *Client) Links(ctx context.Context, search string) iter.Seq2[types.LinkDTO, error] {
+	return func(yield func(types.LinkDTO, error) bool) {
+		query := validator.ListLinksQuery{Search: search, Limit: c.pageSize}
+		for {
+			links, err := c.SearchLinks(ctx, query)
+			if err != nil {
+				yield(types.LinkDTO{}, err)
+				return
+			}
+
+			for _, link := range links {
+				if !yield(link, nil) {
+					return
+				}
+			}
+
+			// A short page is the last one
+			if int32(len(links)) < query.Limit {
+				return
+			}
+			query.Offset += query.Limit
+		}
+	}
+}
+
+// GetLink returns the link with the given ID.
+func (c *Client) GetLink(ctx context.Context, id string) (*types.LinkDTO, error) {
+	var link types.LinkDTO
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/link/" + url.PathEscape(id)}, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ResolveShortURL returns the URL the short URL redirects to, without following the redirect.
+func (c *Client) ResolveShortURL(ctx context.Context, shortURL string) (string, error) {
+	res, err := c.do(ctx, request{method: http.MethodGet, path: "/link/r/" + url.PathEscape(shortURL), noRedirect: true}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	location := res.Header.Get("Location")
+	if location == "" {
+		return "", errors.New("refero: short URL response has no Location header")
+	}
+	return location, nil
+}
+
+// UpdateLink replaces the details and categories of a link.
+// If version is set, the update only succeeds while the link is still at that version.
+func (c *Client) UpdateLink(ctx context.Context, id string, link validator.UpdateLinkPayload, version *int32) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: "/link/" + url.PathEscape(id), header: ifMatch(version), body: link}, nil)
+	return err
+}
+
+// PatchLink applies a JSON Merge Patch to a link, replacing the fields it sets.
+// If version is set, the patch only succeeds while the link is still at that version.
+func (c *Client) PatchLink(ctx context.Context, id string, patch map[string]any, version *int32) error {
+	_, err := c.do(ctx, request{
+		method:      http.MethodPatch,
+		path:        "/link/" + url.PathEscape(id),
+		header:      ifMatch(version),
+		body:        patch,
+		contentType: validator.MergePatchMediaType,
+	}, nil)
+	return err
+}
+
+// DeleteLink moves a link to the trash.
+// If version is set, the link is only deleted while it is still at that version.
+func (c *Client) DeleteLink(ctx context.Context, id string, version *int32) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/link/" + url.PathEscape(id), header: ifMatch(version)}, nil)
+	return err
+}
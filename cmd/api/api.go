@@ -1,54 +1,155 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/OmprakashD20/refero-api/cache"
+	"github.com/OmprakashD20/refero-api/config"
 	"github.com/OmprakashD20/refero-api/database"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/metrics"
 	"github.com/OmprakashD20/refero-api/middlewares"
+	"github.com/OmprakashD20/refero-api/openapi"
+	"github.com/OmprakashD20/refero-api/ratelimit"
+	"github.com/OmprakashD20/refero-api/repository"
 	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/services/feed"
+	"github.com/OmprakashD20/refero-api/services/graph"
+	"github.com/OmprakashD20/refero-api/services/health"
 	"github.com/OmprakashD20/refero-api/services/links"
+	"github.com/OmprakashD20/refero-api/services/rpc"
+	"github.com/OmprakashD20/refero-api/services/trash"
+	"github.com/OmprakashD20/refero-api/services/webhooks"
+	"github.com/OmprakashD20/refero-api/types"
 )
 
+// healthCheckTimeout bounds how long /readyz waits for all checks.
+const healthCheckTimeout = 3 * time.Second
+
 type APIServer struct {
-	port string
-	conn *pgxpool.Pool
+	cfg     *config.Config
+	conn    *pgxpool.Pool
+	replica *pgxpool.Pool // Optional, read-only queries fall back to conn
+}
+
+func NewAPIServer(cfg *config.Config, conn, replica *pgxpool.Pool) *APIServer {
+	return &APIServer{cfg, conn, replica}
 }
 
-func NewAPIServer(port string, conn *pgxpool.Pool) *APIServer {
-	return &APIServer{port, conn}
+// application is the HTTP handler of the API along with the background work it depends on.
+type application struct {
+	engine *gin.Engine
+	spec   *openapi.Spec
+	// workers run until their context is cancelled, which only happens once the HTTP server has drained
+	workers []func(ctx context.Context)
+	// onShutdown is called as soon as the shutdown starts
+	onShutdown []func()
 }
 
-func (s *APIServer) Run() error {
+// Run serves the API until the context is cancelled, then drains in-flight requests
+// and stops the background workers before returning.
+func (s *APIServer) Run(ctx context.Context) error {
+	app, err := s.setup()
+	if err != nil {
+		return err
+	}
+
+	// Background workers outlive individual requests, so they get their own context
+	// which is only cancelled once the HTTP server has drained.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	for _, run := range app.workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
+	}
+
+	serverCfg := s.cfg.Server
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", s.cfg.Port),
+		Handler:           app.engine.Handler(),
+		ReadTimeout:       time.Duration(serverCfg.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(serverCfg.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(serverCfg.WriteTimeout),
+		IdleTimeout:       time.Duration(serverCfg.IdleTimeout),
+		MaxHeaderBytes:    int(serverCfg.MaxHeaderBytes),
+	}
+	for _, f := range app.onShutdown {
+		server.RegisterOnShutdown(f)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server is running", "port", s.cfg.Port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
+		// The server failed to start or stopped on its own
+	case <-ctx.Done():
+		slog.Info("shutting down, draining requests", "timeout", serverCfg.ShutdownTimeout)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(serverCfg.ShutdownTimeout))
+		defer cancel()
+
+		if err = server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("failed to drain requests", "error", err)
+			server.Close()
+		}
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	stopWorkers()
+	workers.Wait()
+
+	slog.Info("server stopped")
+
+	return err
+}
+
+// setup registers the middlewares and routes, and wires the services along with their background workers.
+func (s *APIServer) setup() (*application, error) {
 	gin.SetMode(gin.ReleaseMode)
 
 	app := gin.New()
 	app.RedirectTrailingSlash = false
+	// Only proxies in front of the API may set the client IP, which rate limits are keyed on
+	if err := app.SetTrustedProxies(s.cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	// gRPC clients speak HTTP/2, which needs h2c without TLS
+	app.UseH2C = s.cfg.RPC.Enabled
 
-	app.Use(middlewares.CORS())
-
-	app.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("[GIN] [%s] | [%s] %d | %s | %s\n",
-			param.TimeStamp.Format("2006-01-02 15:04:05"),
-			param.Method,
-			param.StatusCode,
-			param.ClientIP,
-			param.Path,
-		)
-	}))
+	app.Use(middlewares.RequestID())
+	app.Use(middlewares.Tracing())
+	app.Use(middlewares.Metrics())
+	app.Use(middlewares.Logger())
+	app.Use(middlewares.CORS(s.corsPolicy()))
 
 	app.Use(middlewares.RecoveryMiddleware())
 	app.Use(middlewares.ErrorHandler())
 
 	// Handle 404 API routes
 	app.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "route not found",
-		})
+		c.Error(errs.NotFound(errs.ErrRouteNotFound))
 	})
 
 	app.GET("/", func(c *gin.Context) {
@@ -57,32 +158,220 @@ func (s *APIServer) Run() error {
 		})
 	})
 
+	// Routes are described where they are mounted, so the document only lists the routes being served
+	spec := openapi.New(openapi.Info{Title: "Refero API", Version: "v1", Description: "Save, categorize and shorten links."})
+	spec.Describe(app, "meta", rootDocs...)
+	app.GET("/openapi.json", spec.Handler(app.Routes))
+	app.GET("/docs/*filepath", openapi.UI("/openapi.json"))
+
+	// Transaction Store
+	txnStore := database.NewTransactionStore(s.conn)
+
+	// Purge expired items from the trash
+	purgeJob := trash.NewPurgeJob(
+		trash.NewStore(s.conn), txnStore,
+		time.Duration(s.cfg.Trash.Retention), time.Duration(s.cfg.Trash.PurgeInterval),
+	)
+	workers := []func(context.Context){purgeJob.Run}
+
+	// Health Routes
+	migrator, err := database.NewMigrator(s.conn)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := health.NewRegistry(healthCheckTimeout)
+	checks.Register("database", health.DatabaseChecker(s.conn))
+	if s.replica != nil {
+		checks.Register("replica", health.DatabaseChecker(s.replica))
+	}
+	checks.Register("migrations", health.MigrationsChecker(migrator))
+	checks.Register("trash_purge", purgeJob)
+
+	// Mutations on any instance are announced on the events channel
+	listener := events.NewListener(s.conn.Config().ConnConfig.Copy())
+	checks.Register("events", listener)
+	workers = append(workers, listener.Run)
+
+	// Relay the events to the clients of the change feed
+	feedStore := feed.NewStore(s.conn)
+	broker := feed.NewBroker(feedStore, s.cfg.Events.BufferSize, time.Duration(s.cfg.Events.Retention))
+	for _, eventType := range events.Types {
+		listener.Subscribe(eventType, broker.Handle)
+	}
+	listener.OnReconnect(broker.Resync)
+	workers = append(workers, broker.Run)
+	// Streams never drain on their own, so they are ended as soon as the shutdown starts
+	onShutdown := []func(){broker.Close}
+
+	// Deliver webhooks queued by the events
+	webhookStore := webhooks.NewStore(s.conn)
+	var dispatcher *webhooks.Dispatcher
+	if hooks := s.cfg.Webhooks; hooks.Enabled {
+		dispatcher = webhooks.NewDispatcher(webhookStore, webhooks.NewHTTPClient(time.Duration(hooks.Timeout), hooks.AllowPrivateNetworks), webhooks.Options{
+			Workers:        hooks.Workers,
+			Timeout:        time.Duration(hooks.Timeout),
+			PollInterval:   time.Duration(hooks.PollInterval),
+			MaxAttempts:    hooks.MaxAttempts,
+			InitialBackoff: time.Duration(hooks.InitialBackoff),
+			MaxBackoff:     time.Duration(hooks.MaxBackoff),
+			DisableAfter:   hooks.DisableAfter,
+			LogRetention:   time.Duration(hooks.LogRetention),
+		})
+		for _, eventType := range events.Types {
+			listener.Subscribe(eventType, dispatcher.Wake)
+		}
+		workers = append(workers, dispatcher.Run)
+	}
+
+	healthService := health.NewService(checks)
+	healthService.SetupHealthRoutes(app)
+	spec.Describe(app, "health", health.Docs...)
+
+	// Metrics
+	if err := metrics.RegisterPool("primary", s.conn); err != nil {
+		return nil, err
+	}
+	if s.replica != nil {
+		if err := metrics.RegisterPool("replica", s.replica); err != nil {
+			return nil, err
+		}
+	}
+	app.GET("/metrics", gin.WrapH(metrics.Handler()))
+	spec.Describe(app, "meta", metricsDocs...)
+
 	api := app.Group("/api/v1")
+	if s.cfg.RateLimit.Enabled {
+		api.Use(middlewares.RateLimit(ratelimit.NewMemoryStore(), s.rateLimitRules(), s.cfg.RateLimit.APIKeyHeader, s.cfg.RateLimit.APIKeys))
+	}
 	{
 		api.GET("/", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
 				"message": "you hit the v1 API route of Refero",
 			})
 		})
-		// Transaction Store
-		txnStore := database.NewTransactionStore(s.conn)
+		spec.Describe(api, "meta", versionDocs...)
 
 		// Category Routes
-		categoryStore := category.NewStore(s.conn)
+		categoryStore := category.NewStore(s.conn).WithReplica(s.replica)
 		categoryService := category.NewService(categoryStore)
-		categoryService.SetupCategoryRoutes(api.Group("/category"))
+		categoryRoutes := api.Group("/category")
+		categoryService.SetupCategoryRoutes(categoryRoutes)
+		spec.Describe(categoryRoutes, "categories", category.Docs...)
 
 		// Link Routes
-		linkStore := links.NewStore(s.conn)
+		var linkStore types.LinkStore = links.NewStore(s.conn).WithReplica(s.replica)
+		if cacheCfg := s.cfg.Cache; cacheCfg.Enabled {
+			shortURLCache := cache.NewLRU[*types.LinkDTO](cacheCfg.Size)
+			cachedStore := links.NewCachedStore(linkStore, repository.New(s.conn), shortURLCache, time.Duration(cacheCfg.TTL), time.Duration(cacheCfg.NegativeTTL))
+			for _, eventType := range []string{events.LinkCreated, events.LinkUpdated, events.LinkDeleted} {
+				listener.Subscribe(eventType, cachedStore.HandleLinkEvent)
+			}
+			listener.OnReconnect(cachedStore.Reset)
+			linkStore = cachedStore
+		}
 		LinkService := links.NewService(linkStore, txnStore)
-		LinkService.SetupLinkRoutes(api.Group("/link"))
+		linkRoutes := api.Group("/link")
+		LinkService.SetupLinkRoutes(linkRoutes)
+		spec.Describe(linkRoutes, "links", links.Docs...)
+
+		// Trash Routes
+		trashStore := trash.NewStore(s.conn).WithReplica(s.replica)
+		trashService := trash.NewService(trashStore)
+		trashRoutes := api.Group("/trash")
+		trashService.SetupTrashRoutes(trashRoutes)
+		spec.Describe(trashRoutes, "trash", trash.Docs...)
+
+		// Webhook Routes
+		webhookService := webhooks.NewService(webhookStore)
+		webhookRoutes := api.Group("/webhooks")
+		webhookService.SetupWebhookRoutes(webhookRoutes)
+		spec.Describe(webhookRoutes, "webhooks", webhooks.Docs...)
+
+		// Change Feed Routes
+		feedService := feed.NewService(broker, feedStore, time.Duration(s.cfg.Events.Heartbeat), s.websocketOrigins())
+		feedRoutes := api.Group("/events")
+		feedService.SetupFeedRoutes(feedRoutes, s.cfg.Events.WebSocket)
+		spec.Describe(feedRoutes, "events", feed.Docs...)
+
+		// GraphQL Routes
+		if gql := s.cfg.GraphQL; gql.Enabled {
+			graphService := graph.NewService(LinkService, categoryService, linkStore, categoryStore, graph.Options{
+				MaxDepth:      gql.MaxDepth,
+				MaxComplexity: gql.MaxComplexity,
+				Introspection: gql.Introspection,
+			})
+			graphRoutes := api.Group("/graphql")
+			graphService.SetupGraphQLRoutes(graphRoutes)
+			spec.Describe(graphRoutes, "graphql", graph.Docs...)
+		}
+
+		// RPC Routes, outside of the versioned REST prefix
+		if s.cfg.RPC.Enabled {
+			rpcService := rpc.NewService(
+				rpc.NewLinkServer(LinkService, linkStore),
+				rpc.NewCategoryServer(categoryService, categoryStore),
+				rpc.ParseTokens(s.cfg.RPC.Tokens),
+			)
+			rpcService.SetupRPCRoutes(app)
+			spec.Describe(app, "rpc", rpc.Docs...)
+		}
 	}
 
 	for _, r := range app.Routes() {
-		fmt.Printf("[%s]: %s\n", r.Method, r.Path)
+		slog.Debug("route registered", "method", r.Method, "path", r.Path)
+	}
+
+	return &application{app, spec, workers, onShutdown}, nil
+}
+
+// rateLimitRules maps the route groups to their rate limit policies, first match wins.
+func (s *APIServer) rateLimitRules() []ratelimit.Rule {
+	policy := func(name string, cfg config.RateLimitPolicy) ratelimit.Policy {
+		return ratelimit.Policy{Name: name, Requests: cfg.Requests, Period: time.Duration(cfg.Period), Burst: cfg.Burst}
 	}
 
-	log.Printf("Server is running on PORT %s", s.port)
+	limits := s.cfg.RateLimit
+	return []ratelimit.Rule{
+		{Prefix: "/api/v1/link/r/", Policy: policy("redirect", limits.Redirect)},
+		{Prefix: "/api/v1/", Methods: []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, Policy: policy("write", limits.Write)},
+		{Prefix: "/api/v1/", Policy: policy("read", limits.Read)},
+	}
+}
 
-	return app.Run(fmt.Sprintf(":%s", s.port))
+// corsPolicy builds the default CORS policy along with the per-route overrides.
+func (s *APIServer) corsPolicy() (middlewares.CORSPolicy, []middlewares.CORSRoute) {
+	policy := func(cfg config.CORSPolicy) middlewares.CORSPolicy {
+		return middlewares.CORSPolicy{
+			AllowedOrigins:   cfg.AllowedOrigins,
+			AllowedMethods:   cfg.AllowedMethods,
+			AllowedHeaders:   cfg.AllowedHeaders,
+			ExposedHeaders:   cfg.ExposedHeaders,
+			AllowCredentials: cfg.AllowCredentials,
+			MaxAge:           time.Duration(cfg.MaxAge),
+		}
+	}
+
+	cors := s.cfg.CORS
+	routes := make([]middlewares.CORSRoute, len(cors.Overrides))
+	for i, override := range cors.Overrides {
+		routes[i] = middlewares.CORSRoute{Prefix: override.Prefix, Policy: policy(override.Policy(cors.CORSPolicy))}
+	}
+
+	return policy(cors.CORSPolicy), routes
+}
+
+// websocketOrigins converts the origins allowed by the CORS policy into the host patterns
+// checked by the WebSocket handshake, which isn't subject to CORS.
+func (s *APIServer) websocketOrigins() []string {
+	origins := s.cfg.CORS.AllowedOrigins
+	patterns := make([]string, len(origins))
+	for i, origin := range origins {
+		_, host, found := strings.Cut(origin, "://")
+		if !found {
+			host = origin
+		}
+		patterns[i] = host
+	}
+	return patterns
 }
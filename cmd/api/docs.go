@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/OmprakashD20/refero-api/openapi"
+)
+
+// greeting is the body of the index routes.
+type greeting struct {
+	Message string `json:"message"`
+}
+
+// rootDocs describes the routes registered on the engine by the server itself.
+var rootDocs = []openapi.Route{
+	{
+		Method:      http.MethodGet,
+		Path:        "/",
+		OperationID: "getIndex",
+		Summary:     "Greet the client",
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: greeting{}}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/openapi.json",
+		OperationID: "getOpenAPIDocument",
+		Summary:     "Get this document",
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: &openapi.Schema{Type: "object"}}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/docs/*filepath",
+		OperationID: "getDocs",
+		Summary:     "Browse this document with Swagger UI",
+		Description: "The UI is served at /docs/.",
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: openapi.Content{"text/html": &openapi.Schema{Type: "string"}}}},
+	},
+}
+
+var metricsDocs = []openapi.Route{{
+	Method:      http.MethodGet,
+	Path:        "/metrics",
+	OperationID: "getMetrics",
+	Summary:     "Get the Prometheus metrics of the server",
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: openapi.Content{"text/plain": &openapi.Schema{Type: "string"}}}},
+}}
+
+var versionDocs = []openapi.Route{{
+	Method:      http.MethodGet,
+	Path:        "/",
+	OperationID: "getAPIIndex",
+	Summary:     "Greet the client of the v1 API",
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: greeting{}}},
+}}
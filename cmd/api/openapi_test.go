@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/config"
+	"github.com/OmprakashD20/refero-api/openapi"
+)
+
+// testApp registers every route, including the optional ones, without connecting to a database.
+// The metrics of the pools are registered globally, so it is only set up once.
+var testApp = sync.OnceValues(func() (*application, error) {
+	cfg := config.Default()
+	cfg.GraphQL.Enabled = true
+	cfg.Events.WebSocket = true
+	cfg.Webhooks.Enabled = true
+	cfg.RPC.Enabled = true
+	cfg.RPC.Tokens = []string{"test:secret"}
+
+	// Pools connect lazily, and nothing is queried while the routes are registered
+	conn, err := pgxpool.New(context.Background(), "postgres://localhost:1/refero")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAPIServer(&cfg, conn, nil).setup()
+})
+
+func setupTestApp(t *testing.T) *application {
+	t.Helper()
+
+	app, err := testApp()
+	if err != nil {
+		t.Fatalf("setting up the routes: %v", err)
+	}
+	return app
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	app := setupTestApp(t)
+
+	for _, route := range app.spec.Missing(app.engine.Routes()) {
+		t.Errorf("%s is not described in the OpenAPI document, add it to the Docs of its service", route)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	app := setupTestApp(t)
+
+	res := httptest.NewRecorder()
+	app.engine.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if res.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json: got status %d", res.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(res.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decoding the document: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("got openapi %q, want %q", doc.OpenAPI, openapi.Version)
+	}
+
+	operations := 0
+	for _, item := range doc.Paths {
+		operations += len(item)
+	}
+	if routes := len(app.engine.Routes()); operations != routes {
+		t.Errorf("the document has %d operations for %d routes", operations, routes)
+	}
+
+	// Binding constraints are part of the schemas
+	payload := doc.Components.Schemas["LinkPayload"]
+	if payload == nil {
+		t.Fatal("LinkPayload schema is missing")
+	}
+	if title := payload.Properties["title"]; title == nil || title.MinLength == nil || *title.MinLength != 4 {
+		t.Errorf("title of LinkPayload should have a minimum length of 4, got %+v", title)
+	}
+	if url := payload.Properties["url"]; url == nil || url.Format != "uri" {
+		t.Errorf("url of LinkPayload should be formatted as an uri, got %+v", url)
+	}
+	if ids := payload.Properties["categoryIds"]; ids == nil || ids.Items == nil || ids.Items.Format != "uuid" {
+		t.Errorf("categoryIds of LinkPayload should be a list of uuids, got %+v", ids)
+	}
+	if !strings.Contains(strings.Join(payload.Required, ","), "title") {
+		t.Errorf("title of LinkPayload should be required, got %v", payload.Required)
+	}
+}
+
+func TestDocsUI(t *testing.T) {
+	app := setupTestApp(t)
+
+	for path, want := range map[string]string{
+		"/docs/":                       "swagger-ui",
+		"/docs/swagger-initializer.js": `"/openapi.json"`,
+	} {
+		res := httptest.NewRecorder()
+		app.engine.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if res.Code != http.StatusOK {
+			t.Errorf("GET %s: got status %d", path, res.Code)
+		} else if !strings.Contains(res.Body.String(), want) {
+			t.Errorf("GET %s: body doesn't contain %s", path, want)
+		}
+	}
+}
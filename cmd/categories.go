@@ -0,0 +1,92 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/config"
+	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+const categoriesUsage = `usage: categories <command>
+
+commands:
+  tree [-o table|json]   print the category hierarchy`
+
+var errCategoriesUsage = errors.New(categoriesUsage)
+
+// categoryNode is a category along with its subcategories.
+type categoryNode struct {
+	types.CategoryDTO
+	Children []*categoryNode `json:"children"`
+}
+
+func runCategories(ctx context.Context, cfg *config.Config, conn *pgxpool.Pool, args []string) error {
+	if len(args) == 0 || args[0] != "tree" {
+		return errCategoriesUsage
+	}
+
+	fs := flag.NewFlagSet("categories tree", flag.ContinueOnError)
+	format := addFormatFlag(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	categories, err := category.NewStore(conn).GetAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+
+	tree := buildCategoryTree(categories)
+
+	return printOutput(*format, tree, func(w io.Writer) {
+		printCategoryTree(w, tree, 0)
+	})
+}
+
+// buildCategoryTree nests the categories under their parents, sorted by name.
+// Categories whose parent is missing are treated as roots.
+func buildCategoryTree(categories []types.CategoryDTO) []*categoryNode {
+	nodes := make(map[string]*categoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &categoryNode{CategoryDTO: c, Children: []*categoryNode{}}
+	}
+
+	roots := []*categoryNode{}
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if c.ParentID != nil {
+			if parent, ok := nodes[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	byName := func(a, b *categoryNode) int {
+		return cmp.Compare(a.Name, b.Name)
+	}
+	for _, node := range nodes {
+		slices.SortFunc(node.Children, byName)
+	}
+	slices.SortFunc(roots, byName)
+
+	return roots
+}
+
+func printCategoryTree(w io.Writer, nodes []*categoryNode, depth int) {
+	for _, node := range nodes {
+		fmt.Fprintf(w, "%s%s\t%s\n", strings.Repeat("  ", depth), node.Name, node.ID)
+		printCategoryTree(w, node.Children, depth+1)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/config"
+	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/services/links"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+func runExport(ctx context.Context, cfg *config.Config, conn *pgxpool.Pool, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	file := fs.String("file", "", "write the export to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	linkStore := links.NewStore(conn)
+
+	categories, err := category.NewStore(conn).GetAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+
+	all, err := linkStore.GetAllLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	export := types.ExportDTO{
+		Categories: categories,
+		Links:      make([]types.LinkExportDTO, len(all)),
+	}
+	if export.Categories == nil {
+		export.Categories = []types.CategoryDTO{}
+	}
+
+	for i, link := range all {
+		categoryIDs, err := linkStore.GetCategoriesForLink(ctx, link.ID, nil)
+		if err != nil {
+			return fmt.Errorf("link %s: %w", link.ID, err)
+		}
+		export.Links[i] = types.LinkExportDTO{LinkDTO: link, CategoryIDs: categoryIDs}
+	}
+
+	out := os.Stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(export)
+}
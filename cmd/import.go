@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/config"
+	"github.com/OmprakashD20/refero-api/database"
+	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/services/links"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+var errImportUsage = errors.New("usage: import FILE (use - to read from stdin)")
+
+// runImport loads an export file. Categories are matched by name and links by URL,
+// so importing the same file twice does not create duplicates.
+func runImport(ctx context.Context, cfg *config.Config, conn *pgxpool.Pool, args []string) error {
+	if len(args) != 1 {
+		return errImportUsage
+	}
+
+	var in io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var export types.ExportDTO
+	if err := json.NewDecoder(in).Decode(&export); err != nil {
+		return fmt.Errorf("decode %s: %w", args[0], err)
+	}
+
+	categoryIDs, err := importCategories(ctx, category.NewStore(conn), export.Categories)
+	if err != nil {
+		return err
+	}
+
+	service := links.NewService(links.NewStore(conn), database.NewTransactionStore(conn))
+	for _, link := range export.Links {
+		description := link.Description
+		payload := validator.CreateLinkPayload{
+			Title:       link.Title,
+			URL:         link.Url,
+			Description: &description,
+		}
+		for _, id := range link.CategoryIDs {
+			if newID, ok := categoryIDs[id]; ok {
+				payload.CategoryIDs = append(payload.CategoryIDs, newID)
+			}
+		}
+
+		if err := validatePayload(&payload); err != nil {
+			return fmt.Errorf("link %s: %w", link.Url, err)
+		}
+
+		// Keep the short URL so that shared redirects keep working
+		if _, err := service.CreateLink(ctx, payload, link.ShortUrl); err != nil {
+			return fmt.Errorf("link %s: %w", link.Url, err)
+		}
+	}
+
+	fmt.Printf("Imported %d categories and %d links\n", len(export.Categories), len(export.Links))
+	return nil
+}
+
+// importCategories creates the missing categories, parents before their subcategories.
+// It returns the ID each exported category has in this database.
+func importCategories(ctx context.Context, store types.CategoryStore, categories []types.CategoryDTO) (map[string]string, error) {
+	byID := make(map[string]types.CategoryDTO, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	ids := make(map[string]string, len(categories))
+	visiting := make(map[string]bool)
+
+	var importCategory func(id string) (string, error)
+	importCategory = func(id string) (string, error) {
+		if newID, ok := ids[id]; ok {
+			return newID, nil
+		}
+		if visiting[id] {
+			return "", fmt.Errorf("category %s is its own ancestor", id)
+		}
+		visiting[id] = true
+
+		c := byID[id]
+		payload := validator.CreateCategoryPayload{Name: c.Name, Description: c.Description}
+		if c.ParentID != nil {
+			if _, ok := byID[*c.ParentID]; ok {
+				parentID, err := importCategory(*c.ParentID)
+				if err != nil {
+					return "", err
+				}
+				payload.ParentId = parentID
+			}
+		}
+
+		existing, err := store.GetCategoryByName(ctx, c.Name)
+		if err != nil {
+			return "", err
+		}
+
+		if existing == nil {
+			if err := validatePayload(&payload); err != nil {
+				return "", fmt.Errorf("category %s: %w", c.Name, err)
+			}
+			if err := store.CreateCategory(ctx, payload); err != nil {
+				return "", fmt.Errorf("category %s: %w", c.Name, err)
+			}
+			if existing, err = store.GetCategoryByName(ctx, c.Name); err != nil {
+				return "", err
+			}
+		}
+
+		ids[id] = existing.ID
+		return existing.ID, nil
+	}
+
+	for _, c := range categories {
+		if _, err := importCategory(c.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
+}
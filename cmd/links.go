@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/config"
+	"github.com/OmprakashD20/refero-api/database"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/services/links"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+const linksUsage = `usage: links <command>
+
+commands:
+  add -title T -url U -description D [-category ID]... [-o table|json]   create a link
+  list [-o table|json]                                                   list all links
+  rm ID                                                                  move a link to the trash`
+
+var errLinksUsage = errors.New(linksUsage)
+
+func runLinks(ctx context.Context, cfg *config.Config, conn *pgxpool.Pool, args []string) error {
+	if len(args) == 0 {
+		return errLinksUsage
+	}
+
+	store := links.NewStore(conn)
+
+	switch args[0] {
+	case "add":
+		fs := flag.NewFlagSet("links add", flag.ContinueOnError)
+		title := fs.String("title", "", "title of the link")
+		url := fs.String("url", "", "URL of the link")
+		description := fs.String("description", "", "description of the link")
+		var categoryIDs stringList
+		fs.Var(&categoryIDs, "category", "ID of a category to add the link to (repeatable)")
+		format := addFormatFlag(fs)
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		payload := validator.CreateLinkPayload{
+			Title:       *title,
+			URL:         *url,
+			Description: description,
+			CategoryIDs: categoryIDs,
+		}
+		if err := validatePayload(&payload); err != nil {
+			return err
+		}
+
+		service := links.NewService(store, database.NewTransactionStore(conn))
+		linkID, err := service.CreateLink(ctx, payload, "")
+		if err != nil {
+			return err
+		}
+
+		link, err := store.GetLinkByID(ctx, *linkID)
+		if err != nil {
+			return err
+		}
+
+		return printOutput(*format, link, func(w io.Writer) {
+			printLinks(w, []types.LinkDTO{*link})
+		})
+	case "list":
+		fs := flag.NewFlagSet("links list", flag.ContinueOnError)
+		format := addFormatFlag(fs)
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		all, err := store.GetAllLinks(ctx)
+		if err != nil {
+			return err
+		}
+		if all == nil {
+			all = []types.LinkDTO{}
+		}
+
+		return printOutput(*format, all, func(w io.Writer) {
+			printLinks(w, all)
+		})
+	case "rm":
+		if len(args) != 2 {
+			return errLinksUsage
+		}
+
+		if err := store.DeleteLinkByID(ctx, args[1], nil, nil); err != nil {
+			if errors.Is(err, errs.ErrLinkNotFound) {
+				return fmt.Errorf("%w: %s", errs.ErrLinkNotFound, args[1])
+			}
+			return err
+		}
+
+		fmt.Printf("Moved link %s to the trash\n", args[1])
+		return nil
+	}
+
+	return errLinksUsage
+}
+
+func printLinks(w io.Writer, all []types.LinkDTO) {
+	fmt.Fprintln(w, "ID\tSHORT URL\tTITLE\tURL")
+	for _, link := range all {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", link.ID, link.ShortUrl, link.Title, link.Url)
+	}
+}
@@ -2,32 +2,102 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
 	"time"
 
-	"github.com/OmprakashD20/refero-api/cmd/api"
+	"github.com/jackc/pgx/v5/pgxpool"
+
 	"github.com/OmprakashD20/refero-api/config"
 	"github.com/OmprakashD20/refero-api/database"
+	"github.com/OmprakashD20/refero-api/logging"
+	"github.com/OmprakashD20/refero-api/tracing"
 )
 
+const usage = `usage: refero <command> [arguments]
+
+commands:
+  serve                       start the API server (default)
+  migrate <command>           manage the database schema
+  import FILE                 import links and categories from an export file
+  export [-file FILE]         export all links and categories as JSON
+  links add|list|rm           manage links
+  categories tree             print the category hierarchy
+  shortcode resolve CODE      print the link behind a short code
+
+Listing commands accept -o table|json to select the output format.
+
+Configuration is read from the file named by CONFIG_FILE (YAML or TOML),
+then overridden by environment variables, a .env file and DATABASE_URL.`
+
+type command func(ctx context.Context, cfg *config.Config, conn *pgxpool.Pool, args []string) error
+
+var commands = map[string]command{
+	"serve":      runServe,
+	"migrate":    runMigrate,
+	"import":     runImport,
+	"export":     runExport,
+	"links":      runLinks,
+	"categories": runCategories,
+	"shortcode":  runShortcode,
+}
+
 func main() {
-	dbCtx, dbCancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer dbCancel()
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	run, ok := commands[name]
+	if !ok {
+		fmt.Fprintln(os.Stderr, usage)
+		if name == "help" || name == "-h" || name == "--help" {
+			return
+		}
+		os.Exit(2)
+	}
 
-	// Init Database Connection
-	conn, err := database.InitDB(dbCtx, &config.Envs.DB)
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
 	if err != nil {
-		log.Fatalf("Failed connecting the database: %v", err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
-	defer conn.Close()
+	// Logs go to stderr, so they never mix with the output of the CLI commands
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log.Format, cfg.Log.Level))
 
-	log.Println("Connected to the database successfully")
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("failed setting up tracing", err)
+	}
+	defer func() {
+		// Flush the remaining spans, without holding up the exit for long
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 
-	// Run the server
-	server := api.NewAPIServer(config.Envs.Port, conn)
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("failed flushing traces", "error", err)
+		}
+	}()
 
-	if err := server.Run(); err != nil {
-		log.Fatalf("Failed to run the server: %v", err)
+	// Init Database Connection, retrying until the database is up
+	conn, err := database.InitDB(context.Background(), &cfg.DB)
+	if err != nil {
+		fatal("failed connecting the database", err)
 	}
+
+	defer conn.Close()
+
+	slog.Info("connected to the database")
+
+	if err := run(context.Background(), cfg, conn, args); err != nil {
+		conn.Close()
+		fatal("failed to run "+name, err)
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
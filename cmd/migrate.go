@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/config"
+	"github.com/OmprakashD20/refero-api/database"
+)
+
+const migrateUsage = `usage: migrate <command>
+
+commands:
+  up              apply all pending migrations
+  down [N]        revert the last N applied migrations (default 1)
+  status          list migrations and whether they are applied
+  goto VERSION    migrate up or down to the given version
+  force VERSION   record migrations up to VERSION as applied without running them`
+
+var errMigrateUsage = errors.New(migrateUsage)
+
+func runMigrate(ctx context.Context, cfg *config.Config, conn *pgxpool.Pool, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	migrator, err := database.NewMigrator(conn)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s), now at version %d\n", applied, migrator.Latest())
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errMigrateUsage
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %d migration(s)\n", reverted)
+	case "goto", "force":
+		if len(args) < 2 {
+			return errMigrateUsage
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errMigrateUsage
+		}
+
+		if args[0] == "force" {
+			if err := migrator.Force(ctx, version); err != nil {
+				return err
+			}
+			fmt.Printf("Forced version %d\n", version)
+			return nil
+		}
+
+		changed, err := migrator.Goto(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Migrated %d migration(s), now at version %d\n", changed, version)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+	default:
+		return errMigrateUsage
+	}
+
+	return nil
+}
+
+func printMigrationStatus(statuses []database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin/binding"
+
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// addFormatFlag registers the -o flag that selects between table and JSON output.
+func addFormatFlag(fs *flag.FlagSet) *string {
+	return fs.String("o", "table", "output format: table or json")
+}
+
+// printOutput writes v as indented JSON, or renders it as a table with the given function.
+func printOutput(format string, v any, table func(w io.Writer)) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// validatePayload checks a payload against its binding rules and reports every failing field.
+func validatePayload(payload any) error {
+	err := binding.Validator.ValidateStruct(payload)
+	if err == nil {
+		return nil
+	}
+
+	httpErr := validator.ValidationError(err)
+	if len(httpErr.Fields) == 0 {
+		return httpErr
+	}
+
+	messages := make([]string, len(httpErr.Fields))
+	for i, field := range httpErr.Fields {
+		messages[i] = field.Message
+	}
+
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// stringList is a flag that can be repeated to collect several values.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/cmd/api"
+	"github.com/OmprakashD20/refero-api/config"
+	"github.com/OmprakashD20/refero-api/database"
+)
+
+func runServe(ctx context.Context, cfg *config.Config, conn *pgxpool.Pool, args []string) error {
+	// Stop the server on SIGINT/SIGTERM, the pool is closed by main once it returns
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Connect the read replica, if any
+	replica, err := database.InitReplicaDB(ctx, &cfg.DB)
+	if err != nil {
+		return fmt.Errorf("failed connecting the read replica: %w", err)
+	}
+	if replica != nil {
+		defer replica.Close()
+		slog.Info("connected to the read replica")
+	}
+
+	// Run the server
+	server := api.NewAPIServer(cfg, conn, replica)
+
+	return server.Run(ctx)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/config"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/services/links"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+const shortcodeUsage = `usage: shortcode <command>
+
+commands:
+  resolve [-o table|json] CODE   print the link behind a short code`
+
+var errShortcodeUsage = errors.New(shortcodeUsage)
+
+func runShortcode(ctx context.Context, cfg *config.Config, conn *pgxpool.Pool, args []string) error {
+	if len(args) == 0 || args[0] != "resolve" {
+		return errShortcodeUsage
+	}
+
+	fs := flag.NewFlagSet("shortcode resolve", flag.ContinueOnError)
+	format := addFormatFlag(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errShortcodeUsage
+	}
+
+	link, err := links.NewStore(conn).GetLinkByShortURL(ctx, fs.Arg(0), nil)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return fmt.Errorf("%w: %s", errs.ErrLinkNotFound, fs.Arg(0))
+	}
+
+	return printOutput(*format, link, func(w io.Writer) {
+		printLinks(w, []types.LinkDTO{*link})
+	})
+}
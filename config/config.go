@@ -1,53 +1,608 @@
 package config
 
 import (
-	"log"
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
-type EnvConfig struct {
-	AppEnv string
-	Port   string
-	DB     DBConfig
+// Config is resolved in layers, each overriding the previous one:
+// defaults, the optional config file, the environment (including a .env file)
+// and finally DATABASE_URL.
+type Config struct {
+	AppEnv    string          `yaml:"app_env" toml:"app_env" env:"APP_ENV"`
+	Port      string          `yaml:"port" toml:"port" env:"PORT"`
+	Log       LogConfig       `yaml:"log" toml:"log"`
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	DB        DBConfig        `yaml:"db" toml:"db"`
+	Trash     TrashConfig     `yaml:"trash" toml:"trash"`
+	Tracing   TracingConfig   `yaml:"tracing" toml:"tracing"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit" env:"RATE_LIMIT"`
+	CORS      CORSConfig      `yaml:"cors" toml:"cors" env:"CORS"`
+	Cache     CacheConfig     `yaml:"cache" toml:"cache" env:"CACHE"`
+	Events    EventsConfig    `yaml:"events" toml:"events" env:"EVENTS"`
+	Webhooks  WebhooksConfig  `yaml:"webhooks" toml:"webhooks" env:"WEBHOOKS"`
+	GraphQL   GraphQLConfig   `yaml:"graphql" toml:"graphql" env:"GRAPHQL"`
+	RPC       RPCConfig       `yaml:"rpc" toml:"rpc" env:"RPC"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT"` // "json" or "text"
+}
+
+type ServerConfig struct {
+	ReadTimeout       Duration `yaml:"read_timeout" toml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	ReadHeaderTimeout Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	WriteTimeout      Duration `yaml:"write_timeout" toml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout   Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT"` // How long in-flight requests get to finish on shutdown
+	MaxHeaderBytes    ByteSize `yaml:"max_header_bytes" toml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES"`
+	// Addresses or CIDR ranges of the proxies whose X-Forwarded-For header is trusted for the client IP.
+	// None are trusted by default, so the client IP is the address of the peer.
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES"`
 }
 
 type DBConfig struct {
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	SSLMode    string
+	URL        string `yaml:"url" toml:"url" env:"DATABASE_URL"` // Takes precedence over the individual fields below
+	DBHost     string `yaml:"host" toml:"host" env:"DB_HOST"`
+	DBPort     string `yaml:"port" toml:"port" env:"DB_PORT"`
+	DBUser     string `yaml:"user" toml:"user" env:"DB_USER"`
+	DBPassword string `yaml:"password" toml:"password" env:"DB_PASS"`
+	DBName     string `yaml:"name" toml:"name" env:"DB_NAME"`
+	SSLMode    string `yaml:"sslmode" toml:"sslmode" env:"DB_SSLMODE"`
+
+	ReplicaURL string `yaml:"replica_url" toml:"replica_url" env:"DATABASE_REPLICA_URL"` // Optional read replica for read-only queries
+
+	Pool  PoolConfig  `yaml:"pool" toml:"pool"`
+	Retry RetryConfig `yaml:"retry" toml:"retry"`
+}
+
+type PoolConfig struct {
+	MaxConns          int32    `yaml:"max_conns" toml:"max_conns" env:"DB_MAX_CONNS"`
+	MinConns          int32    `yaml:"min_conns" toml:"min_conns" env:"DB_MIN_CONNS"`
+	MaxConnLifetime   Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime   Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME"`
+	HealthCheckPeriod Duration `yaml:"health_check_period" toml:"health_check_period" env:"DB_HEALTH_CHECK_PERIOD"`
+	StatementTimeout  Duration `yaml:"statement_timeout" toml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT"` // Zero disables the timeout
 }
 
-func initEnvConfig() EnvConfig {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Fatalf("Error loading .env file")
+// RetryConfig controls how the initial connection is retried while the database is unavailable.
+type RetryConfig struct {
+	Timeout      Duration `yaml:"timeout" toml:"timeout" env:"DB_CONNECT_TIMEOUT"` // Give up connecting after this long
+	InitialDelay Duration `yaml:"initial_delay" toml:"initial_delay" env:"DB_RETRY_INITIAL_DELAY"`
+	MaxDelay     Duration `yaml:"max_delay" toml:"max_delay" env:"DB_RETRY_MAX_DELAY"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER"`            // "none" or "otlp"
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"` // An http:// endpoint disables TLS
+	ServiceName string  `yaml:"service_name" toml:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled" env:"ENABLED"`
+	// Clients sending one of APIKeys in this header are limited per key instead of per IP.
+	// Other values are ignored, so clients can't dodge the limits by sending a new key with every request.
+	APIKeyHeader string   `yaml:"api_key_header" toml:"api_key_header" env:"API_KEY_HEADER"`
+	APIKeys      []string `yaml:"api_keys" toml:"api_keys" env:"API_KEYS"`
+
+	Redirect RateLimitPolicy `yaml:"redirect" toml:"redirect" env:"REDIRECT"` // Short URL redirects
+	Read     RateLimitPolicy `yaml:"read" toml:"read" env:"READ"`
+	Write    RateLimitPolicy `yaml:"write" toml:"write" env:"WRITE"` // POST, PUT, PATCH and DELETE requests
+}
+
+// RateLimitPolicy allows Requests per Period on average, with bursts of up to Burst requests.
+type RateLimitPolicy struct {
+	Requests int      `yaml:"requests" toml:"requests" env:"REQUESTS"`
+	Period   Duration `yaml:"period" toml:"period" env:"PERIOD"`
+	Burst    int      `yaml:"burst" toml:"burst" env:"BURST"`
+}
+
+// CORSPolicy lists the cross-origin requests browsers are allowed to make.
+// Origins may be "*" or use a wildcard for subdomains, e.g. "https://*.example.com".
+type CORSPolicy struct {
+	AllowedOrigins   []string `yaml:"allowed_origins" toml:"allowed_origins" env:"ALLOWED_ORIGINS"`
+	AllowedMethods   []string `yaml:"allowed_methods" toml:"allowed_methods" env:"ALLOWED_METHODS"`
+	AllowedHeaders   []string `yaml:"allowed_headers" toml:"allowed_headers" env:"ALLOWED_HEADERS"`
+	ExposedHeaders   []string `yaml:"exposed_headers" toml:"exposed_headers" env:"EXPOSED_HEADERS"`
+	AllowCredentials bool     `yaml:"allow_credentials" toml:"allow_credentials" env:"ALLOW_CREDENTIALS"`
+	MaxAge           Duration `yaml:"max_age" toml:"max_age" env:"MAX_AGE"` // How long browsers may cache preflight responses
+}
+
+type CORSConfig struct {
+	CORSPolicy `yaml:",inline" toml:",inline"`
+
+	// Overrides apply a different policy to the routes under a path prefix. Fields left empty
+	// are inherited from the default policy. They can only be set in the config file.
+	Overrides []CORSOverride `yaml:"overrides" toml:"overrides"`
+}
+
+type CORSOverride struct {
+	Prefix           string   `yaml:"prefix" toml:"prefix"`
+	AllowedOrigins   []string `yaml:"allowed_origins" toml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods" toml:"allowed_methods"`
+	AllowCredentials *bool    `yaml:"allow_credentials" toml:"allow_credentials"`
+}
+
+// Policy returns the policy of the override, filling in the blanks from the default policy.
+func (o CORSOverride) Policy(defaults CORSPolicy) CORSPolicy {
+	policy := defaults
+	if len(o.AllowedOrigins) > 0 {
+		policy.AllowedOrigins = o.AllowedOrigins
 	}
+	if len(o.AllowedMethods) > 0 {
+		policy.AllowedMethods = o.AllowedMethods
+	}
+	if o.AllowCredentials != nil {
+		policy.AllowCredentials = *o.AllowCredentials
+	}
+	return policy
+}
+
+// CacheConfig controls the in-process cache of short URL lookups.
+type CacheConfig struct {
+	Enabled     bool     `yaml:"enabled" toml:"enabled" env:"ENABLED"`
+	Size        int      `yaml:"size" toml:"size" env:"SIZE"` // Maximum number of cached short URLs
+	TTL         Duration `yaml:"ttl" toml:"ttl" env:"TTL"`
+	NegativeTTL Duration `yaml:"negative_ttl" toml:"negative_ttl" env:"NEGATIVE_TTL"` // How long unknown short URLs are remembered
+}
+
+// EventsConfig controls the change feed and the outbox clients resume it from.
+type EventsConfig struct {
+	Retention  Duration `yaml:"retention" toml:"retention" env:"RETENTION"`       // How long clients can resume after disconnecting
+	BufferSize int      `yaml:"buffer_size" toml:"buffer_size" env:"BUFFER_SIZE"` // Events queued per subscriber before it is disconnected
+	Heartbeat  Duration `yaml:"heartbeat" toml:"heartbeat" env:"HEARTBEAT"`       // Keeps idle connections open through proxies
+	WebSocket  bool     `yaml:"websocket" toml:"websocket" env:"WEBSOCKET"`
+}
 
-	return EnvConfig{
-		AppEnv: *getEnv("APP_ENV"),
-		Port:   *getEnv("PORT"),
+// WebhooksConfig controls how webhook deliveries are attempted and retried.
+type WebhooksConfig struct {
+	Enabled        bool     `yaml:"enabled" toml:"enabled" env:"ENABLED"` // Whether this instance delivers webhooks
+	Workers        int      `yaml:"workers" toml:"workers" env:"WORKERS"` // Deliveries attempted concurrently
+	Timeout        Duration `yaml:"timeout" toml:"timeout" env:"TIMEOUT"`
+	PollInterval   Duration `yaml:"poll_interval" toml:"poll_interval" env:"POLL_INTERVAL"` // Events wake the workers up sooner
+	MaxAttempts    int      `yaml:"max_attempts" toml:"max_attempts" env:"MAX_ATTEMPTS"`
+	InitialBackoff Duration `yaml:"initial_backoff" toml:"initial_backoff" env:"INITIAL_BACKOFF"`
+	MaxBackoff     Duration `yaml:"max_backoff" toml:"max_backoff" env:"MAX_BACKOFF"`
+	DisableAfter   int      `yaml:"disable_after" toml:"disable_after" env:"DISABLE_AFTER"` // Consecutive failed attempts before a webhook is disabled
+	LogRetention   Duration `yaml:"log_retention" toml:"log_retention" env:"LOG_RETENTION"`
+	// Allows webhooks to target loopback and private addresses. Leave it off unless every client
+	// that can register webhooks is trusted, as they could otherwise probe the internal network.
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" toml:"allow_private_networks" env:"ALLOW_PRIVATE_NETWORKS"`
+}
+
+// GraphQLConfig controls the GraphQL endpoint and the limits on the queries it accepts.
+type GraphQLConfig struct {
+	Enabled       bool `yaml:"enabled" toml:"enabled" env:"ENABLED"`
+	MaxDepth      int  `yaml:"max_depth" toml:"max_depth" env:"MAX_DEPTH"`                // Deepest nesting of fields a query may select
+	MaxComplexity int  `yaml:"max_complexity" toml:"max_complexity" env:"MAX_COMPLEXITY"` // Lists count ten times the complexity of their items
+	Introspection bool `yaml:"introspection" toml:"introspection" env:"INTROSPECTION"`
+}
+
+// RPCConfig controls the gRPC and Connect API for internal services.
+type RPCConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled" env:"ENABLED"`
+	// Bearer tokens of the services allowed to call the API, as "name:secret".
+	// The name identifies the caller in the logs.
+	Tokens []string `yaml:"tokens" toml:"tokens" env:"TOKENS"`
+}
+
+type TrashConfig struct {
+	Retention     Duration `yaml:"retention" toml:"retention" env:"TRASH_RETENTION"`
+	PurgeInterval Duration `yaml:"purge_interval" toml:"purge_interval" env:"TRASH_PURGE_INTERVAL"`
+}
+
+var (
+	appEnvs          = []string{"development", "test", "production"}
+	logLevels        = []string{"debug", "info", "warn", "error"}
+	logFormats       = []string{"json", "text"}
+	tracingExporters = []string{"none", "otlp"}
+	sslModes         = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+)
+
+// Default returns the configuration used when nothing else is set.
+func Default() Config {
+	return Config{
+		AppEnv: "development",
+		Port:   "8080",
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Server: ServerConfig{
+			ReadTimeout:       Duration(15 * time.Second),
+			ReadHeaderTimeout: Duration(5 * time.Second),
+			WriteTimeout:      Duration(30 * time.Second),
+			IdleTimeout:       Duration(60 * time.Second),
+			ShutdownTimeout:   Duration(20 * time.Second),
+			MaxHeaderBytes:    1 << 20,
+		},
 		DB: DBConfig{
-			DBHost:     *getEnv("DB_HOST"),
-			DBPort:     *getEnv("DB_PORT"),
-			DBUser:     *getEnv("DB_USER"),
-			DBPassword: *getEnv("DB_PASS"),
-			DBName:     *getEnv("DB_NAME"),
-			SSLMode:    *getEnv("DB_SSLMODE"),
+			DBHost:  "localhost",
+			DBPort:  "5432",
+			SSLMode: "disable",
+			Pool: PoolConfig{
+				MaxConns:          10,
+				MinConns:          0,
+				MaxConnLifetime:   Duration(time.Hour),
+				MaxConnIdleTime:   Duration(30 * time.Minute),
+				HealthCheckPeriod: Duration(time.Minute),
+				StatementTimeout:  Duration(30 * time.Second),
+			},
+			Retry: RetryConfig{
+				Timeout:      Duration(time.Minute),
+				InitialDelay: Duration(500 * time.Millisecond),
+				MaxDelay:     Duration(10 * time.Second),
+			},
+		},
+		Trash: TrashConfig{
+			Retention:     Duration(30 * 24 * time.Hour),
+			PurgeInterval: Duration(time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:  true,
+			Redirect: RateLimitPolicy{Requests: 600, Period: Duration(time.Minute), Burst: 100},
+			Read:     RateLimitPolicy{Requests: 300, Period: Duration(time.Minute), Burst: 60},
+			Write:    RateLimitPolicy{Requests: 30, Period: Duration(time.Minute), Burst: 10},
+		},
+		CORS: CORSConfig{
+			CORSPolicy: CORSPolicy{
+				AllowedOrigins: []string{"http://localhost:5173"},
+				AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+				AllowedHeaders: []string{
+					"Content-Type", "Accept", "Authorization", "Cache-Control", "X-Requested-With",
+					"X-CSRF-Token", "X-Request-ID", "If-Match", "If-None-Match",
+				},
+				ExposedHeaders: []string{
+					"ETag", "Location", "X-Request-ID", "Retry-After",
+					"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+				},
+				MaxAge: Duration(24 * time.Hour),
+			},
+		},
+		Cache: CacheConfig{
+			Enabled:     true,
+			Size:        10_000,
+			TTL:         Duration(5 * time.Minute),
+			NegativeTTL: Duration(30 * time.Second),
+		},
+		Events: EventsConfig{
+			Retention:  Duration(7 * 24 * time.Hour),
+			BufferSize: 64,
+			Heartbeat:  Duration(15 * time.Second),
+			WebSocket:  true,
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:        true,
+			Workers:        4,
+			Timeout:        Duration(10 * time.Second),
+			PollInterval:   Duration(5 * time.Second),
+			MaxAttempts:    8,
+			InitialBackoff: Duration(30 * time.Second),
+			MaxBackoff:     Duration(6 * time.Hour),
+			DisableAfter:   50,
+			LogRetention:   Duration(30 * 24 * time.Hour),
+		},
+		GraphQL: GraphQLConfig{
+			Enabled:       true,
+			MaxDepth:      10,
+			MaxComplexity: 5_000,
+			Introspection: true,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			Endpoint:    "http://localhost:4318",
+			ServiceName: "refero-api",
+			SampleRatio: 1,
 		},
 	}
 }
 
-func getEnv(key string) *string {
-	if value, ok := os.LookupEnv(key); ok {
-		return &value
+// Load resolves the configuration from the defaults, the config file at path (if not empty)
+// and the environment. A .env file in the working directory is loaded when present,
+// without overriding variables that are already set.
+func Load(path string) (*Config, error) {
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("load .env: %w", err)
+	}
+
+	return load(path, os.LookupEnv)
+}
+
+// load resolves the configuration with the environment variables returned by lookup.
+func load(path string, lookup func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// Invalid env values are reported along with the validation errors
+	envErr := applyEnv(reflect.ValueOf(&cfg).Elem(), "", lookup)
+	if err := errors.Join(envErr, cfg.Validate()); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("config file %s: unsupported format, expected .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv overrides every field tagged with `env` whose variable is set.
+// The env tag of a nested struct is a prefix for the variables of its fields,
+// so the same struct type can be used more than once. All invalid values are reported together.
+func applyEnv(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	var errs []error
+
+	for i := range v.NumField() {
+		field, value := v.Type().Field(i), v.Field(i)
+		key := field.Tag.Get("env")
+
+		if field.Type.Kind() == reflect.Struct {
+			nested := prefix
+			if key != "" {
+				nested = prefix + key + "_"
+			}
+			errs = append(errs, applyEnv(value, nested, lookup))
+			continue
+		}
+
+		if key == "" {
+			continue
+		}
+		key = prefix + key
+		raw, ok := lookup(key)
+		if !ok {
+			continue
+		}
+
+		if err := setField(value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func setField(value reflect.Value, raw string) error {
+	if u, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config type %s", value.Type())
+		}
+		// Comma separated, e.g. "https://a.example.com, https://b.example.com"
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	case reflect.Bool:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(flag)
+	case reflect.Float64:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		value.SetFloat(number)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(number)
+	default:
+		return fmt.Errorf("unsupported config type %s", value.Type())
 	}
 
-	log.Fatalf("Environment variable %s is not set", key)
 	return nil
 }
 
-var Envs = initEnvConfig()
+// Validate checks the whole configuration and reports every problem at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(slices.Contains(appEnvs, c.AppEnv), "app_env must be one of %s, got %q", strings.Join(appEnvs, ", "), c.AppEnv)
+	check(validPort(c.Port), "port must be a number between 1 and 65535, got %q", c.Port)
+
+	check(slices.Contains(logLevels, strings.ToLower(c.Log.Level)), "log.level must be one of %s, got %q", strings.Join(logLevels, ", "), c.Log.Level)
+	check(slices.Contains(logFormats, strings.ToLower(c.Log.Format)), "log.format must be one of %s, got %q", strings.Join(logFormats, ", "), c.Log.Format)
+
+	check(c.Server.ReadTimeout >= 0, "server.read_timeout must not be negative")
+	check(c.Server.ReadHeaderTimeout >= 0, "server.read_header_timeout must not be negative")
+	check(c.Server.WriteTimeout >= 0, "server.write_timeout must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.MaxHeaderBytes > 0, "server.max_header_bytes must be positive")
+
+	if c.DB.URL != "" {
+		u, err := url.Parse(c.DB.URL)
+		check(err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql"),
+			"db.url must be a postgres:// or postgresql:// URL")
+	} else {
+		check(c.DB.DBHost != "", "db.host is required")
+		check(validPort(c.DB.DBPort), "db.port must be a number between 1 and 65535, got %q", c.DB.DBPort)
+		check(c.DB.DBUser != "", "db.user is required")
+		check(c.DB.DBName != "", "db.name is required")
+		check(slices.Contains(sslModes, c.DB.SSLMode), "db.sslmode must be one of %s, got %q", strings.Join(sslModes, ", "), c.DB.SSLMode)
+	}
+
+	if c.DB.ReplicaURL != "" {
+		u, err := url.Parse(c.DB.ReplicaURL)
+		check(err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql"),
+			"db.replica_url must be a postgres:// or postgresql:// URL")
+	}
+
+	pool := c.DB.Pool
+	check(pool.MaxConns > 0, "db.pool.max_conns must be positive")
+	check(pool.MinConns >= 0 && pool.MinConns <= pool.MaxConns, "db.pool.min_conns must be between 0 and db.pool.max_conns")
+	check(pool.MaxConnLifetime > 0, "db.pool.max_conn_lifetime must be positive")
+	check(pool.MaxConnIdleTime > 0, "db.pool.max_conn_idle_time must be positive")
+	check(pool.HealthCheckPeriod > 0, "db.pool.health_check_period must be positive")
+	check(pool.StatementTimeout >= 0, "db.pool.statement_timeout must not be negative")
+
+	retry := c.DB.Retry
+	check(retry.Timeout > 0, "db.retry.timeout must be positive")
+	check(retry.InitialDelay > 0, "db.retry.initial_delay must be positive")
+	check(retry.MaxDelay >= retry.InitialDelay, "db.retry.max_delay must not be less than db.retry.initial_delay")
+
+	check(slices.Contains(tracingExporters, c.Tracing.Exporter), "tracing.exporter must be one of %s, got %q", strings.Join(tracingExporters, ", "), c.Tracing.Exporter)
+	if c.Tracing.Exporter != "none" {
+		u, err := url.Parse(c.Tracing.Endpoint)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"tracing.endpoint must be an http:// or https:// URL, got %q", c.Tracing.Endpoint)
+		check(c.Tracing.ServiceName != "", "tracing.service_name is required")
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+
+	for _, policy := range []struct {
+		name string
+		RateLimitPolicy
+	}{
+		{"redirect", c.RateLimit.Redirect},
+		{"read", c.RateLimit.Read},
+		{"write", c.RateLimit.Write},
+	} {
+		check(policy.Requests > 0, "rate_limit.%s.requests must be positive", policy.name)
+		check(policy.Period > 0, "rate_limit.%s.period must be positive", policy.name)
+		check(policy.Burst > 0, "rate_limit.%s.burst must be positive", policy.name)
+	}
+
+	if c.RateLimit.APIKeyHeader != "" {
+		check(len(c.RateLimit.APIKeys) > 0, "rate_limit.api_keys must not be empty when rate_limit.api_key_header is set")
+	}
+
+	checkCORS := func(name string, policy CORSPolicy) {
+		for _, origin := range policy.AllowedOrigins {
+			check(validOriginPattern(origin), "%s.allowed_origins: invalid origin %q", name, origin)
+			check(origin != "*" || !policy.AllowCredentials, "%s: credentials can't be allowed for every origin", name)
+		}
+		check(policy.MaxAge >= 0, "%s.max_age must not be negative", name)
+	}
+	checkCORS("cors", c.CORS.CORSPolicy)
+	for i, override := range c.CORS.Overrides {
+		name := fmt.Sprintf("cors.overrides[%d]", i)
+		check(strings.HasPrefix(override.Prefix, "/"), "%s.prefix must start with /", name)
+		checkCORS(name, override.Policy(c.CORS.CORSPolicy))
+	}
+
+	check(c.Cache.Size > 0, "cache.size must be positive")
+	check(c.Cache.TTL > 0, "cache.ttl must be positive")
+	check(c.Cache.NegativeTTL >= 0, "cache.negative_ttl must not be negative")
+
+	check(c.Events.Retention > 0, "events.retention must be positive")
+	check(c.Events.BufferSize > 0, "events.buffer_size must be positive")
+	check(c.Events.Heartbeat > 0, "events.heartbeat must be positive")
+
+	check(c.Webhooks.Workers > 0, "webhooks.workers must be positive")
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout must be positive")
+	check(c.Webhooks.PollInterval > 0, "webhooks.poll_interval must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+	check(c.Webhooks.InitialBackoff > 0, "webhooks.initial_backoff must be positive")
+	check(c.Webhooks.MaxBackoff >= c.Webhooks.InitialBackoff, "webhooks.max_backoff must not be less than webhooks.initial_backoff")
+	check(c.Webhooks.DisableAfter > 0, "webhooks.disable_after must be positive")
+	check(c.Webhooks.LogRetention > 0, "webhooks.log_retention must be positive")
+
+	check(c.GraphQL.MaxDepth > 0, "graphql.max_depth must be positive")
+	check(c.GraphQL.MaxComplexity > 0, "graphql.max_complexity must be positive")
+
+	if c.RPC.Enabled {
+		check(len(c.RPC.Tokens) > 0, "rpc.tokens must not be empty when rpc is enabled")
+	}
+	for i, token := range c.RPC.Tokens {
+		name, secret, _ := strings.Cut(token, ":")
+		check(name != "" && secret != "", "rpc.tokens[%d] must be formatted as name:secret", i)
+	}
+
+	check(c.Trash.Retention >= 0, "trash.retention must not be negative")
+	check(c.Trash.PurgeInterval >= 0, "trash.purge_interval must not be negative")
+
+	return errors.Join(errs...)
+}
+
+// DSN returns the connection string for the database.
+func (c *DBConfig) DSN() string {
+	if c.URL != "" {
+		return c.URL
+	}
+
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		quoteDSN(c.DBHost), quoteDSN(c.DBPort), quoteDSN(c.DBUser), quoteDSN(c.DBPassword), quoteDSN(c.DBName), quoteDSN(c.SSLMode),
+	)
+}
+
+// quoteDSN quotes a keyword/value connection string value, so that empty values
+// and values with spaces or quotes are parsed correctly.
+func quoteDSN(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+func validPort(port string) bool {
+	number, err := strconv.Atoi(port)
+	return err == nil && number > 0 && number <= 65535
+}
+
+// validOriginPattern accepts "*" and scheme://host[:port] origins, where the host
+// may start with a "*." wildcard.
+func validOriginPattern(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	return err == nil && u.Scheme != "" && u.Host != "" && u.Path == "" && u.RawQuery == "" && u.User == nil
+}
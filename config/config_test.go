@@ -0,0 +1,419 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// env returns a lookup of the given variables only.
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+// writeFile writes a config file with the given name to a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const yamlConfig = `
+port: "9000"
+log:
+  level: debug
+db:
+  host: db.internal
+  user: refero
+  name: refero
+  pool:
+    max_conns: 20
+rate_limit:
+  read:
+    requests: 100
+    period: 30s
+    burst: 20
+cors:
+  allowed_origins: ["https://*.example.com"]
+  overrides:
+    - prefix: /api/v1/link/r
+      allowed_origins: ["*"]
+`
+
+const tomlConfig = `
+port = "9000"
+
+[log]
+level = "debug"
+
+[db]
+host = "db.internal"
+user = "refero"
+name = "refero"
+
+[db.pool]
+max_conns = 20
+
+[rate_limit.read]
+requests = 100
+period = "30s"
+burst = 20
+
+[cors]
+allowed_origins = ["https://*.example.com"]
+
+[[cors.overrides]]
+prefix = "/api/v1/link/r"
+allowed_origins = ["*"]
+`
+
+func TestLoadLayers(t *testing.T) {
+	for _, file := range []struct{ name, content string }{
+		{"config.yaml", yamlConfig},
+		{"config.toml", tomlConfig},
+	} {
+		t.Run(file.name, func(t *testing.T) {
+			path := writeFile(t, file.name, file.content)
+
+			cfg, err := load(path, env(map[string]string{
+				"PORT":                    "9100",
+				"DB_MAX_CONNS":            "30",
+				"RATE_LIMIT_READ_BURST":   "40",
+				"RATE_LIMIT_WRITE_PERIOD": "2m",
+				"CORS_ALLOWED_ORIGINS":    "https://app.example.com, https://admin.example.com",
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The environment overrides the file, which overrides the defaults
+			tests := []struct {
+				name string
+				got  any
+				want any
+			}{
+				{"port from env", cfg.Port, "9100"},
+				{"log level from file", cfg.Log.Level, "debug"},
+				{"log format from defaults", cfg.Log.Format, "text"},
+				{"db host from file", cfg.DB.DBHost, "db.internal"},
+				{"db port from defaults", cfg.DB.DBPort, "5432"},
+				{"max conns from env", cfg.DB.Pool.MaxConns, int32(30)},
+				{"min conns from defaults", cfg.DB.Pool.MinConns, int32(0)},
+				{"read requests from file", cfg.RateLimit.Read.Requests, 100},
+				{"read period from file", cfg.RateLimit.Read.Period, Duration(30 * time.Second)},
+				{"read burst from env", cfg.RateLimit.Read.Burst, 40},
+				{"write period from env", cfg.RateLimit.Write.Period, Duration(2 * time.Minute)},
+				{"write requests from defaults", cfg.RateLimit.Write.Requests, 30},
+				{"override prefix from file", cfg.CORS.Overrides[0].Prefix, "/api/v1/link/r"},
+			}
+			for _, tt := range tests {
+				if tt.got != tt.want {
+					t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+				}
+			}
+
+			if want := []string{"https://app.example.com", "https://admin.example.com"}; !slices.Equal(cfg.CORS.AllowedOrigins, want) {
+				t.Errorf("allowed origins from env: got %q, want %q", cfg.CORS.AllowedOrigins, want)
+			}
+		})
+	}
+}
+
+func TestLoadNestedEnv(t *testing.T) {
+	// The env tags of nested structs prefix the variables of their fields
+	cfg, err := load("", env(map[string]string{
+		"DB_USER":                      "refero",
+		"DB_NAME":                      "refero",
+		"RATE_LIMIT_ENABLED":           "false",
+		"RATE_LIMIT_REDIRECT_REQUESTS": "1000",
+		"RATE_LIMIT_WRITE_REQUESTS":    "5",
+		"CACHE_NEGATIVE_TTL":           "0s",
+		"EVENTS_WEBSOCKET":             "false",
+		"WEBHOOKS_MAX_BACKOFF":         "12h",
+		"RPC_ENABLED":                  "true",
+		"RPC_TOKENS":                   "search:secret",
+		"HTTP_MAX_HEADER_BYTES":        "64KiB",
+		"TRACING_SAMPLE_RATIO":         "0.25",
+		// Without the prefix of the nested struct, the variables are ignored
+		"REQUESTS": "1",
+		"ENABLED":  "false",
+		"TTL":      "1s",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"rate limit enabled", cfg.RateLimit.Enabled, false},
+		{"redirect requests", cfg.RateLimit.Redirect.Requests, 1000},
+		{"write requests", cfg.RateLimit.Write.Requests, 5},
+		{"read requests", cfg.RateLimit.Read.Requests, 300},
+		{"cache enabled", cfg.Cache.Enabled, true},
+		{"cache ttl", cfg.Cache.TTL, Duration(5 * time.Minute)},
+		{"cache negative ttl", cfg.Cache.NegativeTTL, Duration(0)},
+		{"websocket", cfg.Events.WebSocket, false},
+		{"webhooks max backoff", cfg.Webhooks.MaxBackoff, Duration(12 * time.Hour)},
+		{"webhooks enabled", cfg.Webhooks.Enabled, true},
+		{"rpc enabled", cfg.RPC.Enabled, true},
+		{"max header bytes", cfg.Server.MaxHeaderBytes, ByteSize(64 << 10)},
+		{"sample ratio", cfg.Tracing.SampleRatio, 0.25},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadDatabaseURL(t *testing.T) {
+	path := writeFile(t, "config.yaml", yamlConfig)
+
+	cfg, err := load(path, env(map[string]string{"DATABASE_URL": "postgres://refero@db.example.com/refero"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.DB.DSN(); got != "postgres://refero@db.example.com/refero" {
+		t.Errorf("DSN: got %q, want DATABASE_URL", got)
+	}
+
+	// The individual fields aren't required once the URL is set
+	cfg, err = load("", env(map[string]string{"DATABASE_URL": "postgresql://localhost/refero", "DB_HOST": ""}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.DB.DSN(); got != "postgresql://localhost/refero" {
+		t.Errorf("DSN: got %q, want DATABASE_URL", got)
+	}
+
+	// Without it, the DSN is built from the fields
+	cfg, err = load(path, env(map[string]string{"DB_PASS": "it's secret"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `host='db.internal' port='5432' user='refero' password='it\'s secret' dbname='refero' sslmode='disable'`
+	if got := cfg.DB.DSN(); got != want {
+		t.Errorf("DSN: got %q, want %q", got, want)
+	}
+
+	if _, err := load("", env(map[string]string{"DATABASE_URL": "mysql://localhost/refero"})); err == nil || !strings.Contains(err.Error(), "db.url must be") {
+		t.Errorf("load with a mysql:// DATABASE_URL: got %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := load("", env(map[string]string{
+		"APP_ENV":                "staging",
+		"DB_USER":                "refero",
+		"DB_NAME":                "refero",
+		"DB_MAX_CONNS":           "many",
+		"HTTP_READ_TIMEOUT":      "15",
+		"RATE_LIMIT_READ_BURST":  "0",
+		"CORS_ALLOWED_ORIGINS":   "*",
+		"CORS_ALLOW_CREDENTIALS": "true",
+		"RPC_ENABLED":            "yes",
+	}))
+	if err == nil {
+		t.Fatal("load succeeded")
+	}
+
+	// Invalid env values and failed checks are reported together
+	for _, want := range []string{
+		`DB_MAX_CONNS: invalid integer "many"`,
+		`HTTP_READ_TIMEOUT: invalid duration "15"`,
+		`RPC_ENABLED: invalid boolean "yes"`,
+		`app_env must be one of development, test, production, got "staging"`,
+		"rate_limit.read.burst must be positive",
+		"cors: credentials can't be allowed for every origin",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.yaml"), "read config file"},
+		{"unsupported format", writeFile(t, "config.json", `{}`), "unsupported format"},
+		{"invalid yaml", writeFile(t, "config.yml", "port: [9000"), "parse config file"},
+		{"invalid toml", writeFile(t, "config.toml", `port = `), "parse config file"},
+		{"invalid duration in file", writeFile(t, "config.yaml", "cache:\n  ttl: 5 minutes\n"), "invalid duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := load(tt.path, env(nil)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Config {
+		cfg := Default()
+		cfg.DB.DBUser, cfg.DB.DBName = "refero", "refero"
+		return cfg
+	}
+
+	if cfg := valid(); cfg.Validate() != nil {
+		t.Fatalf("the defaults with a database are invalid: %v", cfg.Validate())
+	}
+
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   []string
+	}{
+		{
+			name:   "database fields",
+			modify: func(cfg *Config) { cfg.DB.DBUser, cfg.DB.DBPort, cfg.DB.SSLMode = "", "0", "always" },
+			want:   []string{"db.user is required", `db.port must be a number between 1 and 65535, got "0"`, `db.sslmode must be one of`},
+		},
+		{
+			name: "pool and retry",
+			modify: func(cfg *Config) {
+				cfg.DB.Pool.MinConns = 11
+				cfg.DB.Retry.MaxDelay = Duration(time.Millisecond)
+			},
+			want: []string{"db.pool.min_conns must be between 0 and db.pool.max_conns", "db.retry.max_delay must not be less than db.retry.initial_delay"},
+		},
+		{
+			name: "tracing",
+			modify: func(cfg *Config) {
+				cfg.Tracing.Exporter, cfg.Tracing.Endpoint, cfg.Tracing.SampleRatio = "otlp", "localhost:4318", 2
+			},
+			want: []string{`tracing.endpoint must be an http:// or https:// URL, got "localhost:4318"`, "tracing.sample_ratio must be between 0 and 1"},
+		},
+		{
+			name: "cors overrides",
+			modify: func(cfg *Config) {
+				allow := true
+				cfg.CORS.Overrides = []CORSOverride{
+					{Prefix: "api", AllowedOrigins: []string{"https://example.com/path"}},
+					{Prefix: "/api", AllowedOrigins: []string{"*"}, AllowCredentials: &allow},
+				}
+			},
+			want: []string{
+				"cors.overrides[0].prefix must start with /",
+				`cors.overrides[0].allowed_origins: invalid origin "https://example.com/path"`,
+				"cors.overrides[1]: credentials can't be allowed for every origin",
+			},
+		},
+		{
+			name: "rpc tokens",
+			modify: func(cfg *Config) {
+				cfg.RPC.Enabled, cfg.RPC.Tokens = true, []string{"search:secret", "nosecret", ":secret"}
+			},
+			want: []string{"rpc.tokens[1] must be formatted as name:secret", "rpc.tokens[2] must be formatted as name:secret"},
+		},
+		{
+			name:   "rate limit keys",
+			modify: func(cfg *Config) { cfg.RateLimit.APIKeyHeader = "X-API-Key" },
+			want:   []string{"rate_limit.api_keys must not be empty when rate_limit.api_key_header is set"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("Validate succeeded")
+			}
+			if got := strings.Count(err.Error(), "\n") + 1; got != len(tt.want) {
+				t.Errorf("got %d errors, want %d:\n%v", got, len(tt.want), err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error does not mention %q:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Duration
+		ok   bool
+	}{
+		{"15s", 15 * time.Second, true},
+		{" 720h ", 720 * time.Hour, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"0", 0, true},
+		{"-5s", -5 * time.Second, true},
+		{"15", 0, false},
+		{"five seconds", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		var d Duration
+		err := d.UnmarshalText([]byte(tt.text))
+		if tt.ok && (err != nil || time.Duration(d) != tt.want) {
+			t.Errorf("Duration(%q): got %v, %v, want %v", tt.text, d, err, tt.want)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("Duration(%q): got %v, want an error", tt.text, d)
+		}
+	}
+
+	if text, err := Duration(90 * time.Second).MarshalText(); err != nil || string(text) != "1m30s" {
+		t.Errorf("MarshalText: got %q, %v", text, err)
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		text string
+		want int64
+		ok   bool
+	}{
+		{"1048576", 1 << 20, true},
+		{"512B", 512, true},
+		{"512KB", 512_000, true},
+		{"512kib", 512 << 10, true},
+		{"1MiB", 1 << 20, true},
+		{"1 MB", 1_000_000, true},
+		{"2GB", 2_000_000_000, true},
+		{"2GiB", 2 << 30, true},
+		{"4k", 4 << 10, true},
+		{"4M", 4 << 20, true},
+		{" 1G ", 1 << 30, true},
+		{"-1KB", 0, false},
+		{"1.5MB", 0, false},
+		{"KB", 0, false},
+		{"1TB", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		var b ByteSize
+		err := b.UnmarshalText([]byte(tt.text))
+		if tt.ok && (err != nil || int64(b) != tt.want) {
+			t.Errorf("ByteSize(%q): got %d, %v, want %d", tt.text, b, err, tt.want)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("ByteSize(%q): got %d, want an error", tt.text, b)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+// Package dbtest runs tests against a throwaway Postgres cluster.
+//
+// The cluster is created with initdb the first time a test asks for a database, listens on a
+// Unix socket in a temporary directory, and is removed once the tests finish. The migrations are
+// applied once to a template database, which every test database is cloned from.
+//
+// Tests are skipped when the Postgres binaries can't be found on the PATH or in the usual install
+// locations, when initdb can't run because the tests run as root, and in short mode.
+// Set REFERO_TEST_DATABASE_URL to run them against an existing server instead,
+// connecting as a role which can create databases. Set REFERO_TEST_REQUIRE_DATABASE
+// to fail the tests instead of skipping them when Postgres is unavailable, as CI does.
+//
+// Packages using it run their tests through Main, which stops the cluster:
+//
+//	func TestMain(m *testing.M) {
+//		dbtest.Main(m)
+//	}
+package dbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/database"
+)
+
+// URLEnv names the environment variable of the server to use instead of starting a cluster.
+const URLEnv = "REFERO_TEST_DATABASE_URL"
+
+// RequireEnv names the environment variable which makes an unavailable Postgres fail the tests.
+const RequireEnv = "REFERO_TEST_REQUIRE_DATABASE"
+
+// setupTimeout bounds starting the cluster and migrating the template database.
+const setupTimeout = time.Minute
+
+// errUnavailable marks the setup failures which skip the tests instead of failing them.
+var errUnavailable = errors.New("postgres is not available")
+
+type server struct {
+	dir      string // Directory of the cluster started by initdb, empty for an existing server
+	pgCtl    string
+	config   *pgxpool.Config // Connects to the maintenance database
+	admin    *pgxpool.Pool
+	prefix   string // Prefix of the databases created by this test binary
+	template string
+	shared   *pgxpool.Pool // Database the transactions of Tx run in
+
+	databases atomic.Int64
+}
+
+var (
+	setupOnce sync.Once
+	srv       *server
+	setupErr  error
+)
+
+// Main runs the tests and stops the cluster, if one was started, before exiting.
+func Main(m *testing.M) {
+	code := m.Run()
+
+	if srv != nil {
+		if err := srv.stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "dbtest: stopping postgres: %v\n", err)
+		}
+	}
+
+	os.Exit(code)
+}
+
+// Pool returns a pool connected to a new, migrated database, which is dropped when the test ends.
+// It is meant for code that opens its own transactions, such as the stores.
+func Pool(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+
+	s := start(t)
+	ctx := context.Background()
+
+	name := fmt.Sprintf("%s_%d", s.prefix, s.databases.Add(1))
+	if err := s.createDatabase(ctx, name, s.template); err != nil {
+		t.Fatalf("dbtest: %v", err)
+	}
+
+	pool, err := s.connect(ctx, name)
+	if err != nil {
+		t.Fatalf("dbtest: %v", err)
+	}
+
+	t.Cleanup(func() {
+		pool.Close()
+		if _, err := s.admin.Exec(context.Background(), "DROP DATABASE IF EXISTS "+pgx.Identifier{name}.Sanitize()+" WITH (FORCE)"); err != nil {
+			t.Errorf("dbtest: dropping database %s: %v", name, err)
+		}
+	})
+
+	return pool
+}
+
+// Tx begins a transaction in a migrated database shared by the tests of the package,
+// and rolls it back when the test ends, so that the tests don't see each other's changes.
+func Tx(t testing.TB) pgx.Tx {
+	t.Helper()
+
+	s := start(t)
+
+	txn, err := s.shared.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("dbtest: beginning transaction: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := txn.Rollback(context.Background()); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			t.Errorf("dbtest: rolling back transaction: %v", err)
+		}
+	})
+
+	return txn
+}
+
+// start sets up the server on first use, skipping the test if Postgres is unavailable,
+// unless it is required.
+func start(t testing.TB) *server {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("dbtest: skipping database test in short mode")
+	}
+
+	setupOnce.Do(func() {
+		srv, setupErr = setup()
+	})
+
+	if errors.Is(setupErr, errUnavailable) && os.Getenv(RequireEnv) == "" {
+		t.Skipf("dbtest: %v", setupErr)
+	}
+	if setupErr != nil {
+		t.Fatalf("dbtest: %v", setupErr)
+	}
+
+	return srv
+}
+
+func setup() (*server, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	prefix := make([]byte, 4)
+	rand.Read(prefix)
+	s := &server{prefix: "refero_test_" + hex.EncodeToString(prefix)}
+	s.template = s.prefix + "_template"
+
+	dsn := os.Getenv(URLEnv)
+	if dsn == "" {
+		var err error
+		if dsn, err = s.initCluster(ctx); err != nil {
+			s.stop()
+			return nil, err
+		}
+	}
+
+	var err error
+	if s.config, err = pgxpool.ParseConfig(dsn); err != nil {
+		s.stop()
+		return nil, fmt.Errorf("invalid %s: %w", URLEnv, err)
+	}
+	if s.admin, err = pgxpool.NewWithConfig(ctx, s.config); err == nil {
+		err = s.admin.Ping(ctx)
+	}
+	if err != nil {
+		s.stop()
+		return nil, fmt.Errorf("connecting to postgres: %w", err)
+	}
+
+	if err := s.migrateTemplate(ctx); err != nil {
+		s.stop()
+		return nil, err
+	}
+
+	shared := s.prefix + "_shared"
+	if err := s.createDatabase(ctx, shared, s.template); err != nil {
+		s.stop()
+		return nil, err
+	}
+	if s.shared, err = s.connect(ctx, shared); err != nil {
+		s.stop()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// initCluster creates and starts a cluster listening on a Unix socket only, and returns its DSN.
+func (s *server) initCluster(ctx context.Context) (string, error) {
+	initDB, err := findBinary("initdb")
+	if err != nil {
+		return "", err
+	}
+	s.pgCtl = filepath.Join(filepath.Dir(initDB), "pg_ctl")
+
+	if os.Geteuid() == 0 {
+		return "", fmt.Errorf("%w: initdb can't run as root, set %s to use an existing server", errUnavailable, URLEnv)
+	}
+
+	if s.dir, err = os.MkdirTemp("", "refero-pg-"); err != nil {
+		return "", err
+	}
+	data := filepath.Join(s.dir, "data")
+
+	out, err := exec.CommandContext(ctx, initDB, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("initdb: %w\n%s", err, out)
+	}
+
+	// Durability doesn't matter for a throwaway cluster
+	options := fmt.Sprintf("-k %s -c listen_addresses='' -F", s.dir)
+	logFile := filepath.Join(s.dir, "postgres.log")
+	out, err = exec.CommandContext(ctx, s.pgCtl, "-D", data, "-l", logFile, "-o", options, "-w", "start").CombinedOutput()
+	if err != nil {
+		log, _ := os.ReadFile(logFile)
+		return "", fmt.Errorf("starting postgres: %w\n%s%s", err, out, log)
+	}
+
+	return fmt.Sprintf("host=%s user=postgres dbname=postgres sslmode=disable", s.dir), nil
+}
+
+// migrateTemplate creates the template database and applies the migrations to it.
+// Databases can only be cloned from templates nobody is connected to, so the pool is closed afterwards.
+func (s *server) migrateTemplate(ctx context.Context) error {
+	if err := s.createDatabase(ctx, s.template, "template0"); err != nil {
+		return err
+	}
+
+	pool, err := s.connect(ctx, s.template)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := database.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("migrating the template database: %w", err)
+	}
+
+	return nil
+}
+
+func (s *server) createDatabase(ctx context.Context, name, template string) error {
+	sql := fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", pgx.Identifier{name}.Sanitize(), pgx.Identifier{template}.Sanitize())
+	if _, err := s.admin.Exec(ctx, sql); err != nil {
+		return fmt.Errorf("creating database %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *server) connect(ctx context.Context, name string) (*pgxpool.Pool, error) {
+	config := s.config.Copy()
+	config.ConnConfig.Database = name
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to database %s: %w", name, err)
+	}
+	return pool, nil
+}
+
+// stop drops the databases created on an existing server, or stops and removes the cluster.
+func (s *server) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	if s.shared != nil {
+		s.shared.Close()
+	}
+
+	var errs []error
+	if s.admin != nil {
+		if s.dir == "" {
+			for _, name := range []string{s.prefix + "_shared", s.template} {
+				if _, err := s.admin.Exec(ctx, "DROP DATABASE IF EXISTS "+pgx.Identifier{name}.Sanitize()+" WITH (FORCE)"); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+		s.admin.Close()
+	}
+
+	if s.dir != "" {
+		data := filepath.Join(s.dir, "data")
+		if _, err := os.Stat(filepath.Join(data, "postmaster.pid")); err == nil {
+			if out, err := exec.CommandContext(ctx, s.pgCtl, "-D", data, "-m", "immediate", "-w", "stop").CombinedOutput(); err != nil {
+				errs = append(errs, fmt.Errorf("pg_ctl stop: %w\n%s", err, out))
+			}
+		}
+		errs = append(errs, os.RemoveAll(s.dir))
+	}
+
+	return errors.Join(errs...)
+}
+
+// findBinary looks for a Postgres binary on the PATH, then in the directories packages install it to.
+func findBinary(name string) (string, error) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	for _, pattern := range []string{
+		"/usr/lib/postgresql/*/bin",         // Debian and Ubuntu
+		"/usr/pgsql-*/bin",                  // PGDG packages for RHEL and Fedora
+		"/usr/local/pgsql/bin",              // Built from source
+		"/opt/homebrew/opt/postgresql*/bin", // Homebrew on Apple silicon
+		"/usr/local/opt/postgresql*/bin",    // Homebrew on Intel
+		"/Applications/Postgres.app/Contents/Versions/latest/bin",
+	} {
+		dirs, _ := filepath.Glob(pattern)
+		for _, dir := range dirs {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s not found, install postgres or set %s", errUnavailable, name, URLEnv)
+}
+
+// Exec runs SQL statements, such as fixtures that the queries under test can't create,
+// failing the test if they fail.
+func Exec(t testing.TB, db interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}, sql string, args ...any) {
+	t.Helper()
+
+	if _, err := db.Exec(context.Background(), sql, args...); err != nil {
+		t.Fatalf("dbtest: %s: %v", strings.TrimSpace(sql), err)
+	}
+}
@@ -0,0 +1,75 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/repository"
+)
+
+// Store returns a store over a database of its own, as the stores commit the transactions they run,
+// along with the pool to set up fixtures and inspect the database with.
+func Store[S any](t testing.TB, newStore func(conn *pgxpool.Pool) S) (S, *pgxpool.Pool) {
+	t.Helper()
+
+	pool := Pool(t)
+	return newStore(pool), pool
+}
+
+// InsertCategory inserts a category, under the given parent unless it is empty, and returns its ID.
+// No event is published for it.
+func InsertCategory(t testing.TB, db repository.DBTX, name, parentID string) string {
+	t.Helper()
+
+	var parent pgtype.UUID
+	if parentID != "" {
+		if err := parent.Scan(parentID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	id, err := repository.New(db).CreateCategory(context.Background(), repository.CreateCategoryParams{Name: name, ParentID: parent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id.String()
+}
+
+// InsertLink inserts a link in the given categories and returns its ID. The URL doubles as its short URL.
+// No event is published for it.
+func InsertLink(t testing.TB, db repository.DBTX, url string, categoryIDs ...string) string {
+	t.Helper()
+
+	id, err := repository.New(db).CreateLink(context.Background(), repository.CreateLinkParams{
+		Url:         url,
+		Title:       "Link title",
+		Description: "A link to " + url,
+		ShortUrl:    url,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, categoryID := range categoryIDs {
+		Exec(t, db, `INSERT INTO link_category_map (link_id, category_id) VALUES ($1, $2)`, id, categoryID)
+	}
+	return id.String()
+}
+
+// PublishedEvents returns the types of the events in the outbox, oldest first.
+func PublishedEvents(t testing.TB, db repository.DBTX) []string {
+	t.Helper()
+
+	rows, err := repository.New(db).ListEventsAfter(context.Background(), repository.ListEventsAfterParams{MaxEvents: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := make([]string, len(rows))
+	for i, row := range rows {
+		list[i] = row.Type
+	}
+	return list
+}
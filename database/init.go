@@ -3,19 +3,79 @@ package database
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/OmprakashD20/refero-api/config"
+	"github.com/OmprakashD20/refero-api/tracing"
 )
 
+// InitDB connects to the primary database, retrying with exponential backoff
+// until it is reachable or the retry timeout expires.
 func InitDB(ctx context.Context, config *config.DBConfig) (*pgxpool.Pool, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.DBHost, config.DBPort, config.DBUser, config.DBPassword, config.DBName, config.SSLMode,
-	)
+	return connect(ctx, config.DSN(), config)
+}
+
+// InitReplicaDB connects to the read replica, if one is configured.
+// It returns a nil pool otherwise.
+func InitReplicaDB(ctx context.Context, config *config.DBConfig) (*pgxpool.Pool, error) {
+	if config.ReplicaURL == "" {
+		return nil, nil
+	}
+
+	return connect(ctx, config.ReplicaURL, config)
+}
+
+func connect(ctx context.Context, dsn string, config *config.DBConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database config: %w", err)
+	}
+
+	pool := config.Pool
+	poolConfig.MaxConns = pool.MaxConns
+	poolConfig.MinConns = pool.MinConns
+	poolConfig.MaxConnLifetime = time.Duration(pool.MaxConnLifetime)
+	poolConfig.MaxConnIdleTime = time.Duration(pool.MaxConnIdleTime)
+	poolConfig.HealthCheckPeriod = time.Duration(pool.HealthCheckPeriod)
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+	if pool.StatementTimeout > 0 {
+		timeout := time.Duration(pool.StatementTimeout).Milliseconds()
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(timeout, 10)
+	}
+
+	retry := config.Retry
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(retry.Timeout))
+	defer cancel()
+
+	delay := time.Duration(retry.InitialDelay)
+	for attempt := 1; ; attempt++ {
+		conn, err := ping(ctx, poolConfig)
+		if err == nil {
+			return conn, nil
+		}
+
+		// Full jitter keeps replicas that start together from retrying in lockstep
+		wait := rand.N(delay) + time.Millisecond
+		slog.WarnContext(ctx, "database is not reachable, retrying",
+			"attempt", attempt, "retry_in", wait.Round(time.Millisecond), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("giving up connecting to the database after %d attempts: %w", attempt, err)
+		case <-time.After(wait):
+		}
+
+		delay = min(delay*2, time.Duration(retry.MaxDelay))
+	}
+}
 
-	conn, err := pgxpool.New(ctx, dsn)
+func ping(ctx context.Context, poolConfig *pgxpool.Config) (*pgxpool.Pool, error) {
+	conn, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
 	}
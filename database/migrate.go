@@ -0,0 +1,415 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey identifies the advisory lock held while migrations run,
+// so that concurrent runs (e.g. several replicas starting at once) are serialized.
+const migrationLockKey int64 = 0x72656665726f // "refero"
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+var (
+	ErrMigrationChecksum = errors.New("applied migration has been modified")
+	ErrMigrationMissing  = errors.New("applied migration is missing from the binary")
+	ErrMigrationNoDown   = errors.New("migration has no down script")
+	ErrUnknownVersion    = errors.New("unknown migration version")
+)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 of the up script
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool // The up script differs from the one that was applied
+}
+
+type Migrator struct {
+	conn       *pgxpool.Pool
+	migrations []Migration
+}
+
+type appliedMigration struct {
+	version   int64
+	checksum  string
+	appliedAt time.Time
+}
+
+// NewMigrator creates a migrator for the migrations embedded in the binary.
+func NewMigrator(conn *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{conn: conn, migrations: migrations}, nil
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			sum := sha256.Sum256(data)
+			m.Up = string(data)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Latest returns the version of the newest embedded migration.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations and returns how many were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.Goto(ctx, m.Latest())
+}
+
+// Down reverts the given number of most recently applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	var reverted int
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Goto migrates up or down until exactly the migrations up to the given version are applied.
+// It returns how many migrations were applied or reverted.
+func (m *Migrator) Goto(ctx context.Context, version int64) (int, error) {
+	if version != 0 && !m.known(version) {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	var changed int
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		// Revert newer migrations, newest first
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			changed++
+		}
+
+		// Apply pending migrations, oldest first
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok || migration.Version > version {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			changed++
+		}
+
+		return nil
+	})
+
+	return changed, err
+}
+
+// Force records the migrations up to the given version as applied, and the newer ones as not applied,
+// without running any of them. It is meant for adopting databases that were migrated by hand
+// and for recovering from a failed or modified migration.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(txn pgx.Tx) error {
+			if _, err := txn.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+				return err
+			}
+
+			for _, migration := range m.migrations {
+				if migration.Version > version {
+					break
+				}
+				if err := recordMigration(ctx, txn, migration); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// Status lists every embedded migration along with whether it has been applied.
+// It does not wait for the migration lock, so it can be used while migrations are running.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.conn.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	applied := make(map[int64]appliedMigration)
+
+	// Nothing has been applied if the table has not been created yet
+	var exists bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		if applied, err = m.applied(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &record.appliedAt
+			statuses[i].Modified = record.checksum != migration.Checksum
+		}
+	}
+
+	return statuses, nil
+}
+
+// Pending returns how many embedded migrations have not been applied yet.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending int
+	for _, status := range statuses {
+		if !status.Applied {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+func (m *Migrator) known(version int64) bool {
+	return slices.ContainsFunc(m.migrations, func(migration Migration) bool {
+		return migration.Version == version
+	})
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.conn.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// Waiting for the lock and running migrations may take longer than the statement timeout of the pool.
+	// The connection is reset before it is returned to the pool.
+	if _, err := conn.Exec(ctx, `SET statement_timeout = 0`); err != nil {
+		return fmt.Errorf("disable statement timeout: %w", err)
+	}
+	defer conn.Exec(context.Background(), `RESET statement_timeout`)
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT now()
+		)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.Query(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var record appliedMigration
+		if err := rows.Scan(&record.version, &record.checksum, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[record.version] = record
+	}
+
+	return applied, rows.Err()
+}
+
+// verify loads the applied migrations and makes sure none of them were modified or removed since.
+func (m *Migrator) verify(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for version, record := range applied {
+		idx := slices.IndexFunc(m.migrations, func(migration Migration) bool {
+			return migration.Version == version
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: version %d", ErrMigrationMissing, version)
+		}
+
+		migration := m.migrations[idx]
+		if record.checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMigrationChecksum, migration.Version, migration.Name)
+		}
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, migration Migration) error {
+	err := pgx.BeginFunc(ctx, conn, func(txn pgx.Tx) error {
+		if _, err := txn.Exec(ctx, migration.Up); err != nil {
+			return err
+		}
+		return recordMigration(ctx, txn, migration)
+	})
+	if err != nil {
+		return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *pgxpool.Conn, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("%w: %d_%s", ErrMigrationNoDown, migration.Version, migration.Name)
+	}
+
+	err := pgx.BeginFunc(ctx, conn, func(txn pgx.Tx) error {
+		if _, err := txn.Exec(ctx, migration.Down); err != nil {
+			return err
+		}
+		_, err := txn.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
+
+func recordMigration(ctx context.Context, txn pgx.Tx, migration Migration) error {
+	_, err := txn.Exec(ctx,
+		`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		migration.Version, migration.Name, migration.Checksum,
+	)
+	return err
+}
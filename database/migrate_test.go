@@ -0,0 +1,119 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/000002_links.up.sql":     {Data: []byte("CREATE TABLE links ();")},
+		"migrations/000001_init.up.sql":      {Data: []byte("CREATE TABLE category ();")},
+		"migrations/000001_init.down.sql":    {Data: []byte("DROP TABLE category;")},
+		"migrations/000010_indexes.up.sql":   {Data: []byte("CREATE INDEX links_url_idx ON links (url);")},
+		"migrations/000010_indexes.down.sql": {Data: []byte("DROP INDEX links_url_idx;")},
+		"migrations/README.md":               {Data: []byte("Not a migration")},
+		"migrations/000003_draft.sql":        {Data: []byte("Neither up nor down")},
+		"migrations/000001_init.up.sql.bak":  {Data: []byte("A backup")},
+		"migrations/nested/000004_x.up.sql":  {Data: []byte("Not in the directory")},
+	}
+
+	migrations, err := loadMigrations(fsys, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		version int64
+		name    string
+		up      string
+		down    string
+	}{
+		{1, "init", "CREATE TABLE category ();", "DROP TABLE category;"},
+		{2, "links", "CREATE TABLE links ();", ""},
+		{10, "indexes", "CREATE INDEX links_url_idx ON links (url);", "DROP INDEX links_url_idx;"},
+	}
+	if len(migrations) != len(want) {
+		t.Fatalf("got %d migrations, want %d: %+v", len(migrations), len(want), migrations)
+	}
+	for i, w := range want {
+		m := migrations[i]
+		sum := sha256.Sum256([]byte(w.up))
+		if m.Version != w.version || m.Name != w.name || m.Up != w.up || m.Down != w.down || m.Checksum != hex.EncodeToString(sum[:]) {
+			t.Errorf("migration %d: got %+v, want %+v", i, m, w)
+		}
+	}
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string // Substring of the error
+	}{
+		{
+			name: "conflicting names",
+			fsys: fstest.MapFS{
+				"migrations/000001_init.up.sql":      {Data: []byte("CREATE TABLE category ();")},
+				"migrations/000001_initial.down.sql": {Data: []byte("DROP TABLE category;")},
+			},
+			want: `conflicting names "init" and "initial"`,
+		},
+		{
+			name: "missing up script",
+			fsys: fstest.MapFS{
+				"migrations/000001_init.up.sql":    {Data: []byte("CREATE TABLE category ();")},
+				"migrations/000002_links.down.sql": {Data: []byte("DROP TABLE links;")},
+			},
+			want: "migration 2_links has no up script",
+		},
+		{
+			name: "empty up script",
+			fsys: fstest.MapFS{
+				"migrations/000001_init.up.sql":   {Data: []byte("")},
+				"migrations/000001_init.down.sql": {Data: []byte("DROP TABLE category;")},
+			},
+			want: "migration 1_init has no up script",
+		},
+		{
+			name: "version out of range",
+			fsys: fstest.MapFS{
+				"migrations/99999999999999999999_init.up.sql": {Data: []byte("CREATE TABLE category ();")},
+			},
+			want: "value out of range",
+		},
+		{
+			name: "missing directory",
+			fsys: fstest.MapFS{},
+			want: "file does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.fsys, "migrations")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %+v, %v, want an error containing %q", migrations, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d_%s: got version %d, want %d", m.Version, m.Name, m.Version, i+1)
+		}
+		if m.Down == "" {
+			t.Errorf("migration %d_%s has no down script", m.Version, m.Name)
+		}
+	}
+}
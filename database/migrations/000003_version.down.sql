@@ -0,0 +1,2 @@
+ALTER TABLE links DROP COLUMN IF EXISTS version;
+ALTER TABLE category DROP COLUMN IF EXISTS version;
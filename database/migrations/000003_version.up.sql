@@ -0,0 +1,3 @@
+-- Row versions used for optimistic concurrency control
+ALTER TABLE category ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE links ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_links_deleted_at;
+DROP INDEX IF EXISTS idx_category_deleted_at;
+
+DELETE FROM links WHERE deleted_at IS NOT NULL;
+DELETE FROM category WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS links_url_key;
+ALTER TABLE links ADD CONSTRAINT links_url_key UNIQUE (url);
+
+DROP INDEX IF EXISTS category_name_key;
+ALTER TABLE category ADD CONSTRAINT category_name_key UNIQUE (name);
+
+ALTER TABLE links DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE category DROP COLUMN IF EXISTS deleted_at;
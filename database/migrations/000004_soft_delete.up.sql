@@ -0,0 +1,13 @@
+-- Trashed rows keep their data until they are purged
+ALTER TABLE category ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE links ADD COLUMN deleted_at TIMESTAMP NULL;
+
+-- Names and URLs only need to be unique among rows that are not in the trash
+ALTER TABLE category DROP CONSTRAINT IF EXISTS category_name_key;
+CREATE UNIQUE INDEX category_name_key ON category(name) WHERE deleted_at IS NULL;
+
+ALTER TABLE links DROP CONSTRAINT IF EXISTS links_url_key;
+CREATE UNIQUE INDEX links_url_key ON links(url) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_category_deleted_at ON category(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_links_deleted_at ON links(deleted_at) WHERE deleted_at IS NOT NULL;
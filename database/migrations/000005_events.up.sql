@@ -0,0 +1,11 @@
+-- Outbox of link and category changes, which clients of the change feed resume from
+CREATE TABLE events (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    source TEXT NOT NULL,
+    category_ids UUID[] NOT NULL DEFAULT '{}',
+    data JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_events_created_at ON events(created_at);
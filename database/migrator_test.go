@@ -0,0 +1,204 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/database"
+	"github.com/OmprakashD20/refero-api/database/dbtest"
+)
+
+// The databases of dbtest are migrated to the latest version when the tests get them.
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func setup(t *testing.T) (*database.Migrator, *pgxpool.Pool) {
+	t.Helper()
+
+	pool := dbtest.Pool(t)
+	migrator, err := database.NewMigrator(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrator, pool
+}
+
+// applied returns the versions recorded as applied, oldest first.
+func applied(t *testing.T, migrator *database.Migrator) []int64 {
+	t.Helper()
+
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var versions []int64
+	for _, status := range statuses {
+		if status.Applied {
+			versions = append(versions, status.Version)
+		}
+	}
+	return versions
+}
+
+func tableExists(t *testing.T, pool *pgxpool.Pool, name string) bool {
+	t.Helper()
+
+	var exists bool
+	if err := pool.QueryRow(context.Background(), `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+		t.Fatal(err)
+	}
+	return exists
+}
+
+func TestMigratorDown(t *testing.T) {
+	migrator, pool := setup(t)
+	ctx := context.Background()
+	latest := migrator.Latest()
+
+	reverted, err := migrator.Down(ctx, 1)
+	if err != nil || reverted != 1 {
+		t.Fatalf("Down(1): got %d, %v, want 1", reverted, err)
+	}
+	if got := applied(t, migrator); len(got) != int(latest-1) || got[len(got)-1] != latest-1 {
+		t.Errorf("applied after Down(1): got %v", got)
+	}
+	if tableExists(t, pool, "webhooks") {
+		t.Error("the webhooks table is left after reverting its migration")
+	}
+	if pending, err := migrator.Pending(ctx); err != nil || pending != 1 {
+		t.Errorf("Pending: got %d, %v, want 1", pending, err)
+	}
+
+	// Reverting more steps than are applied stops at the first migration
+	if reverted, err = migrator.Down(ctx, 100); err != nil || reverted != int(latest-1) {
+		t.Fatalf("Down(100): got %d, %v, want %d", reverted, err, latest-1)
+	}
+	if got := applied(t, migrator); len(got) != 0 {
+		t.Errorf("applied after reverting everything: got %v", got)
+	}
+	if tableExists(t, pool, "links") || tableExists(t, pool, "category") {
+		t.Error("tables are left after reverting every migration")
+	}
+
+	if applied, err := migrator.Up(ctx); err != nil || applied != int(latest) {
+		t.Fatalf("Up: got %d, %v, want %d", applied, err, latest)
+	}
+	if !tableExists(t, pool, "webhooks") {
+		t.Error("the webhooks table is missing after migrating up again")
+	}
+}
+
+func TestMigratorGoto(t *testing.T) {
+	migrator, pool := setup(t)
+	ctx := context.Background()
+	latest := migrator.Latest()
+
+	changed, err := migrator.Goto(ctx, 3)
+	if err != nil || changed != int(latest-3) {
+		t.Fatalf("Goto(3): got %d, %v, want %d", changed, err, latest-3)
+	}
+	if got := applied(t, migrator); len(got) != 3 || got[2] != 3 {
+		t.Errorf("applied after Goto(3): got %v", got)
+	}
+	if tableExists(t, pool, "events") {
+		t.Error("the events table is left after going back to version 3")
+	}
+
+	// Going to the current version changes nothing
+	if changed, err = migrator.Goto(ctx, 3); err != nil || changed != 0 {
+		t.Errorf("Goto(3) again: got %d, %v, want 0", changed, err)
+	}
+
+	if changed, err = migrator.Goto(ctx, latest); err != nil || changed != int(latest-3) {
+		t.Fatalf("Goto(%d): got %d, %v, want %d", latest, changed, err, latest-3)
+	}
+	if !tableExists(t, pool, "events") {
+		t.Error("the events table is missing after migrating to the latest version")
+	}
+
+	if _, err := migrator.Goto(ctx, latest+1); !errors.Is(err, database.ErrUnknownVersion) {
+		t.Errorf("Goto(%d): got %v, want %v", latest+1, err, database.ErrUnknownVersion)
+	}
+}
+
+func TestMigratorForce(t *testing.T) {
+	migrator, pool := setup(t)
+	ctx := context.Background()
+	latest := migrator.Latest()
+
+	if err := migrator.Force(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := applied(t, migrator); len(got) != 2 || got[1] != 2 {
+		t.Errorf("applied after Force(2): got %v", got)
+	}
+	// Forcing only records the versions
+	if !tableExists(t, pool, "webhooks") {
+		t.Error("Force reverted a migration")
+	}
+
+	if err := migrator.Force(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := migrator.Pending(ctx); err != nil || pending != 0 {
+		t.Errorf("Pending after Force(%d): got %d, %v, want 0", latest, pending, err)
+	}
+
+	if err := migrator.Force(ctx, latest+1); !errors.Is(err, database.ErrUnknownVersion) {
+		t.Errorf("Force(%d): got %v, want %v", latest+1, err, database.ErrUnknownVersion)
+	}
+}
+
+func TestMigratorVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper string
+		want   error
+	}{
+		{
+			name:   "checksum mismatch",
+			tamper: `UPDATE schema_migrations SET checksum = 'modified' WHERE version = 1`,
+			want:   database.ErrMigrationChecksum,
+		},
+		{
+			name:   "missing migration",
+			tamper: `INSERT INTO schema_migrations (version, name, checksum) VALUES (999999, 'removed', 'checksum')`,
+			want:   database.ErrMigrationMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrator, pool := setup(t)
+			ctx := context.Background()
+			dbtest.Exec(t, pool, tt.tamper)
+
+			if _, err := migrator.Up(ctx); !errors.Is(err, tt.want) {
+				t.Errorf("Up: got %v, want %v", err, tt.want)
+			}
+			if _, err := migrator.Down(ctx, 1); !errors.Is(err, tt.want) {
+				t.Errorf("Down: got %v, want %v", err, tt.want)
+			}
+			if _, err := migrator.Goto(ctx, 1); !errors.Is(err, tt.want) {
+				t.Errorf("Goto: got %v, want %v", err, tt.want)
+			}
+			if got := applied(t, migrator); len(got) < int(migrator.Latest()) {
+				t.Errorf("a migration was reverted despite the failed verification: %v", got)
+			}
+
+			// Forcing the version recovers from it
+			if err := migrator.Force(ctx, migrator.Latest()); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := migrator.Up(ctx); err != nil {
+				t.Errorf("Up after Force: %v", err)
+			}
+		})
+	}
+}
@@ -1,34 +1,74 @@
 -- Get all categories
 -- name: GetAllCategories :many
-SELECT id, name, parent_id, description, created_at, updated_at FROM category;
+SELECT id, name, parent_id, description, version, created_at, updated_at FROM category
+WHERE deleted_at IS NULL;
 
 -- Get category by ID
 -- name: GetCategoryByID :one
-SELECT id, name, parent_id, description FROM category 
-WHERE id = $1;
+SELECT id, name, parent_id, description, version FROM category 
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- Get category by name
 -- name: GetCategoryByName :one
 SELECT id, name, parent_id, description FROM category 
-WHERE name = $1;
+WHERE name = $1 AND deleted_at IS NULL;
 
 -- Get subcategories of a category
 -- name: GetSubcategories :many
 SELECT id, name, description, created_at, updated_at 
 FROM category 
-WHERE parent_id = $1;
+WHERE parent_id = $1 AND deleted_at IS NULL;
+
+-- Get the categories with the given IDs
+-- name: GetCategoriesByIDs :many
+SELECT id, name, parent_id, description, version, created_at, updated_at FROM category
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL;
+
+-- Get the subcategories of several categories at once
+-- name: GetSubcategoriesOfCategories :many
+SELECT id, name, parent_id, description, version, created_at, updated_at
+FROM category
+WHERE parent_id = ANY(sqlc.arg(parent_ids)::uuid[]) AND deleted_at IS NULL;
+
+-- Lock a category that is not in the trash, so it isn't trashed before the transaction commits
+-- name: LockLiveCategory :one
+SELECT id FROM category
+WHERE id = $1 AND deleted_at IS NULL
+FOR SHARE;
+
+-- Lock the categories with the given IDs that are not in the trash, so they aren't trashed
+-- before the transaction commits
+-- name: LockLiveCategories :many
+SELECT id FROM category
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL
+FOR SHARE;
 
 -- Create a new category
 -- name: CreateCategory :one
 INSERT INTO category (name, parent_id, description) 
 VALUES ($1, $2, $3) RETURNING id;
 
--- Update category details
--- name: UpdateCategory :execrows
-UPDATE category 
-SET name = $1, parent_id = $2, description = $3, updated_at = now() 
-WHERE id = $4;
+-- Update category details, optionally only if it is still at the given version.
+-- Returns the parent the category had before, which a move takes it away from.
+-- name: UpdateCategory :one
+UPDATE category c
+SET name = sqlc.arg(name), parent_id = sqlc.arg(parent_id), description = sqlc.arg(description), version = c.version + 1, updated_at = now() 
+FROM (SELECT p.id, p.parent_id FROM category p WHERE p.id = sqlc.arg(id) FOR UPDATE) old
+WHERE c.id = old.id AND c.deleted_at IS NULL AND (sqlc.narg(version)::integer IS NULL OR c.version = sqlc.narg(version))
+RETURNING old.parent_id;
 
--- Delete category
--- name: DeleteCategory :execrows
-DELETE FROM category WHERE id = $1;
+-- Move a category and its subcategories to the trash, optionally only if it is still at the given version.
+-- Returns the trashed categories.
+-- name: DeleteCategory :many
+WITH RECURSIVE subtree AS (
+    SELECT c.id FROM category c
+    WHERE c.id = sqlc.arg(id) AND c.deleted_at IS NULL AND (sqlc.narg(version)::integer IS NULL OR c.version = sqlc.narg(version))
+    UNION ALL
+    SELECT child.id FROM category child
+    JOIN subtree ON child.parent_id = subtree.id
+    WHERE child.deleted_at IS NULL
+)
+UPDATE category 
+SET deleted_at = now(), version = version + 1 
+WHERE id IN (SELECT id FROM subtree)
+RETURNING id, parent_id;
@@ -0,0 +1,33 @@
+-- Serialize the transactions publishing events until they commit, so that event IDs are
+-- assigned in commit order and clients resuming after an ID can't miss an event.
+-- The lock is global: every mutation publishes an event, so writes commit one at a time,
+-- and write throughput is bounded by the latency of a single transaction.
+-- name: LockEvents :exec
+SELECT pg_advisory_xact_lock(hashtext('refero_events'));
+
+-- Append an event to the outbox
+-- name: InsertEvent :one
+INSERT INTO events (type, source, category_ids, data)
+VALUES (sqlc.arg(type), sqlc.arg(source), sqlc.arg(category_ids)::uuid[], sqlc.arg(data))
+RETURNING id;
+
+-- Get an event by its ID
+-- name: GetEventByID :one
+SELECT id, type, source, category_ids, data FROM events
+WHERE id = sqlc.arg(id);
+
+-- Get the events published after the given ID, oldest first
+-- name: ListEventsAfter :many
+SELECT id, type, source, category_ids, data FROM events
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(max_events);
+
+-- Delete events older than the retention period
+-- name: PurgeEvents :execrows
+DELETE FROM events
+WHERE created_at < now() - sqlc.arg(retention_seconds)::bigint * interval '1 second';
+
+-- Get the ID of the latest event
+-- name: GetLatestEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint FROM events;
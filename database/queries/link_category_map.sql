@@ -3,23 +3,38 @@
 SELECT c.id, c.name, c.description 
 FROM category c 
 JOIN link_category_map lcm ON c.id = lcm.category_id 
-WHERE lcm.link_id = $1;
+WHERE lcm.link_id = $1 AND c.deleted_at IS NULL;
 
 -- Get all links in a category
 -- name: GetLinksForCategory :many
 SELECT l.id, l.url, l.title, l.description, l.short_url, l.created_at, l.updated_at 
 FROM links l 
 JOIN link_category_map lcm ON l.id = lcm.link_id 
-WHERE lcm.category_id = $1;
+WHERE lcm.category_id = $1 AND l.deleted_at IS NULL;
+
+-- Get the categories of several links at once
+-- name: GetCategoriesForLinks :many
+SELECT lcm.link_id, c.id, c.name, c.parent_id, c.description, c.version, c.created_at, c.updated_at
+FROM category c
+JOIN link_category_map lcm ON c.id = lcm.category_id
+WHERE lcm.link_id = ANY(sqlc.arg(link_ids)::uuid[]) AND c.deleted_at IS NULL;
+
+-- Get the links in several categories at once
+-- name: GetLinksForCategories :many
+SELECT lcm.category_id, l.id, l.url, l.title, l.description, l.short_url, l.version, l.created_at, l.updated_at
+FROM links l
+JOIN link_category_map lcm ON l.id = lcm.link_id
+WHERE lcm.category_id = ANY(sqlc.arg(category_ids)::uuid[]) AND l.deleted_at IS NULL;
 
 -- Get all uncategorized links
 -- name: GetUncategorizedLinks :many
 SELECT * 
 FROM links l
-WHERE NOT EXISTS (
+WHERE l.deleted_at IS NULL AND NOT EXISTS (
     SELECT 1 
     FROM link_category_map lcm 
-    WHERE l.id = lcm.link_id
+    JOIN category c ON c.id = lcm.category_id 
+    WHERE l.id = lcm.link_id AND c.deleted_at IS NULL
 );
 
 -- Associate a link with a category
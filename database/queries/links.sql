@@ -1,47 +1,68 @@
 -- Get all public links
 -- name: GetAllLinks :many
-SELECT id, url, title, description, short_url, created_at, updated_at FROM links;
+SELECT id, url, title, description, short_url, version, created_at, updated_at FROM links
+WHERE deleted_at IS NULL;
 
 -- Get link by ID
 -- name: GetLinkByID :one
-SELECT id, url, title, description, short_url, created_at, updated_at 
+SELECT id, url, title, description, short_url, version, created_at, updated_at 
 FROM links 
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- Get link by URL
 -- name: GetLinkByURL :one
-SELECT id, url, title, description, short_url FROM links WHERE url = $1;
+SELECT id, url, title, description, short_url FROM links WHERE url = $1 AND deleted_at IS NULL;
 
 -- Check if link exists by URL
 -- name: CheckIfLinkExistsByURL :one
-SELECT id, true AS exists FROM links l WHERE l.url = $1
+SELECT id, true AS exists FROM links l WHERE l.url = $1 AND l.deleted_at IS NULL
 UNION ALL
-SELECT NULL, false AS exists WHERE NOT EXISTS (SELECT 1 FROM links WHERE links.url = $1)
+SELECT NULL, false AS exists WHERE NOT EXISTS (SELECT 1 FROM links WHERE links.url = $1 AND links.deleted_at IS NULL)
 LIMIT 1;
 
 -- Get link by short URL
 -- name: GetLinkByShortURL :one
 SELECT id, url, title, description, short_url FROM links 
-WHERE short_url = $1;
+WHERE short_url = $1 AND deleted_at IS NULL;
 
 -- Create a new link
 -- name: CreateLink :one
 INSERT INTO links (url, title, description, short_url) 
 VALUES ($1, $2, $3, $4) RETURNING id;
 
--- Update link details
--- name: UpdateLink :execrows
+-- Update link details, optionally only if it is still at the given version
+-- name: UpdateLink :one
 UPDATE links 
-SET title = $1, description = $2, updated_at = now() 
-WHERE id = $3;
+SET title = sqlc.arg(title), url = sqlc.arg(url), description = sqlc.arg(description), version = version + 1, updated_at = now() 
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL AND (sqlc.narg(version)::integer IS NULL OR version = sqlc.narg(version))
+RETURNING short_url;
 
--- Delete link
--- name: DeleteLink :execrows
-DELETE FROM links WHERE id = $1;
+-- Bump the version of a link whose categories changed
+-- name: TouchLink :one
+UPDATE links 
+SET version = version + 1, updated_at = now() 
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING short_url;
 
--- Get paginated links
--- name: GetLinksPaginated :many
-SELECT id, url, title, description, short_url, created_at, updated_at 
-FROM links 
-ORDER BY created_at DESC 
-LIMIT $1 OFFSET $2;
+-- Move link to the trash, optionally only if it is still at the given version
+-- name: DeleteLink :one
+UPDATE links 
+SET deleted_at = now(), version = version + 1 
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL AND (sqlc.narg(version)::integer IS NULL OR version = sqlc.narg(version))
+RETURNING short_url;
+
+-- Get a page of links matching the pattern in their title, URL or description, newest first.
+-- All matching links are returned when no limit is given.
+-- name: SearchLinks :many
+SELECT id, url, title, description, short_url, version, created_at, updated_at
+FROM links
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(pattern)::text IS NULL OR title ILIKE sqlc.narg(pattern) OR url ILIKE sqlc.narg(pattern) OR description ILIKE sqlc.narg(pattern))
+ORDER BY created_at DESC, id
+LIMIT sqlc.narg(max_links)::integer OFFSET sqlc.arg(skip)::integer;
+
+-- Get the links with the given IDs
+-- name: GetLinksByIDs :many
+SELECT id, url, title, description, short_url, version, created_at, updated_at
+FROM links
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL;
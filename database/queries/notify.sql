@@ -0,0 +1,3 @@
+-- Send a notification to the listeners of a channel, delivered once the transaction commits
+-- name: Notify :exec
+SELECT pg_notify(sqlc.arg(channel)::text, sqlc.arg(payload)::text);
@@ -0,0 +1,48 @@
+-- Get all links in the trash
+-- name: GetTrashedLinks :many
+SELECT id, url, title, description, short_url, created_at, updated_at, version, deleted_at 
+FROM links 
+WHERE deleted_at IS NOT NULL 
+ORDER BY deleted_at DESC;
+
+-- Get all categories in the trash
+-- name: GetTrashedCategories :many
+SELECT id, name, parent_id, description, created_at, updated_at, version, deleted_at 
+FROM category 
+WHERE deleted_at IS NOT NULL 
+ORDER BY deleted_at DESC;
+
+-- Restore a link from the trash, along with its category mappings
+-- name: RestoreLink :one
+UPDATE links 
+SET deleted_at = NULL, version = version + 1, updated_at = now() 
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING short_url;
+
+-- Restore a category and the subcategories that were trashed along with it.
+-- The parent category, if any, has to be restored first.
+-- name: RestoreCategory :many
+WITH RECURSIVE subtree AS (
+    SELECT c.id, c.deleted_at FROM category c
+    WHERE c.id = $1 AND c.deleted_at IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM category p WHERE p.id = c.parent_id AND p.deleted_at IS NOT NULL
+    )
+    UNION ALL
+    SELECT child.id, child.deleted_at FROM category child
+    JOIN subtree ON child.parent_id = subtree.id
+    WHERE child.deleted_at = subtree.deleted_at
+)
+UPDATE category 
+SET deleted_at = NULL, version = version + 1, updated_at = now() 
+WHERE id IN (SELECT id FROM subtree)
+RETURNING id, parent_id;
+
+-- Permanently delete links that have been in the trash for longer than the retention period
+-- name: PurgeTrashedLinks :execrows
+DELETE FROM links 
+WHERE deleted_at IS NOT NULL AND deleted_at < now() - sqlc.arg(retention_seconds)::bigint * interval '1 second';
+
+-- Permanently delete categories that have been in the trash for longer than the retention period
+-- name: PurgeTrashedCategories :execrows
+DELETE FROM category 
+WHERE deleted_at IS NOT NULL AND deleted_at < now() - sqlc.arg(retention_seconds)::bigint * interval '1 second';
@@ -0,0 +1,96 @@
+-- Register a webhook
+-- name: CreateWebhook :one
+INSERT INTO webhooks (url, description, secret, event_types)
+VALUES (sqlc.arg(url), sqlc.arg(description), sqlc.arg(secret), sqlc.arg(event_types)::text[])
+RETURNING id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at;
+
+-- Get all webhooks
+-- name: GetAllWebhooks :many
+SELECT id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at FROM webhooks
+ORDER BY created_at;
+
+-- Get a webhook by its ID
+-- name: GetWebhookByID :one
+SELECT id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at FROM webhooks
+WHERE id = sqlc.arg(id);
+
+-- Update a webhook. Enabling it again resets its failures.
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = sqlc.arg(url), description = sqlc.arg(description), event_types = sqlc.arg(event_types)::text[], enabled = sqlc.arg(enabled),
+    failure_count = CASE WHEN sqlc.arg(enabled) AND NOT enabled THEN 0 ELSE failure_count END,
+    disabled_at = CASE WHEN sqlc.arg(enabled) THEN NULL ELSE disabled_at END,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at;
+
+-- Delete a webhook along with its deliveries
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks WHERE id = sqlc.arg(id);
+
+-- Queue a delivery of the event for every enabled webhook subscribed to its type
+-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+SELECT id, sqlc.arg(event_id), sqlc.arg(event_type)::text, sqlc.arg(payload)
+FROM webhooks
+WHERE enabled AND (
+    sqlc.arg(event_type)::text = ANY(event_types)
+    OR split_part(sqlc.arg(event_type)::text, '.', 1) || '.*' = ANY(event_types)
+);
+
+-- Lease the deliveries that are due, so that no other instance attempts them until the lease expires
+-- name: ClaimWebhookDeliveries :many
+WITH due AS (
+    SELECT d.id FROM webhook_deliveries d
+    JOIN webhooks w ON w.id = d.webhook_id
+    WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND w.enabled
+    ORDER BY d.next_attempt_at
+    LIMIT sqlc.arg(max_deliveries)
+    FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = now() + sqlc.arg(lease_seconds)::bigint * interval '1 second'
+FROM due, webhooks w
+WHERE d.id = due.id AND w.id = d.webhook_id
+RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.attempts, d.created_at, w.url, w.secret;
+
+-- Record the outcome of a delivery attempt
+-- name: RecordWebhookAttempt :exec
+UPDATE webhook_deliveries
+SET status = sqlc.arg(status), attempts = attempts + 1, last_attempt_at = now(),
+    next_attempt_at = now() + sqlc.arg(retry_in_seconds)::bigint * interval '1 second',
+    response_status = sqlc.narg(response_status), response_body = sqlc.narg(response_body), error = sqlc.narg(error)
+WHERE id = sqlc.arg(id);
+
+-- Reset the consecutive failures of a webhook after a successful delivery
+-- name: ResetWebhookFailures :exec
+UPDATE webhooks SET failure_count = 0 WHERE id = sqlc.arg(id) AND failure_count > 0;
+
+-- Count a failed delivery attempt, and disable the webhook once it failed too often in a row
+-- name: RecordWebhookFailure :one
+UPDATE webhooks
+SET failure_count = failure_count + 1,
+    enabled = enabled AND failure_count + 1 < sqlc.arg(max_failures)::integer,
+    disabled_at = CASE WHEN enabled AND failure_count + 1 >= sqlc.arg(max_failures)::integer THEN now() ELSE disabled_at END
+WHERE id = sqlc.arg(id)
+RETURNING enabled;
+
+-- Get the latest deliveries of a webhook
+-- name: GetWebhookDeliveries :many
+SELECT id, webhook_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, response_body, error, created_at
+FROM webhook_deliveries
+WHERE webhook_id = sqlc.arg(webhook_id)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(max_deliveries);
+
+-- Queue a delivery of the same event again
+-- name: RedeliverWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+SELECT d.webhook_id, d.event_id, d.event_type, d.payload FROM webhook_deliveries d
+WHERE d.id = sqlc.arg(id) AND d.webhook_id = sqlc.arg(webhook_id)
+RETURNING id, webhook_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, response_body, error, created_at;
+
+-- Delete deliveries that are done and older than the retention period
+-- name: PurgeWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries
+WHERE status <> 'pending' AND created_at < now() - sqlc.arg(retention_seconds)::bigint * interval '1 second';
@@ -6,7 +6,9 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/OmprakashD20/refero-api/metrics"
 	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/tracing"
 )
 
 type Store struct {
@@ -18,7 +20,12 @@ func NewTransactionStore(conn *pgxpool.Pool) *Store {
 	return &Store{conn: conn, db: repository.New(conn)}
 }
 
-func (s *Store) Exec(ctx context.Context, fn func(q *repository.Queries) error) error {
+// Exec runs fn in a transaction, which is committed if fn succeeds and rolled back otherwise.
+// fn is given the context of the transaction's span, so the queries it runs become children of it.
+func (s *Store) Exec(ctx context.Context, fn func(ctx context.Context, q *repository.Queries) error) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "transaction")
+	defer func() { tracing.EndSpan(span, err) }()
+
 	txn, err := s.conn.Begin(ctx)
 	if err != nil {
 		return err
@@ -26,15 +33,16 @@ func (s *Store) Exec(ctx context.Context, fn func(q *repository.Queries) error)
 
 	q := s.db.WithTx(txn)
 
-	err = fn(q)
+	err = fn(ctx, q)
 	if err != nil {
+		metrics.TransactionRollbacks.Inc()
+
 		if rollbackErr := txn.Rollback(ctx); rollbackErr != nil {
-			return fmt.Errorf("txn err: %v, rollback err: %v", err, rollbackErr)
+			return fmt.Errorf("txn err: %w, rollback err: %v", err, rollbackErr)
 		}
 
 		return err
 	}
 
 	return txn.Commit(ctx)
-
 }
@@ -0,0 +1,127 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc fetches the values of several keys at once.
+// Keys missing from the returned map resolve to the zero value of V.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader coalesces the loads issued within a short window into a single call to the batch function,
+// and caches the results for its lifetime. Loaders are meant to live for a single request, so that
+// the cache never outlives the data it was read from.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	results map[K]*result[V]
+	batch   *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	ctx     context.Context
+	keys    []K
+	results []*result[V]
+	timer   *time.Timer
+}
+
+// New creates a loader that waits up to wait for more keys before calling fetch,
+// or calls it right away once maxBatch keys are pending.
+func New[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		results:  make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value of the key, batching it with the other keys loaded in the meantime.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	r, ok := l.results[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.results[key] = r
+		l.enqueue(ctx, key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// enqueue adds the key to the pending batch, starting a new one if needed.
+// It must be called with l.mu held.
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	if l.batch == nil {
+		b := &batch[K, V]{ctx: ctx}
+		b.timer = time.AfterFunc(l.wait, func() { l.dispatch(b) })
+		l.batch = b
+	}
+
+	b := l.batch
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, r)
+
+	if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+		// The batch is full, so there is no point waiting for more keys
+		b.timer.Stop()
+		l.batch = nil
+		go l.run(b)
+	}
+}
+
+// dispatch runs the batch once its window has passed, unless it was already dispatched because it was full.
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.batch != b {
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+func (l *Loader[K, V]) run(b *batch[K, V]) {
+	values, err := l.fetch(b.ctx, b.keys)
+
+	if err != nil {
+		// Forget failed keys, so that a later load tries again
+		l.mu.Lock()
+		for i, key := range b.keys {
+			if l.results[key] == b.results[i] {
+				delete(l.results, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+
+	for i, key := range b.keys {
+		r := b.results[i]
+		if err != nil {
+			r.err = err
+		} else {
+			r.value = values[key]
+		}
+		close(r.done)
+	}
+}
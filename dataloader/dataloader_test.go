@@ -0,0 +1,143 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingFetch records the keys of every call to the batch function.
+type countingFetch struct {
+	mu    sync.Mutex
+	calls [][]int
+	err   error
+}
+
+func (f *countingFetch) fetch(ctx context.Context, keys []int) (map[int]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, slices.Sorted(slices.Values(keys)))
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	values := make(map[int]string, len(keys))
+	for _, key := range keys {
+		if key >= 0 {
+			values[key] = strconv.Itoa(key)
+		}
+	}
+	return values, nil
+}
+
+func (f *countingFetch) batches() [][]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.calls)
+}
+
+type loaded struct {
+	value string
+	err   error
+}
+
+// loadAll loads the keys at the same time and returns what each load returned.
+func loadAll(l *Loader[int, string], keys ...int) []loaded {
+	results := make([]loaded, len(keys))
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i].value, results[i].err = l.Load(context.Background(), key)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func TestLoaderWindow(t *testing.T) {
+	f := &countingFetch{}
+	l := New(f.fetch, 50*time.Millisecond, 0)
+
+	// Loads within the window share a call, and so do loads of the same key
+	results := loadAll(l, 1, 2, 3, 2, -1)
+	want := []loaded{{"1", nil}, {"2", nil}, {"3", nil}, {"2", nil}, {"", nil}}
+	if !slices.Equal(results, want) {
+		t.Errorf("got %v, want %v", results, want)
+	}
+	if got := f.batches(); len(got) != 1 || !slices.Equal(got[0], []int{-1, 1, 2, 3}) {
+		t.Errorf("got batches %v, want [[-1 1 2 3]]", got)
+	}
+
+	// Loaded keys are cached, including the ones which were missing
+	if results := loadAll(l, 3, -1); !slices.Equal(results, []loaded{{"3", nil}, {"", nil}}) {
+		t.Errorf("cached keys: got %v", results)
+	}
+	if got := f.batches(); len(got) != 1 {
+		t.Errorf("cached keys: got batches %v, want no new call", got)
+	}
+
+	// Loads after the window are batched again
+	loadAll(l, 3, 4)
+	if got := f.batches(); len(got) != 2 || !slices.Equal(got[1], []int{4}) {
+		t.Errorf("got batches %v, want a call for key 4", got)
+	}
+}
+
+func TestLoaderMaxBatch(t *testing.T) {
+	f := &countingFetch{}
+	// The window never passes, so only full batches are fetched
+	l := New(f.fetch, time.Hour, 2)
+
+	results := loadAll(l, 1, 2, 3, 4)
+	if want := []loaded{{"1", nil}, {"2", nil}, {"3", nil}, {"4", nil}}; !slices.Equal(results, want) {
+		t.Errorf("got %v, want %v", results, want)
+	}
+
+	got := f.batches()
+	if len(got) != 2 {
+		t.Fatalf("got batches %v, want 2", got)
+	}
+	for _, keys := range got {
+		if len(keys) != 2 {
+			t.Errorf("got batch %v, want 2 keys", keys)
+		}
+	}
+	if keys := slices.Sorted(slices.Values(slices.Concat(got...))); !slices.Equal(keys, []int{1, 2, 3, 4}) {
+		t.Errorf("got keys %v, want each key fetched once", keys)
+	}
+}
+
+func TestLoaderError(t *testing.T) {
+	errFetch := errors.New("connection refused")
+	f := &countingFetch{err: errFetch}
+	l := New(f.fetch, 10*time.Millisecond, 0)
+
+	// Every load of the batch fails with the error
+	for i, r := range loadAll(l, 1, 2) {
+		if !errors.Is(r.err, errFetch) || r.value != "" {
+			t.Errorf("load %d: got %v, want the error", i, r)
+		}
+	}
+
+	// Failed keys are forgotten, so they are fetched again
+	f.mu.Lock()
+	f.err = nil
+	f.mu.Unlock()
+
+	if results := loadAll(l, 1, 2); !slices.Equal(results, []loaded{{"1", nil}, {"2", nil}}) {
+		t.Errorf("after the error: got %v", results)
+	}
+	if got := f.batches(); len(got) != 2 || !slices.Equal(got[1], []int{1, 2}) {
+		t.Errorf("got batches %v, want the keys fetched again", got)
+	}
+}
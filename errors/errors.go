@@ -7,31 +7,92 @@ import (
 )
 
 var (
-	ErrInvalidPayload = errors.New("invalid data")
+	ErrInvalidPayload       = New("invalid_payload", "invalid data")
+	ErrInvalidPatch         = New("invalid_patch", "invalid patch document")
+	ErrUnsupportedMediaType = New("unsupported_media_type", "unsupported media type")
+	ErrPreconditionFailed   = New("precondition_failed", "resource has been modified")
+	ErrValidationFailed     = New("validation_failed", "validation failed")
+	ErrRouteNotFound        = New("route_not_found", "route not found")
+	ErrRateLimited          = New("rate_limited", "too many requests, try again later")
+	ErrUnauthenticated      = New("unauthenticated", "missing or invalid credentials")
 )
 
 // Category
 var (
-	ErrCategoryNotFound       = errors.New("category not found")
-	ErrCategoryExists         = errors.New("category already exists")
-	ErrFailedToCreateCategory = errors.New("failed to create category")
-	ErrFailedToUpdateCategory = errors.New("failed to update category")
-	ErrFailedToDeleteCategory = errors.New("failed to delete category")
+	ErrCategoryNotFound       = New("category_not_found", "category not found")
+	ErrCategoryExists         = New("category_exists", "category already exists")
+	ErrFailedToCreateCategory = New("category_create_failed", "failed to create category")
+	ErrFailedToUpdateCategory = New("category_update_failed", "failed to update category")
+	ErrFailedToDeleteCategory = New("category_delete_failed", "failed to delete category")
 )
 
 // Link
 var (
-	ErrLinkNotFound       = errors.New("link not found")
-	ErrLinkExists         = errors.New("link already exists")
-	ErrFailedToCreateLink = errors.New("failed to create link")
-	ErrFailedToUpdateLink = errors.New("failed to update link")
-	ErrFailedToDeleteLink = errors.New("failed to delete link")
+	ErrLinkNotFound       = New("link_not_found", "link not found")
+	ErrLinkExists         = New("link_exists", "link already exists")
+	ErrFailedToCreateLink = New("link_create_failed", "failed to create link")
+	ErrFailedToUpdateLink = New("link_update_failed", "failed to update link")
+	ErrFailedToDeleteLink = New("link_delete_failed", "failed to delete link")
 )
 
+// Trash
+var (
+	ErrTrashItemNotFound = New("trash_item_not_found", "item not found in trash")
+	ErrFailedToRestore   = New("trash_restore_failed", "failed to restore item")
+)
+
+// Webhook
+var (
+	ErrWebhookNotFound         = New("webhook_not_found", "webhook not found")
+	ErrWebhookDeliveryNotFound = New("webhook_delivery_not_found", "webhook delivery not found")
+	ErrFailedToCreateWebhook   = New("webhook_create_failed", "failed to create webhook")
+	ErrFailedToUpdateWebhook   = New("webhook_update_failed", "failed to update webhook")
+	ErrFailedToDeleteWebhook   = New("webhook_delete_failed", "failed to delete webhook")
+	ErrFailedToRedeliver       = New("webhook_redeliver_failed", "failed to redeliver webhook")
+)
+
+// Events
+var (
+	ErrInvalidLastEventID = New("invalid_last_event_id", "Last-Event-ID must be an event ID")
+	ErrFeedUnavailable    = New("feed_unavailable", "change feed is shutting down, reconnect later")
+	ErrSubscriberTooSlow  = New("subscriber_too_slow", "events were not consumed fast enough, resume from the last event")
+)
+
+// CodedError is a sentinel error with a stable, machine-readable code
+// that clients can branch on instead of the message.
+type CodedError struct {
+	code string
+	msg  string
+}
+
+// New creates a sentinel error with the given code and message.
+func New(code, msg string) error {
+	return &CodedError{code, msg}
+}
+
+func (e *CodedError) Error() string {
+	return e.msg
+}
+
+// Code returns the machine-readable code of the error.
+func (e *CodedError) Code() string {
+	return e.code
+}
+
+// Code returns the code of the first CodedError in the error's chain, if any.
+func Code(err error) (string, bool) {
+	var ce *CodedError
+	if errors.As(err, &ce) {
+		return ce.code, true
+	}
+	return "", false
+}
+
 // IsErrNoRows checks if the provided error is a pgx.ErrNoRows error.
+// Any other error is translated with TranslatePgError.
 func IsErrNoRows[T any](err error, value T) (T, error) {
 	if errors.Is(err, pgx.ErrNoRows) {
 		return value, nil
 	}
-	return value, err
+	return value, TranslatePgError(err)
 }
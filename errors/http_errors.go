@@ -1,15 +1,19 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // HTTPError represents an HTTP error with a status code, a message, and an optional cause.
 type HTTPError struct {
-	ErrorMsg   string // Error message
-	StatusCode int    // HTTP status code
-	Cause      error  // Underlying error, if any
+	ErrorMsg   string       // Error message
+	StatusCode int          // HTTP status code
+	Code       string       // Machine-readable error code, if any
+	Fields     []FieldError // Field-level validation errors, if any
+	Cause      error        // Underlying error, if any
 }
 
 // Option is a functional option for configuring an HTTPError.
@@ -24,6 +28,27 @@ func (e *HTTPError) Error() string {
 	return fmt.Sprintf("[%d %s]: %s", e.StatusCode, http.StatusText(e.StatusCode), e.ErrorMsg)
 }
 
+// Unwrap returns the underlying cause of the error.
+func (e *HTTPError) Unwrap() error {
+	return e.Cause
+}
+
+// Resolve returns the client error (4xx) found in the cause chain of a server error, if any.
+// This keeps errors translated by the stores from being masked by handlers that wrap
+// every store failure as an internal server error.
+func (e *HTTPError) Resolve() *HTTPError {
+	if e.StatusCode < http.StatusInternalServerError || e.Cause == nil {
+		return e
+	}
+
+	var inner *HTTPError
+	if errors.As(e.Cause, &inner) {
+		return inner.Resolve()
+	}
+
+	return e
+}
+
 // WithCause returns an Option that sets the cause of an HTTPError.
 func WithCause(err error) Option {
 	return func(e *HTTPError) {
@@ -32,12 +57,15 @@ func WithCause(err error) Option {
 }
 
 // WithError returns an Option that sets the error message for an HTTPError.
-// The provided value can be a string or an error.
+// The provided value can be a string or an error. If the error carries a code, it is set as well.
 func WithError(err any) Option {
 	return func(e *HTTPError) {
 		switch v := err.(type) {
 		case error:
 			e.ErrorMsg = v.Error()
+			if code, ok := Code(v); ok {
+				e.Code = code
+			}
 		case string:
 			e.ErrorMsg = v
 		default:
@@ -46,6 +74,29 @@ func WithError(err any) Option {
 	}
 }
 
+// WithCode returns an Option that sets the machine-readable code of an HTTPError.
+func WithCode(code string) Option {
+	return func(e *HTTPError) {
+		e.Code = code
+	}
+}
+
+// WithFields returns an Option that attaches field-level validation errors to an HTTPError.
+func WithFields(fields ...FieldError) Option {
+	return func(e *HTTPError) {
+		e.Fields = append(e.Fields, fields...)
+	}
+}
+
+// ErrorCode returns the machine-readable code of the error.
+// Errors without an explicit code fall back to one derived from the status code.
+func (e *HTTPError) ErrorCode() string {
+	if e.Code != "" {
+		return e.Code
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(e.StatusCode)), " ", "_")
+}
+
 // WithStatus returns an Option that sets the HTTP status code for an HTTPError.
 func WithStatus(statusCode int) Option {
 	return func(e *HTTPError) {
@@ -99,6 +150,22 @@ func Validation(err any, opts ...Option) *HTTPError {
 	return NewHTTPError(WithStatus(http.StatusUnprocessableEntity), WithError(err), WithOptions(opts...))
 }
 
+func UnsupportedMediaType(err any, opts ...Option) *HTTPError {
+	return NewHTTPError(WithStatus(http.StatusUnsupportedMediaType), WithError(err), WithOptions(opts...))
+}
+
+func PreconditionFailed(err any, opts ...Option) *HTTPError {
+	return NewHTTPError(WithStatus(http.StatusPreconditionFailed), WithError(err), WithOptions(opts...))
+}
+
+func TooManyRequests(err any, opts ...Option) *HTTPError {
+	return NewHTTPError(WithStatus(http.StatusTooManyRequests), WithError(err), WithOptions(opts...))
+}
+
+func ServiceUnavailable(err any, opts ...Option) *HTTPError {
+	return NewHTTPError(WithStatus(http.StatusServiceUnavailable), WithError(err), WithOptions(opts...))
+}
+
 func InternalServerError(opts ...Option) *HTTPError {
 	return NewHTTPError(WithOptions(opts...))
 }
@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes that are caused by client input
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgInvalidTextRepr     = "22P02"
+)
+
+var (
+	ErrDuplicateValue     = New("duplicate_value", "value already exists")
+	ErrInvalidReference   = New("invalid_reference", "referenced resource does not exist")
+	ErrConstraintViolated = New("constraint_violation", "value violates a constraint")
+	ErrInvalidValue       = New("invalid_value", "value has an invalid format")
+)
+
+// constraintFields maps constraint names to the payload field that violates them.
+var constraintFields = map[string]string{
+	"category_name_key":                         "name",
+	"category_parent_id_fkey":                   "parentId",
+	"links_url_key":                             "url",
+	"links_short_url_key":                       "shortUrl",
+	"link_category_map_link_id_fkey":            "linkId",
+	"link_category_map_category_id_fkey":        "categoryIds",
+	"link_category_map_link_id_category_id_key": "categoryIds",
+}
+
+// detailKeyPattern extracts the column from details like `Key (url)=(https://...) already exists.`
+var detailKeyPattern = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// TranslatePgError converts Postgres constraint violations into client errors naming the offending field.
+// Any other error is returned unchanged.
+func TranslatePgError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	field := pgErrorField(pgErr)
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return Conflict(ErrDuplicateValue,
+			WithError(fmt.Sprintf("%s already exists", field)),
+			WithFields(FieldError{Field: field, Constraint: "unique", Message: fmt.Sprintf("%s must be unique", field)}),
+			WithCause(err),
+		)
+	case pgForeignKeyViolation:
+		return InvalidReference(field, WithCause(err))
+	case pgCheckViolation:
+		return Validation(ErrConstraintViolated,
+			WithError(fmt.Sprintf("%s violates a constraint", field)),
+			WithFields(FieldError{Field: field, Constraint: "check", Message: fmt.Sprintf("%s violates the %s constraint", field, pgErr.ConstraintName)}),
+			WithCause(err),
+		)
+	case pgInvalidTextRepr:
+		return Validation(ErrInvalidValue, WithCause(err))
+	}
+
+	return err
+}
+
+// InvalidReference reports that the field references a resource that does not exist.
+func InvalidReference(field string, opts ...Option) *HTTPError {
+	return Validation(ErrInvalidReference,
+		WithError(fmt.Sprintf("%s references a resource that does not exist", field)),
+		WithFields(FieldError{Field: field, Constraint: "exists", Message: fmt.Sprintf("%s must reference an existing resource", field)}),
+		WithOptions(opts...),
+	)
+}
+
+func pgErrorField(pgErr *pgconn.PgError) string {
+	if field, ok := constraintFields[pgErr.ConstraintName]; ok {
+		return field
+	}
+	if pgErr.ColumnName != "" {
+		return pgErr.ColumnName
+	}
+	if match := detailKeyPattern.FindStringSubmatch(pgErr.Detail); match != nil {
+		return match[1]
+	}
+	if pgErr.ConstraintName != "" {
+		return pgErr.ConstraintName
+	}
+	return "value"
+}
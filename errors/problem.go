@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"net/http"
+)
+
+const (
+	// ProblemContentType is the media type of RFC 7807 problem details responses.
+	ProblemContentType = "application/problem+json"
+
+	// ProblemTypePrefix prefixes the error code to build the problem type URI.
+	ProblemTypePrefix = "urn:refero:problem:"
+)
+
+// FieldError describes a single field that failed validation.
+type FieldError struct {
+	Field      string `json:"field"`           // JSON name of the field
+	Constraint string `json:"constraint"`      // Failing validation tag, e.g. "required" or "min"
+	Param      string `json:"param,omitempty"` // Constraint parameter, e.g. "4" for "min=4"
+	Message    string `json:"message"`         // Human readable message
+}
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// Problem converts the HTTPError to a problem details object for the given request path.
+func (e *HTTPError) Problem(instance string) Problem {
+	code := e.ErrorCode()
+
+	return Problem{
+		Type:     ProblemTypePrefix + code,
+		Title:    http.StatusText(e.StatusCode),
+		Status:   e.StatusCode,
+		Detail:   e.ErrorMsg,
+		Instance: instance,
+		Code:     code,
+		Errors:   e.Fields,
+	}
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/utils"
+)
+
+// Channel is the Postgres notification channel all events are sent on.
+const Channel = "refero_events"
+
+const (
+	LinkCreated     = "link.created"
+	LinkUpdated     = "link.updated"
+	LinkDeleted     = "link.deleted"
+	CategoryCreated = "category.created"
+	CategoryUpdated = "category.updated"
+	CategoryDeleted = "category.deleted"
+)
+
+// Types lists every event type.
+var Types = []string{LinkCreated, LinkUpdated, LinkDeleted, CategoryCreated, CategoryUpdated, CategoryDeleted}
+
+// Source identifies the instance that published an event.
+var Source = newSource()
+
+// Envelope is an event of the outbox. Data holds the event specific payload,
+// which is decoded with Decode by the subscribers of the event type.
+type Envelope struct {
+	ID         int64           `json:"id"` // Position in the outbox, increasing in commit order
+	Type       string          `json:"type"`
+	Source     string          `json:"source"`
+	Categories []string        `json:"categories,omitempty"` // Categories the event is relevant to
+	Data       json.RawMessage `json:"data"`
+}
+
+// notification is the payload of the notifications. It only identifies the event, which listeners
+// read from the outbox, as Postgres rejects payloads of 8000 bytes or more and events can be larger.
+type notification struct {
+	ID     int64  `json:"id"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+}
+
+type LinkChanged struct {
+	ID       string `json:"id"`
+	ShortURL string `json:"shortUrl"`
+}
+
+type CategoryChanged struct {
+	ID string `json:"id"`
+}
+
+// Publish appends the event to the outbox, queues its webhook deliveries and notifies the listeners
+// of every instance. It must run in a transaction, so the event is only stored and delivered once it commits.
+func Publish(ctx context.Context, q *repository.Queries, eventType string, data any, categoryIDs []string) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if err := q.LockEvents(ctx); err != nil {
+		return err
+	}
+
+	categories := make([]pgtype.UUID, len(categoryIDs))
+	for i, id := range categoryIDs {
+		categories[i] = utils.ToPgUUID(id)
+	}
+
+	id, err := q.InsertEvent(ctx, repository.InsertEventParams{
+		Type:        eventType,
+		Source:      Source,
+		CategoryIds: categories,
+		Data:        raw,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.EnqueueWebhookDeliveries(ctx, repository.EnqueueWebhookDeliveriesParams{EventID: id, EventType: eventType, Payload: raw})
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(notification{ID: id, Type: eventType, Source: Source})
+	if err != nil {
+		return err
+	}
+
+	return q.Notify(ctx, repository.NotifyParams{Channel: Channel, Payload: string(payload)})
+}
+
+// Decode unmarshals the data of the event.
+func Decode[T any](event Envelope) (T, error) {
+	var data T
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return data, fmt.Errorf("decode %s event: %w", event.Type, err)
+	}
+	return data, nil
+}
+
+func newSource() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
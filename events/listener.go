@@ -0,0 +1,173 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/OmprakashD20/refero-api/repository"
+)
+
+const (
+	minReconnectDelay = 500 * time.Millisecond
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Handler processes an event received by the listener.
+type Handler func(ctx context.Context, event Envelope)
+
+// Listener holds a dedicated connection subscribed to the events channel and dispatches
+// every notification to the handlers of its type. It reconnects whenever the connection is lost.
+type Listener struct {
+	connConfig *pgx.ConnConfig
+
+	mu          sync.RWMutex
+	handlers    map[string][]Handler
+	onReconnect []func(ctx context.Context)
+
+	connected atomic.Bool
+}
+
+// NewListener creates a listener connecting with the given config, which should point at the primary
+// since notifications are not sent to replicas.
+func NewListener(connConfig *pgx.ConnConfig) *Listener {
+	return &Listener{connConfig: connConfig, handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers a handler for the events of the given type.
+func (l *Listener) Subscribe(eventType string, handler Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.handlers[eventType] = append(l.handlers[eventType], handler)
+}
+
+// OnReconnect registers a function called once the connection was lost and is listening again.
+// Events sent in between were missed, so subscribers should resync any state derived from them.
+func (l *Listener) OnReconnect(fn func(ctx context.Context)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.onReconnect = append(l.onReconnect, fn)
+}
+
+// Run listens for events until the context is cancelled.
+func (l *Listener) Run(ctx context.Context) {
+	delay := minReconnectDelay
+
+	var listened bool
+	for {
+		start := time.Now()
+		ok, err := l.listen(ctx, listened)
+		listened = listened || ok
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Start over with a short delay if the connection was up for a while
+		if time.Since(start) > maxReconnectDelay {
+			delay = minReconnectDelay
+		}
+		slog.WarnContext(ctx, "event listener disconnected, reconnecting", "retry_in", delay, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// listen subscribes to the channel and dispatches notifications until the connection fails.
+// It reports whether the subscription was established.
+func (l *Listener) listen(ctx context.Context, reconnected bool) (bool, error) {
+	conn, err := pgx.ConnectConfig(ctx, l.connConfig)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize()); err != nil {
+		return false, err
+	}
+
+	l.connected.Store(true)
+	defer l.connected.Store(false)
+
+	slog.InfoContext(ctx, "listening for events", "channel", Channel)
+
+	// Only now that notifications are queued again, the subscribers can catch up on what they missed
+	if reconnected {
+		l.mu.RLock()
+		for _, fn := range l.onReconnect {
+			fn(ctx)
+		}
+		l.mu.RUnlock()
+	}
+
+	q := repository.New(conn)
+	for {
+		msg, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		var n notification
+		if err := json.Unmarshal([]byte(msg.Payload), &n); err != nil {
+			slog.WarnContext(ctx, "ignoring malformed event", "payload", msg.Payload, "error", err)
+			continue
+		}
+
+		if err := l.dispatch(ctx, q, n); err != nil {
+			return true, err
+		}
+	}
+}
+
+// dispatch reads the event from the outbox and passes it to the handlers of its type.
+func (l *Listener) dispatch(ctx context.Context, q *repository.Queries, n notification) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	handlers := l.handlers[n.Type]
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	row, err := q.GetEventByID(ctx, n.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Purged from the outbox already
+		slog.WarnContext(ctx, "ignoring event missing from the outbox", "id", n.ID, "type", n.Type)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	categories := make([]string, len(row.CategoryIds))
+	for i, id := range row.CategoryIds {
+		categories[i] = id.String()
+	}
+	event := Envelope{ID: row.ID, Type: row.Type, Source: row.Source, Categories: categories, Data: row.Data}
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+	return nil
+}
+
+// Check fails while the listener is not connected, as the local caches may be stale meanwhile.
+func (l *Listener) Check(ctx context.Context) (any, error) {
+	if !l.connected.Load() {
+		return nil, fmt.Errorf("event listener is not connected")
+	}
+	return nil, nil
+}
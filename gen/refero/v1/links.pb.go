@@ -0,0 +1,840 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: refero/v1/links.proto
+
+package referov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CategoryIds   []string               `protobuf:"bytes,4,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLinkRequest) Reset() {
+	*x = CreateLinkRequest{}
+	mi := &file_refero_v1_links_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLinkRequest) ProtoMessage() {}
+
+func (x *CreateLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLinkRequest.ProtoReflect.Descriptor instead.
+func (*CreateLinkRequest) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateLinkRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateLinkRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateLinkRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateLinkRequest) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
+}
+
+type CreateLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Link          *Link                  `protobuf:"bytes,1,opt,name=link,proto3" json:"link,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLinkResponse) Reset() {
+	*x = CreateLinkResponse{}
+	mi := &file_refero_v1_links_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLinkResponse) ProtoMessage() {}
+
+func (x *CreateLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLinkResponse.ProtoReflect.Descriptor instead.
+func (*CreateLinkResponse) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateLinkResponse) GetLink() *Link {
+	if x != nil {
+		return x.Link
+	}
+	return nil
+}
+
+type GetLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkRequest) Reset() {
+	*x = GetLinkRequest{}
+	mi := &file_refero_v1_links_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkRequest) ProtoMessage() {}
+
+func (x *GetLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkRequest) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetLinkRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Link          *Link                  `protobuf:"bytes,1,opt,name=link,proto3" json:"link,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkResponse) Reset() {
+	*x = GetLinkResponse{}
+	mi := &file_refero_v1_links_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkResponse) ProtoMessage() {}
+
+func (x *GetLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkResponse.ProtoReflect.Descriptor instead.
+func (*GetLinkResponse) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetLinkResponse) GetLink() *Link {
+	if x != nil {
+		return x.Link
+	}
+	return nil
+}
+
+type ResolveShortURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveShortURLRequest) Reset() {
+	*x = ResolveShortURLRequest{}
+	mi := &file_refero_v1_links_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveShortURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveShortURLRequest) ProtoMessage() {}
+
+func (x *ResolveShortURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveShortURLRequest.ProtoReflect.Descriptor instead.
+func (*ResolveShortURLRequest) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResolveShortURLRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+type ResolveShortURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Link          *Link                  `protobuf:"bytes,1,opt,name=link,proto3" json:"link,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveShortURLResponse) Reset() {
+	*x = ResolveShortURLResponse{}
+	mi := &file_refero_v1_links_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveShortURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveShortURLResponse) ProtoMessage() {}
+
+func (x *ResolveShortURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveShortURLResponse.ProtoReflect.Descriptor instead.
+func (*ResolveShortURLResponse) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResolveShortURLResponse) GetLink() *Link {
+	if x != nil {
+		return x.Link
+	}
+	return nil
+}
+
+type ListLinksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinksRequest) Reset() {
+	*x = ListLinksRequest{}
+	mi := &file_refero_v1_links_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksRequest) ProtoMessage() {}
+
+func (x *ListLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksRequest.ProtoReflect.Descriptor instead.
+func (*ListLinksRequest) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{6}
+}
+
+type ListLinksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Links         []*Link                `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinksResponse) Reset() {
+	*x = ListLinksResponse{}
+	mi := &file_refero_v1_links_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksResponse) ProtoMessage() {}
+
+func (x *ListLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksResponse.ProtoReflect.Descriptor instead.
+func (*ListLinksResponse) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListLinksResponse) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type ListLinkCategoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinkCategoriesRequest) Reset() {
+	*x = ListLinkCategoriesRequest{}
+	mi := &file_refero_v1_links_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinkCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinkCategoriesRequest) ProtoMessage() {}
+
+func (x *ListLinkCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinkCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListLinkCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListLinkCategoriesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListLinkCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinkCategoriesResponse) Reset() {
+	*x = ListLinkCategoriesResponse{}
+	mi := &file_refero_v1_links_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinkCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinkCategoriesResponse) ProtoMessage() {}
+
+func (x *ListLinkCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinkCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListLinkCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListLinkCategoriesResponse) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type UpdateLinkRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url         string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Title       string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	CategoryIds []string               `protobuf:"bytes,5,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	// If set, the update fails with ABORTED unless the link is still at this version.
+	Version       *int32 `protobuf:"varint,6,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLinkRequest) Reset() {
+	*x = UpdateLinkRequest{}
+	mi := &file_refero_v1_links_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLinkRequest) ProtoMessage() {}
+
+func (x *UpdateLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLinkRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLinkRequest) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateLinkRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateLinkRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *UpdateLinkRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateLinkRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateLinkRequest) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
+}
+
+func (x *UpdateLinkRequest) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type UpdateLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Link          *Link                  `protobuf:"bytes,1,opt,name=link,proto3" json:"link,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLinkResponse) Reset() {
+	*x = UpdateLinkResponse{}
+	mi := &file_refero_v1_links_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLinkResponse) ProtoMessage() {}
+
+func (x *UpdateLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLinkResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLinkResponse) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateLinkResponse) GetLink() *Link {
+	if x != nil {
+		return x.Link
+	}
+	return nil
+}
+
+type DeleteLinkRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// If set, the deletion fails with ABORTED unless the link is still at this version.
+	Version       *int32 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLinkRequest) Reset() {
+	*x = DeleteLinkRequest{}
+	mi := &file_refero_v1_links_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLinkRequest) ProtoMessage() {}
+
+func (x *DeleteLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLinkRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLinkRequest) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteLinkRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteLinkRequest) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLinkResponse) Reset() {
+	*x = DeleteLinkResponse{}
+	mi := &file_refero_v1_links_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLinkResponse) ProtoMessage() {}
+
+func (x *DeleteLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_refero_v1_links_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLinkResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLinkResponse) Descriptor() ([]byte, []int) {
+	return file_refero_v1_links_proto_rawDescGZIP(), []int{13}
+}
+
+var File_refero_v1_links_proto protoreflect.FileDescriptor
+
+const file_refero_v1_links_proto_rawDesc = "" +
+	"\n" +
+	"\x15refero/v1/links.proto\x12\trefero.v1\x1a\x19refero/v1/resources.proto\"\x80\x01\n" +
+	"\x11CreateLinkRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12!\n" +
+	"\fcategory_ids\x18\x04 \x03(\tR\vcategoryIds\"9\n" +
+	"\x12CreateLinkResponse\x12#\n" +
+	"\x04link\x18\x01 \x01(\v2\x0f.refero.v1.LinkR\x04link\" \n" +
+	"\x0eGetLinkRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
+	"\x0fGetLinkResponse\x12#\n" +
+	"\x04link\x18\x01 \x01(\v2\x0f.refero.v1.LinkR\x04link\"5\n" +
+	"\x16ResolveShortURLRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\">\n" +
+	"\x17ResolveShortURLResponse\x12#\n" +
+	"\x04link\x18\x01 \x01(\v2\x0f.refero.v1.LinkR\x04link\"\x12\n" +
+	"\x10ListLinksRequest\":\n" +
+	"\x11ListLinksResponse\x12%\n" +
+	"\x05links\x18\x01 \x03(\v2\x0f.refero.v1.LinkR\x05links\"+\n" +
+	"\x19ListLinkCategoriesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Q\n" +
+	"\x1aListLinkCategoriesResponse\x123\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x13.refero.v1.CategoryR\n" +
+	"categories\"\xbb\x01\n" +
+	"\x11UpdateLinkRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12!\n" +
+	"\fcategory_ids\x18\x05 \x03(\tR\vcategoryIds\x12\x1d\n" +
+	"\aversion\x18\x06 \x01(\x05H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"9\n" +
+	"\x12UpdateLinkResponse\x12#\n" +
+	"\x04link\x18\x01 \x01(\v2\x0f.refero.v1.LinkR\x04link\"N\n" +
+	"\x11DeleteLinkRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x05H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\x14\n" +
+	"\x12DeleteLinkResponse2\xc9\x04\n" +
+	"\vLinkService\x12I\n" +
+	"\n" +
+	"CreateLink\x12\x1c.refero.v1.CreateLinkRequest\x1a\x1d.refero.v1.CreateLinkResponse\x12E\n" +
+	"\aGetLink\x12\x19.refero.v1.GetLinkRequest\x1a\x1a.refero.v1.GetLinkResponse\"\x03\x90\x02\x01\x12]\n" +
+	"\x0fResolveShortURL\x12!.refero.v1.ResolveShortURLRequest\x1a\".refero.v1.ResolveShortURLResponse\"\x03\x90\x02\x01\x12K\n" +
+	"\tListLinks\x12\x1b.refero.v1.ListLinksRequest\x1a\x1c.refero.v1.ListLinksResponse\"\x03\x90\x02\x01\x12f\n" +
+	"\x12ListLinkCategories\x12$.refero.v1.ListLinkCategoriesRequest\x1a%.refero.v1.ListLinkCategoriesResponse\"\x03\x90\x02\x01\x12I\n" +
+	"\n" +
+	"UpdateLink\x12\x1c.refero.v1.UpdateLinkRequest\x1a\x1d.refero.v1.UpdateLinkResponse\x12I\n" +
+	"\n" +
+	"DeleteLink\x12\x1c.refero.v1.DeleteLinkRequest\x1a\x1d.refero.v1.DeleteLinkResponseB;Z9github.com/OmprakashD20/refero-api/gen/refero/v1;referov1b\x06proto3"
+
+var (
+	file_refero_v1_links_proto_rawDescOnce sync.Once
+	file_refero_v1_links_proto_rawDescData []byte
+)
+
+func file_refero_v1_links_proto_rawDescGZIP() []byte {
+	file_refero_v1_links_proto_rawDescOnce.Do(func() {
+		file_refero_v1_links_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_refero_v1_links_proto_rawDesc), len(file_refero_v1_links_proto_rawDesc)))
+	})
+	return file_refero_v1_links_proto_rawDescData
+}
+
+var file_refero_v1_links_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_refero_v1_links_proto_goTypes = []any{
+	(*CreateLinkRequest)(nil),          // 0: refero.v1.CreateLinkRequest
+	(*CreateLinkResponse)(nil),         // 1: refero.v1.CreateLinkResponse
+	(*GetLinkRequest)(nil),             // 2: refero.v1.GetLinkRequest
+	(*GetLinkResponse)(nil),            // 3: refero.v1.GetLinkResponse
+	(*ResolveShortURLRequest)(nil),     // 4: refero.v1.ResolveShortURLRequest
+	(*ResolveShortURLResponse)(nil),    // 5: refero.v1.ResolveShortURLResponse
+	(*ListLinksRequest)(nil),           // 6: refero.v1.ListLinksRequest
+	(*ListLinksResponse)(nil),          // 7: refero.v1.ListLinksResponse
+	(*ListLinkCategoriesRequest)(nil),  // 8: refero.v1.ListLinkCategoriesRequest
+	(*ListLinkCategoriesResponse)(nil), // 9: refero.v1.ListLinkCategoriesResponse
+	(*UpdateLinkRequest)(nil),          // 10: refero.v1.UpdateLinkRequest
+	(*UpdateLinkResponse)(nil),         // 11: refero.v1.UpdateLinkResponse
+	(*DeleteLinkRequest)(nil),          // 12: refero.v1.DeleteLinkRequest
+	(*DeleteLinkResponse)(nil),         // 13: refero.v1.DeleteLinkResponse
+	(*Link)(nil),                       // 14: refero.v1.Link
+	(*Category)(nil),                   // 15: refero.v1.Category
+}
+var file_refero_v1_links_proto_depIdxs = []int32{
+	14, // 0: refero.v1.CreateLinkResponse.link:type_name -> refero.v1.Link
+	14, // 1: refero.v1.GetLinkResponse.link:type_name -> refero.v1.Link
+	14, // 2: refero.v1.ResolveShortURLResponse.link:type_name -> refero.v1.Link
+	14, // 3: refero.v1.ListLinksResponse.links:type_name -> refero.v1.Link
+	15, // 4: refero.v1.ListLinkCategoriesResponse.categories:type_name -> refero.v1.Category
+	14, // 5: refero.v1.UpdateLinkResponse.link:type_name -> refero.v1.Link
+	0,  // 6: refero.v1.LinkService.CreateLink:input_type -> refero.v1.CreateLinkRequest
+	2,  // 7: refero.v1.LinkService.GetLink:input_type -> refero.v1.GetLinkRequest
+	4,  // 8: refero.v1.LinkService.ResolveShortURL:input_type -> refero.v1.ResolveShortURLRequest
+	6,  // 9: refero.v1.LinkService.ListLinks:input_type -> refero.v1.ListLinksRequest
+	8,  // 10: refero.v1.LinkService.ListLinkCategories:input_type -> refero.v1.ListLinkCategoriesRequest
+	10, // 11: refero.v1.LinkService.UpdateLink:input_type -> refero.v1.UpdateLinkRequest
+	12, // 12: refero.v1.LinkService.DeleteLink:input_type -> refero.v1.DeleteLinkRequest
+	1,  // 13: refero.v1.LinkService.CreateLink:output_type -> refero.v1.CreateLinkResponse
+	3,  // 14: refero.v1.LinkService.GetLink:output_type -> refero.v1.GetLinkResponse
+	5,  // 15: refero.v1.LinkService.ResolveShortURL:output_type -> refero.v1.ResolveShortURLResponse
+	7,  // 16: refero.v1.LinkService.ListLinks:output_type -> refero.v1.ListLinksResponse
+	9,  // 17: refero.v1.LinkService.ListLinkCategories:output_type -> refero.v1.ListLinkCategoriesResponse
+	11, // 18: refero.v1.LinkService.UpdateLink:output_type -> refero.v1.UpdateLinkResponse
+	13, // 19: refero.v1.LinkService.DeleteLink:output_type -> refero.v1.DeleteLinkResponse
+	13, // [13:20] is the sub-list for method output_type
+	6,  // [6:13] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_refero_v1_links_proto_init() }
+func file_refero_v1_links_proto_init() {
+	if File_refero_v1_links_proto != nil {
+		return
+	}
+	file_refero_v1_resources_proto_init()
+	file_refero_v1_links_proto_msgTypes[10].OneofWrappers = []any{}
+	file_refero_v1_links_proto_msgTypes[12].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_refero_v1_links_proto_rawDesc), len(file_refero_v1_links_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_refero_v1_links_proto_goTypes,
+		DependencyIndexes: file_refero_v1_links_proto_depIdxs,
+		MessageInfos:      file_refero_v1_links_proto_msgTypes,
+	}.Build()
+	File_refero_v1_links_proto = out.File
+	file_refero_v1_links_proto_goTypes = nil
+	file_refero_v1_links_proto_depIdxs = nil
+}
@@ -0,0 +1,294 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: refero/v1/links.proto
+
+package referov1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/OmprakashD20/refero-api/gen/refero/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// LinkServiceName is the fully-qualified name of the LinkService service.
+	LinkServiceName = "refero.v1.LinkService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// LinkServiceCreateLinkProcedure is the fully-qualified name of the LinkService's CreateLink RPC.
+	LinkServiceCreateLinkProcedure = "/refero.v1.LinkService/CreateLink"
+	// LinkServiceGetLinkProcedure is the fully-qualified name of the LinkService's GetLink RPC.
+	LinkServiceGetLinkProcedure = "/refero.v1.LinkService/GetLink"
+	// LinkServiceResolveShortURLProcedure is the fully-qualified name of the LinkService's
+	// ResolveShortURL RPC.
+	LinkServiceResolveShortURLProcedure = "/refero.v1.LinkService/ResolveShortURL"
+	// LinkServiceListLinksProcedure is the fully-qualified name of the LinkService's ListLinks RPC.
+	LinkServiceListLinksProcedure = "/refero.v1.LinkService/ListLinks"
+	// LinkServiceListLinkCategoriesProcedure is the fully-qualified name of the LinkService's
+	// ListLinkCategories RPC.
+	LinkServiceListLinkCategoriesProcedure = "/refero.v1.LinkService/ListLinkCategories"
+	// LinkServiceUpdateLinkProcedure is the fully-qualified name of the LinkService's UpdateLink RPC.
+	LinkServiceUpdateLinkProcedure = "/refero.v1.LinkService/UpdateLink"
+	// LinkServiceDeleteLinkProcedure is the fully-qualified name of the LinkService's DeleteLink RPC.
+	LinkServiceDeleteLinkProcedure = "/refero.v1.LinkService/DeleteLink"
+)
+
+// LinkServiceClient is a client for the refero.v1.LinkService service.
+type LinkServiceClient interface {
+	// Creates the link, or adds the categories to the link if its URL is already saved.
+	CreateLink(context.Context, *connect.Request[v1.CreateLinkRequest]) (*connect.Response[v1.CreateLinkResponse], error)
+	GetLink(context.Context, *connect.Request[v1.GetLinkRequest]) (*connect.Response[v1.GetLinkResponse], error)
+	// Looks up the link a short URL redirects to.
+	ResolveShortURL(context.Context, *connect.Request[v1.ResolveShortURLRequest]) (*connect.Response[v1.ResolveShortURLResponse], error)
+	ListLinks(context.Context, *connect.Request[v1.ListLinksRequest]) (*connect.Response[v1.ListLinksResponse], error)
+	ListLinkCategories(context.Context, *connect.Request[v1.ListLinkCategoriesRequest]) (*connect.Response[v1.ListLinkCategoriesResponse], error)
+	// Replaces the link details and categories.
+	UpdateLink(context.Context, *connect.Request[v1.UpdateLinkRequest]) (*connect.Response[v1.UpdateLinkResponse], error)
+	// Moves the link to the trash.
+	DeleteLink(context.Context, *connect.Request[v1.DeleteLinkRequest]) (*connect.Response[v1.DeleteLinkResponse], error)
+}
+
+// NewLinkServiceClient constructs a client for the refero.v1.LinkService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewLinkServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) LinkServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	linkServiceMethods := v1.File_refero_v1_links_proto.Services().ByName("LinkService").Methods()
+	return &linkServiceClient{
+		createLink: connect.NewClient[v1.CreateLinkRequest, v1.CreateLinkResponse](
+			httpClient,
+			baseURL+LinkServiceCreateLinkProcedure,
+			connect.WithSchema(linkServiceMethods.ByName("CreateLink")),
+			connect.WithClientOptions(opts...),
+		),
+		getLink: connect.NewClient[v1.GetLinkRequest, v1.GetLinkResponse](
+			httpClient,
+			baseURL+LinkServiceGetLinkProcedure,
+			connect.WithSchema(linkServiceMethods.ByName("GetLink")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		resolveShortURL: connect.NewClient[v1.ResolveShortURLRequest, v1.ResolveShortURLResponse](
+			httpClient,
+			baseURL+LinkServiceResolveShortURLProcedure,
+			connect.WithSchema(linkServiceMethods.ByName("ResolveShortURL")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listLinks: connect.NewClient[v1.ListLinksRequest, v1.ListLinksResponse](
+			httpClient,
+			baseURL+LinkServiceListLinksProcedure,
+			connect.WithSchema(linkServiceMethods.ByName("ListLinks")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listLinkCategories: connect.NewClient[v1.ListLinkCategoriesRequest, v1.ListLinkCategoriesResponse](
+			httpClient,
+			baseURL+LinkServiceListLinkCategoriesProcedure,
+			connect.WithSchema(linkServiceMethods.ByName("ListLinkCategories")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		updateLink: connect.NewClient[v1.UpdateLinkRequest, v1.UpdateLinkResponse](
+			httpClient,
+			baseURL+LinkServiceUpdateLinkProcedure,
+			connect.WithSchema(linkServiceMethods.ByName("UpdateLink")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteLink: connect.NewClient[v1.DeleteLinkRequest, v1.DeleteLinkResponse](
+			httpClient,
+			baseURL+LinkServiceDeleteLinkProcedure,
+			connect.WithSchema(linkServiceMethods.ByName("DeleteLink")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// linkServiceClient implements LinkServiceClient.
+type linkServiceClient struct {
+	createLink         *connect.Client[v1.CreateLinkRequest, v1.CreateLinkResponse]
+	getLink            *connect.Client[v1.GetLinkRequest, v1.GetLinkResponse]
+	resolveShortURL    *connect.Client[v1.ResolveShortURLRequest, v1.ResolveShortURLResponse]
+	listLinks          *connect.Client[v1.ListLinksRequest, v1.ListLinksResponse]
+	listLinkCategories *connect.Client[v1.ListLinkCategoriesRequest, v1.ListLinkCategoriesResponse]
+	updateLink         *connect.Client[v1.UpdateLinkRequest, v1.UpdateLinkResponse]
+	deleteLink         *connect.Client[v1.DeleteLinkRequest, v1.DeleteLinkResponse]
+}
+
+// CreateLink calls refero.v1.LinkService.CreateLink.
+func (c *linkServiceClient) CreateLink(ctx context.Context, req *connect.Request[v1.CreateLinkRequest]) (*connect.Response[v1.CreateLinkResponse], error) {
+	return c.createLink.CallUnary(ctx, req)
+}
+
+// GetLink calls refero.v1.LinkService.GetLink.
+func (c *linkServiceClient) GetLink(ctx context.Context, req *connect.Request[v1.GetLinkRequest]) (*connect.Response[v1.GetLinkResponse], error) {
+	return c.getLink.CallUnary(ctx, req)
+}
+
+// ResolveShortURL calls refero.v1.LinkService.ResolveShortURL.
+func (c *linkServiceClient) ResolveShortURL(ctx context.Context, req *connect.Request[v1.ResolveShortURLRequest]) (*connect.Response[v1.ResolveShortURLResponse], error) {
+	return c.resolveShortURL.CallUnary(ctx, req)
+}
+
+// ListLinks calls refero.v1.LinkService.ListLinks.
+func (c *linkServiceClient) ListLinks(ctx context.Context, req *connect.Request[v1.ListLinksRequest]) (*connect.Response[v1.ListLinksResponse], error) {
+	return c.listLinks.CallUnary(ctx, req)
+}
+
+// ListLinkCategories calls refero.v1.LinkService.ListLinkCategories.
+func (c *linkServiceClient) ListLinkCategories(ctx context.Context, req *connect.Request[v1.ListLinkCategoriesRequest]) (*connect.Response[v1.ListLinkCategoriesResponse], error) {
+	return c.listLinkCategories.CallUnary(ctx, req)
+}
+
+// UpdateLink calls refero.v1.LinkService.UpdateLink.
+func (c *linkServiceClient) UpdateLink(ctx context.Context, req *connect.Request[v1.UpdateLinkRequest]) (*connect.Response[v1.UpdateLinkResponse], error) {
+	return c.updateLink.CallUnary(ctx, req)
+}
+
+// DeleteLink calls refero.v1.LinkService.DeleteLink.
+func (c *linkServiceClient) DeleteLink(ctx context.Context, req *connect.Request[v1.DeleteLinkRequest]) (*connect.Response[v1.DeleteLinkResponse], error) {
+	return c.deleteLink.CallUnary(ctx, req)
+}
+
+// LinkServiceHandler is an implementation of the refero.v1.LinkService service.
+type LinkServiceHandler interface {
+	// Creates the link, or adds the categories to the link if its URL is already saved.
+	CreateLink(context.Context, *connect.Request[v1.CreateLinkRequest]) (*connect.Response[v1.CreateLinkResponse], error)
+	GetLink(context.Context, *connect.Request[v1.GetLinkRequest]) (*connect.Response[v1.GetLinkResponse], error)
+	// Looks up the link a short URL redirects to.
+	ResolveShortURL(context.Context, *connect.Request[v1.ResolveShortURLRequest]) (*connect.Response[v1.ResolveShortURLResponse], error)
+	ListLinks(context.Context, *connect.Request[v1.ListLinksRequest]) (*connect.Response[v1.ListLinksResponse], error)
+	ListLinkCategories(context.Context, *connect.Request[v1.ListLinkCategoriesRequest]) (*connect.Response[v1.ListLinkCategoriesResponse], error)
+	// Replaces the link details and categories.
+	UpdateLink(context.Context, *connect.Request[v1.UpdateLinkRequest]) (*connect.Response[v1.UpdateLinkResponse], error)
+	// Moves the link to the trash.
+	DeleteLink(context.Context, *connect.Request[v1.DeleteLinkRequest]) (*connect.Response[v1.DeleteLinkResponse], error)
+}
+
+// NewLinkServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewLinkServiceHandler(svc LinkServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	linkServiceMethods := v1.File_refero_v1_links_proto.Services().ByName("LinkService").Methods()
+	linkServiceCreateLinkHandler := connect.NewUnaryHandler(
+		LinkServiceCreateLinkProcedure,
+		svc.CreateLink,
+		connect.WithSchema(linkServiceMethods.ByName("CreateLink")),
+		connect.WithHandlerOptions(opts...),
+	)
+	linkServiceGetLinkHandler := connect.NewUnaryHandler(
+		LinkServiceGetLinkProcedure,
+		svc.GetLink,
+		connect.WithSchema(linkServiceMethods.ByName("GetLink")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	linkServiceResolveShortURLHandler := connect.NewUnaryHandler(
+		LinkServiceResolveShortURLProcedure,
+		svc.ResolveShortURL,
+		connect.WithSchema(linkServiceMethods.ByName("ResolveShortURL")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	linkServiceListLinksHandler := connect.NewUnaryHandler(
+		LinkServiceListLinksProcedure,
+		svc.ListLinks,
+		connect.WithSchema(linkServiceMethods.ByName("ListLinks")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	linkServiceListLinkCategoriesHandler := connect.NewUnaryHandler(
+		LinkServiceListLinkCategoriesProcedure,
+		svc.ListLinkCategories,
+		connect.WithSchema(linkServiceMethods.ByName("ListLinkCategories")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	linkServiceUpdateLinkHandler := connect.NewUnaryHandler(
+		LinkServiceUpdateLinkProcedure,
+		svc.UpdateLink,
+		connect.WithSchema(linkServiceMethods.ByName("UpdateLink")),
+		connect.WithHandlerOptions(opts...),
+	)
+	linkServiceDeleteLinkHandler := connect.NewUnaryHandler(
+		LinkServiceDeleteLinkProcedure,
+		svc.DeleteLink,
+		connect.WithSchema(linkServiceMethods.ByName("DeleteLink")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/refero.v1.LinkService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LinkServiceCreateLinkProcedure:
+			linkServiceCreateLinkHandler.ServeHTTP(w, r)
+		case LinkServiceGetLinkProcedure:
+			linkServiceGetLinkHandler.ServeHTTP(w, r)
+		case LinkServiceResolveShortURLProcedure:
+			linkServiceResolveShortURLHandler.ServeHTTP(w, r)
+		case LinkServiceListLinksProcedure:
+			linkServiceListLinksHandler.ServeHTTP(w, r)
+		case LinkServiceListLinkCategoriesProcedure:
+			linkServiceListLinkCategoriesHandler.ServeHTTP(w, r)
+		case LinkServiceUpdateLinkProcedure:
+			linkServiceUpdateLinkHandler.ServeHTTP(w, r)
+		case LinkServiceDeleteLinkProcedure:
+			linkServiceDeleteLinkHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedLinkServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedLinkServiceHandler struct{}
+
+func (UnimplementedLinkServiceHandler) CreateLink(context.Context, *connect.Request[v1.CreateLinkRequest]) (*connect.Response[v1.CreateLinkResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("refero.v1.LinkService.CreateLink is not implemented"))
+}
+
+func (UnimplementedLinkServiceHandler) GetLink(context.Context, *connect.Request[v1.GetLinkRequest]) (*connect.Response[v1.GetLinkResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("refero.v1.LinkService.GetLink is not implemented"))
+}
+
+func (UnimplementedLinkServiceHandler) ResolveShortURL(context.Context, *connect.Request[v1.ResolveShortURLRequest]) (*connect.Response[v1.ResolveShortURLResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("refero.v1.LinkService.ResolveShortURL is not implemented"))
+}
+
+func (UnimplementedLinkServiceHandler) ListLinks(context.Context, *connect.Request[v1.ListLinksRequest]) (*connect.Response[v1.ListLinksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("refero.v1.LinkService.ListLinks is not implemented"))
+}
+
+func (UnimplementedLinkServiceHandler) ListLinkCategories(context.Context, *connect.Request[v1.ListLinkCategoriesRequest]) (*connect.Response[v1.ListLinkCategoriesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("refero.v1.LinkService.ListLinkCategories is not implemented"))
+}
+
+func (UnimplementedLinkServiceHandler) UpdateLink(context.Context, *connect.Request[v1.UpdateLinkRequest]) (*connect.Response[v1.UpdateLinkResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("refero.v1.LinkService.UpdateLink is not implemented"))
+}
+
+func (UnimplementedLinkServiceHandler) DeleteLink(context.Context, *connect.Request[v1.DeleteLinkRequest]) (*connect.Response[v1.DeleteLinkResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("refero.v1.LinkService.DeleteLink is not implemented"))
+}
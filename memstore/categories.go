@@ -0,0 +1,341 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+func (s *Store) CheckIfCategoryExistsByName(ctx context.Context, name string) (bool, error) {
+	if err := s.fault("CheckIfCategoryExistsByName"); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.categoryByName(name) != nil, nil
+}
+
+func (s *Store) CheckIfCategoryExistsByID(ctx context.Context, id string) (bool, error) {
+	if err := s.fault("CheckIfCategoryExistsByID"); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.category(canonicalID(id), false) != nil, nil
+}
+
+func (s *Store) CreateCategory(ctx context.Context, category validator.CreateCategoryPayload) error {
+	if err := s.fault("CreateCategory"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.categoryByName(category.Name) != nil {
+		return violation(uniqueViolation, "category_name_key")
+	}
+
+	parentID, err := s.parent(category.ParentId)
+	if err != nil {
+		return err
+	}
+
+	now := s.timestamp()
+	s.tables.categories = append(s.tables.categories, &categoryRow{
+		id:          newID(),
+		name:        category.Name,
+		parentID:    parentID,
+		description: clone(category.Description),
+		version:     1,
+		createdAt:   now,
+		updatedAt:   now,
+	})
+
+	return nil
+}
+
+func (s *Store) GetAllCategories(ctx context.Context) ([]types.CategoryDTO, error) {
+	if err := s.fault("GetAllCategories"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories := make([]types.CategoryDTO, 0)
+	for _, c := range s.tables.categories {
+		if c.deletedAt == nil {
+			categories = append(categories, c.dto())
+		}
+	}
+
+	return categories, nil
+}
+
+func (s *Store) GetCategoryByID(ctx context.Context, id string) (*types.CategoryDTO, error) {
+	if err := s.fault("GetCategoryByID"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.category(canonicalID(id), false)
+	if c == nil {
+		return nil, nil
+	}
+
+	return &types.CategoryDTO{
+		ID:          c.id,
+		Name:        c.name,
+		Description: clone(c.description),
+		ParentID:    clone(c.parentID),
+		Version:     c.version,
+	}, nil
+}
+
+func (s *Store) GetCategoryByName(ctx context.Context, name string) (*types.CategoryDTO, error) {
+	if err := s.fault("GetCategoryByName"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.categoryByName(name)
+	if c == nil {
+		return nil, nil
+	}
+
+	return &types.CategoryDTO{
+		ID:          c.id,
+		Name:        c.name,
+		Description: clone(c.description),
+		ParentID:    clone(c.parentID),
+	}, nil
+}
+
+func (s *Store) UpdateCategoryByID(ctx context.Context, id string, category validator.UpdateCategoryPayload, version *int32) error {
+	if err := s.fault("UpdateCategoryByID"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The parent is checked first, like the stores do
+	parentID, err := s.parent(category.ParentId)
+	if err != nil {
+		return err
+	}
+
+	c, err := s.categoryAtVersion(canonicalID(id), version)
+	if err != nil {
+		return err
+	}
+
+	if other := s.categoryByName(category.Name); other != nil && other != c {
+		return violation(uniqueViolation, "category_name_key")
+	}
+
+	c.name = category.Name
+	c.parentID = parentID
+	c.description = clone(category.Description)
+	c.version++
+	c.updatedAt = s.timestamp()
+
+	return nil
+}
+
+// DeleteCategoryByID moves the category to the trash, along with its subcategories.
+func (s *Store) DeleteCategoryByID(ctx context.Context, id string, version *int32) error {
+	if err := s.fault("DeleteCategoryByID"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	root, err := s.categoryAtVersion(canonicalID(id), version)
+	if err != nil {
+		return err
+	}
+
+	// The whole subtree is trashed at the same time, so that it is restored together
+	now := s.timestamp()
+	subtree := []*categoryRow{root}
+	for i := 0; i < len(subtree); i++ {
+		for _, c := range s.tables.categories {
+			if c.deletedAt == nil && c.parentID != nil && *c.parentID == subtree[i].id && !slices.Contains(subtree, c) {
+				subtree = append(subtree, c)
+			}
+		}
+	}
+	for _, c := range subtree {
+		c.deletedAt = ptr(now)
+		c.version++
+	}
+
+	return nil
+}
+
+func (s *Store) GetLinksForCategory(ctx context.Context, id string) ([]types.LinkDTO, error) {
+	if err := s.fault("GetLinksForCategory"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id = canonicalID(id)
+	links := make([]types.LinkDTO, 0)
+	for _, mapping := range s.tables.mappings {
+		if mapping.CategoryID != id {
+			continue
+		}
+		if l := s.link(mapping.LinkID, false); l != nil {
+			link := l.dto()
+			link.Version = 0
+			links = append(links, link)
+		}
+	}
+
+	return links, nil
+}
+
+// GetCategoriesByIDs returns the categories with the given IDs, in no particular order.
+// IDs of categories that don't exist are left out.
+func (s *Store) GetCategoriesByIDs(ctx context.Context, ids []string) ([]types.CategoryDTO, error) {
+	if err := s.fault("GetCategoriesByIDs"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := idSet(ids)
+	categories := make([]types.CategoryDTO, 0)
+	for _, c := range s.tables.categories {
+		if _, ok := wanted[c.id]; ok && c.deletedAt == nil {
+			categories = append(categories, c.dto())
+		}
+	}
+
+	return categories, nil
+}
+
+// GetSubcategoriesOfCategories returns the subcategories of each of the given categories, keyed by parent ID.
+func (s *Store) GetSubcategoriesOfCategories(ctx context.Context, ids []string) (map[string][]types.CategoryDTO, error) {
+	if err := s.fault("GetSubcategoriesOfCategories"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := idSet(ids)
+	subcategories := make(map[string][]types.CategoryDTO, len(ids))
+	for _, c := range s.tables.categories {
+		if c.deletedAt != nil || c.parentID == nil {
+			continue
+		}
+		if _, ok := wanted[*c.parentID]; ok {
+			subcategories[*c.parentID] = append(subcategories[*c.parentID], c.dto())
+		}
+	}
+
+	return subcategories, nil
+}
+
+// GetLinksForCategories returns the links in each of the given categories, keyed by category ID.
+func (s *Store) GetLinksForCategories(ctx context.Context, ids []string) (map[string][]types.LinkDTO, error) {
+	if err := s.fault("GetLinksForCategories"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := idSet(ids)
+	links := make(map[string][]types.LinkDTO, len(ids))
+	for _, mapping := range s.tables.mappings {
+		if _, ok := wanted[mapping.CategoryID]; !ok {
+			continue
+		}
+		if l := s.link(mapping.LinkID, false); l != nil {
+			links[mapping.CategoryID] = append(links[mapping.CategoryID], l.dto())
+		}
+	}
+
+	return links, nil
+}
+
+// category returns the category with the given ID, or nil if there is none. Categories in the trash
+// are only returned if trashed is set. The lock must be held.
+func (s *Store) category(id string, trashed bool) *categoryRow {
+	for _, c := range s.tables.categories {
+		if c.id == id && (trashed || c.deletedAt == nil) {
+			return c
+		}
+	}
+	return nil
+}
+
+// categoryByName returns the category with the given name which is not in the trash, or nil.
+// The lock must be held.
+func (s *Store) categoryByName(name string) *categoryRow {
+	for _, c := range s.tables.categories {
+		if c.deletedAt == nil && c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// categoryAtVersion returns the category to write to, failing like the stores do if it doesn't exist
+// or isn't at the given version. The lock must be held.
+func (s *Store) categoryAtVersion(id string, version *int32) (*categoryRow, error) {
+	c := s.category(id, false)
+	if c == nil || (version != nil && c.version != *version) {
+		// Category was modified since the given version
+		if version != nil {
+			return nil, errs.ErrPreconditionFailed
+		}
+		// Category does not exists
+		return nil, errs.ErrCategoryNotFound
+	}
+	return c, nil
+}
+
+// parent returns the ID of the parent category to set, which has to exist outside of the trash.
+// IDs which aren't UUIDs are stored as NULL, like the stores do. The lock must be held.
+func (s *Store) parent(parentID string) (*string, error) {
+	id := canonicalID(parentID)
+	if id == "" {
+		return nil, nil
+	}
+	if s.category(id, false) == nil {
+		return nil, errs.InvalidReference("parentId")
+	}
+	return &id, nil
+}
+
+func (c *categoryRow) dto() types.CategoryDTO {
+	return types.CategoryDTO{
+		ID:          c.id,
+		Name:        c.name,
+		ParentID:    clone(c.parentID),
+		Description: clone(c.description),
+		Version:     c.version,
+		CreatedAt:   ptr(c.createdAt),
+		UpdatedAt:   ptr(c.updatedAt),
+	}
+}
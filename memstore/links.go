@@ -0,0 +1,412 @@
+package memstore
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+func (s *Store) AddLinkToCategory(ctx context.Context, mappings []types.LinkCategoryDTO, txn *repository.Queries) error {
+	if err := s.fault("AddLinkToCategory"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addMappings(mappings)
+}
+
+// AddCategoriesToLink adds the mappings and bumps the version of the link, like the stores do.
+func (s *Store) AddCategoriesToLink(ctx context.Context, id string, categoryIDs []string, txn *repository.Queries) error {
+	if err := s.fault("AddCategoriesToLink"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.linkAtVersion(canonicalID(id), nil)
+	if err != nil {
+		return err
+	}
+
+	mappings := make([]types.LinkCategoryDTO, len(categoryIDs))
+	for i, categoryID := range categoryIDs {
+		mappings[i] = types.LinkCategoryDTO{LinkID: id, CategoryID: categoryID}
+	}
+	if err := s.addMappings(mappings); err != nil {
+		return err
+	}
+
+	l.version++
+	l.updatedAt = s.timestamp()
+	return nil
+}
+
+// addMappings adds the mappings at once, so none of them are added if one is invalid.
+// The lock must be held.
+func (s *Store) addMappings(mappings []types.LinkCategoryDTO) error {
+	added := slices.Clone(s.tables.mappings)
+	for _, obj := range mappings {
+		mapping := types.LinkCategoryDTO{LinkID: canonicalID(obj.LinkID), CategoryID: canonicalID(obj.CategoryID)}
+
+		switch {
+		case mapping.LinkID == "":
+			return nullViolation("link_id")
+		case mapping.CategoryID == "":
+			return nullViolation("category_id")
+		case s.category(mapping.CategoryID, false) == nil:
+			// Trashed categories are rejected like missing ones, as the stores do
+			return errs.InvalidReference("categoryIds")
+		case s.link(mapping.LinkID, true) == nil:
+			return violation(foreignKeyViolation, "link_category_map_link_id_fkey")
+		case slices.Contains(added, mapping):
+			return violation(uniqueViolation, "link_category_map_link_id_category_id_key")
+		}
+
+		added = append(added, mapping)
+	}
+
+	s.tables.mappings = added
+	return nil
+}
+
+func (s *Store) RemoveLinkFromCategory(ctx context.Context, mappings []types.LinkCategoryDTO, txn *repository.Queries) error {
+	if err := s.fault("RemoveLinkFromCategory"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, obj := range mappings {
+		mapping := types.LinkCategoryDTO{LinkID: canonicalID(obj.LinkID), CategoryID: canonicalID(obj.CategoryID)}
+		s.tables.mappings = slices.DeleteFunc(s.tables.mappings, func(m types.LinkCategoryDTO) bool {
+			return m == mapping
+		})
+	}
+
+	return nil
+}
+
+func (s *Store) CheckIfLinkExistsByURL(ctx context.Context, url string, txn *repository.Queries) (*string, error) {
+	if err := s.fault("CheckIfLinkExistsByURL"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, l := range s.tables.links {
+		if l.deletedAt == nil && l.url == url {
+			return ptr(l.id), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Store) CreateLink(ctx context.Context, link validator.CreateLinkPayload, shortUrl string, txn *repository.Queries) (*string, error) {
+	if err := s.fault("CreateLink"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, l := range s.tables.links {
+		if l.deletedAt == nil && l.url == link.URL {
+			return nil, violation(uniqueViolation, "links_url_key")
+		}
+		// Short URLs stay reserved while the link is in the trash
+		if l.shortURL == shortUrl {
+			return nil, violation(uniqueViolation, "links_short_url_key")
+		}
+	}
+
+	now := s.timestamp()
+	row := &linkRow{
+		id:          newID(),
+		url:         link.URL,
+		title:       link.Title,
+		description: *link.Description,
+		shortURL:    shortUrl,
+		version:     1,
+		createdAt:   now,
+		updatedAt:   now,
+	}
+	s.tables.links = append(s.tables.links, row)
+
+	return ptr(row.id), nil
+}
+
+func (s *Store) GetAllLinks(ctx context.Context) ([]types.LinkDTO, error) {
+	if err := s.fault("GetAllLinks"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links := make([]types.LinkDTO, 0)
+	for _, l := range s.tables.links {
+		if l.deletedAt == nil {
+			links = append(links, l.dto())
+		}
+	}
+
+	return links, nil
+}
+
+// SearchLinks returns the links containing the search term in their title, URL or description,
+// ignoring case, newest first. An empty term matches every link, and a limit of 0 returns all of them.
+func (s *Store) SearchLinks(ctx context.Context, search string, limit, offset int32) ([]types.LinkDTO, error) {
+	if err := s.fault("SearchLinks"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	search = strings.ToLower(search)
+	var matches []*linkRow
+	for _, l := range s.tables.links {
+		if l.deletedAt != nil {
+			continue
+		}
+		if strings.Contains(strings.ToLower(l.title), search) ||
+			strings.Contains(strings.ToLower(l.url), search) ||
+			strings.Contains(strings.ToLower(l.description), search) {
+			matches = append(matches, l)
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b *linkRow) int {
+		return cmp.Or(b.createdAt.Compare(a.createdAt), strings.Compare(a.id, b.id))
+	})
+
+	matches = matches[min(int(offset), len(matches)):]
+	if limit > 0 {
+		matches = matches[:min(int(limit), len(matches))]
+	}
+
+	links := make([]types.LinkDTO, len(matches))
+	for i, l := range matches {
+		links[i] = l.dto()
+	}
+
+	return links, nil
+}
+
+func (s *Store) GetLinkByID(ctx context.Context, id string) (*types.LinkDTO, error) {
+	if err := s.fault("GetLinkByID"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := s.link(canonicalID(id), false)
+	if l == nil {
+		return nil, nil
+	}
+
+	return &types.LinkDTO{
+		ID:          l.id,
+		Title:       l.title,
+		Description: l.description,
+		Url:         l.url,
+		ShortUrl:    l.shortURL,
+		Version:     l.version,
+	}, nil
+}
+
+func (s *Store) GetLinkByShortURL(ctx context.Context, shortUrl string, txn *repository.Queries) (*types.LinkDTO, error) {
+	if err := s.fault("GetLinkByShortURL"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, l := range s.tables.links {
+		if l.deletedAt == nil && l.shortURL == shortUrl {
+			return &types.LinkDTO{
+				ID:          l.id,
+				Url:         l.url,
+				Title:       l.title,
+				Description: l.description,
+				ShortUrl:    l.shortURL,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Store) GetCategoriesForLink(ctx context.Context, id string, txn *repository.Queries) ([]string, error) {
+	if err := s.fault("GetCategoriesForLink"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id = canonicalID(id)
+	categoryIDs := make([]string, 0)
+	for _, mapping := range s.tables.mappings {
+		if mapping.LinkID == id && s.category(mapping.CategoryID, false) != nil {
+			categoryIDs = append(categoryIDs, mapping.CategoryID)
+		}
+	}
+
+	return categoryIDs, nil
+}
+
+// GetLinksByIDs returns the links with the given IDs, in no particular order.
+// IDs of links that don't exist are left out.
+func (s *Store) GetLinksByIDs(ctx context.Context, ids []string) ([]types.LinkDTO, error) {
+	if err := s.fault("GetLinksByIDs"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := idSet(ids)
+	links := make([]types.LinkDTO, 0)
+	for _, l := range s.tables.links {
+		if _, ok := wanted[l.id]; ok && l.deletedAt == nil {
+			links = append(links, l.dto())
+		}
+	}
+
+	return links, nil
+}
+
+// GetCategoriesForLinks returns the categories of each of the given links, keyed by link ID.
+func (s *Store) GetCategoriesForLinks(ctx context.Context, ids []string) (map[string][]types.CategoryDTO, error) {
+	if err := s.fault("GetCategoriesForLinks"); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := idSet(ids)
+	categories := make(map[string][]types.CategoryDTO, len(ids))
+	for _, mapping := range s.tables.mappings {
+		if _, ok := wanted[mapping.LinkID]; !ok {
+			continue
+		}
+		if c := s.category(mapping.CategoryID, false); c != nil {
+			categories[mapping.LinkID] = append(categories[mapping.LinkID], c.dto())
+		}
+	}
+
+	return categories, nil
+}
+
+func (s *Store) UpdateLinkByID(ctx context.Context, id string, link validator.UpdateLinkPayload, version *int32, txn *repository.Queries) error {
+	if err := s.fault("UpdateLinkByID"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.linkAtVersion(canonicalID(id), version)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range s.tables.links {
+		if other != l && other.deletedAt == nil && other.url == link.URL {
+			return violation(uniqueViolation, "links_url_key")
+		}
+	}
+
+	l.title = link.Title
+	l.url = link.URL
+	l.description = *link.Description
+	l.version++
+	l.updatedAt = s.timestamp()
+
+	return nil
+}
+
+func (s *Store) DeleteLinkByID(ctx context.Context, id string, version *int32, txn *repository.Queries) error {
+	if err := s.fault("DeleteLinkByID"); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.linkAtVersion(canonicalID(id), version)
+	if err != nil {
+		return err
+	}
+
+	l.deletedAt = ptr(s.timestamp())
+	l.version++
+
+	return nil
+}
+
+// link returns the link with the given ID, or nil if there is none. Links in the trash are only
+// returned if trashed is set. The lock must be held.
+func (s *Store) link(id string, trashed bool) *linkRow {
+	for _, l := range s.tables.links {
+		if l.id == id && (trashed || l.deletedAt == nil) {
+			return l
+		}
+	}
+	return nil
+}
+
+// linkAtVersion returns the link to write to, failing like the stores do if it doesn't exist
+// or isn't at the given version. The lock must be held.
+func (s *Store) linkAtVersion(id string, version *int32) (*linkRow, error) {
+	l := s.link(id, false)
+	if l == nil || (version != nil && l.version != *version) {
+		// Link was modified since the given version
+		if version != nil {
+			return nil, errs.ErrPreconditionFailed
+		}
+		// Link does not exists
+		return nil, errs.ErrLinkNotFound
+	}
+	return l, nil
+}
+
+func (l *linkRow) dto() types.LinkDTO {
+	return types.LinkDTO{
+		ID:          l.id,
+		Url:         l.url,
+		Title:       l.title,
+		Description: l.description,
+		ShortUrl:    l.shortURL,
+		Version:     l.version,
+		CreatedAt:   ptr(l.createdAt),
+		UpdatedAt:   ptr(l.updatedAt),
+	}
+}
+
+// idSet returns the canonical form of the IDs.
+func idSet(ids []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if id = canonicalID(id); id != "" {
+			set[id] = struct{}{}
+		}
+	}
+	return set
+}
@@ -0,0 +1,198 @@
+// Package memstore keeps links and categories in process memory, for testing the services without
+// a database. Store implements the link, category and transaction stores of the types package the
+// way the Postgres stores do: deleted rows are kept in the trash, writes increment the version of
+// the row, and constraint violations are reported with the errors of the Postgres stores.
+// Events are not published.
+package memstore
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+	"github.com/OmprakashD20/refero-api/utils"
+)
+
+var (
+	_ types.LinkStore        = (*Store)(nil)
+	_ types.CategoryStore    = (*Store)(nil)
+	_ types.TransactionStore = (*Store)(nil)
+)
+
+type linkRow struct {
+	id          string
+	url         string
+	title       string
+	description string
+	shortURL    string
+	version     int32
+	createdAt   time.Time
+	updatedAt   time.Time
+	deletedAt   *time.Time
+}
+
+type categoryRow struct {
+	id          string
+	name        string
+	parentID    *string
+	description *string
+	version     int32
+	createdAt   time.Time
+	updatedAt   time.Time
+	deletedAt   *time.Time
+}
+
+// tables holds the rows, in insertion order.
+type tables struct {
+	links      []*linkRow
+	categories []*categoryRow
+	mappings   []types.LinkCategoryDTO
+}
+
+// clone copies the rows, so that a transaction can be rolled back to them.
+func (t tables) clone() tables {
+	c := tables{
+		links:      make([]*linkRow, len(t.links)),
+		categories: make([]*categoryRow, len(t.categories)),
+		mappings:   slices.Clone(t.mappings),
+	}
+	for i, l := range t.links {
+		row := *l
+		c.links[i] = &row
+	}
+	for i, category := range t.categories {
+		row := *category
+		c.categories[i] = &row
+	}
+	return c
+}
+
+// Store is an in-memory store of links and categories. It is safe for concurrent use.
+type Store struct {
+	mu       sync.Mutex
+	tables   tables
+	failures map[string]error
+	last     time.Time // Timestamp of the latest write
+
+	txn sync.Mutex // Serializes transactions
+	now func() time.Time
+}
+
+func New() *Store {
+	return &Store{failures: make(map[string]error), now: time.Now}
+}
+
+// Fail makes every call of the named store method return err, until it is called again with a nil error.
+// Failing "Exec" fails transactions before they begin.
+func (s *Store) Fail(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		delete(s.failures, method)
+		return
+	}
+	s.failures[method] = err
+}
+
+// Exec runs fn and restores the rows it was started with if fn fails. Transactions run one at a time,
+// but calls outside of them are not isolated from them, and are rolled back along with them.
+// The store methods ignore the queries they are given, so fn is called with nil queries.
+func (s *Store) Exec(ctx context.Context, fn func(ctx context.Context, q *repository.Queries) error) error {
+	if err := s.fault("Exec"); err != nil {
+		return err
+	}
+
+	s.txn.Lock()
+	defer s.txn.Unlock()
+
+	s.mu.Lock()
+	snapshot := s.tables.clone()
+	s.mu.Unlock()
+
+	if err := fn(ctx, nil); err != nil {
+		s.mu.Lock()
+		s.tables = snapshot
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) fault(method string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failures[method]
+}
+
+// timestamp returns the time of a write. Timestamps increase strictly, like the transaction
+// timestamps of Postgres, so that ordering by them is deterministic. The lock must be held.
+func (s *Store) timestamp() time.Time {
+	now := s.now().Truncate(time.Microsecond)
+	if !now.After(s.last) {
+		now = s.last.Add(time.Microsecond)
+	}
+	s.last = now
+	return now
+}
+
+// Postgres error codes of the constraint violations the stores report
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	notNullViolation    = "23502"
+)
+
+// violation reports a constraint violation the way the Postgres stores do.
+func violation(code, constraint string) error {
+	return errs.TranslatePgError(&pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           code,
+		Message:        "violates constraint " + constraint,
+		ConstraintName: constraint,
+	})
+}
+
+// nullViolation reports a missing value, such as an ID which is not a UUID, the way the Postgres stores do.
+func nullViolation(column string) error {
+	return errs.TranslatePgError(&pgconn.PgError{
+		Severity:   "ERROR",
+		Code:       notNullViolation,
+		Message:    "null value in column " + column + " violates not-null constraint",
+		ColumnName: column,
+	})
+}
+
+// canonicalID returns the ID in the format Postgres returns UUIDs in, or "" if it is not a UUID.
+// IDs which aren't UUIDs don't match any row, as the stores query them as NULL.
+func canonicalID(id string) string {
+	pgID := utils.ToPgUUID(id)
+	if !pgID.Valid {
+		return ""
+	}
+	return pgID.String()
+}
+
+func newID() string {
+	return uuid.NewString()
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// clone copies the value p points to, so that rows don't share it with their callers.
+func clone[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	return ptr(*p)
+}
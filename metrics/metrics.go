@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "refero"
+
+// Registry holds every metric exposed on /metrics.
+// A dedicated registry keeps metrics registered by dependencies out of the output.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	}, []string{"method", "route"})
+
+	TransactionRollbacks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "transaction_rollbacks_total",
+		Help:      "Number of database transactions that were rolled back.",
+	})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Number of cache lookups by cache and result (hit, negative_hit or miss).",
+	}, []string{"cache", "result"})
+
+	Redirects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "links",
+		Name:      "redirects_total",
+		Help:      "Number of short URL lookups by result (hit or miss).",
+	}, []string{"result"})
+
+	FeedSubscribers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "feed",
+		Name:      "subscribers",
+		Help:      "Number of clients subscribed to the change feed by transport (sse or websocket).",
+	}, []string{"transport"})
+
+	FeedDroppedSubscribers = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "feed",
+		Name:      "dropped_subscribers_total",
+		Help:      "Number of change feed subscribers disconnected for falling behind.",
+	})
+
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhooks",
+		Name:      "delivery_attempts_total",
+		Help:      "Number of webhook delivery attempts by result (succeeded, retrying or failed).",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		TransactionRollbacks,
+		CacheRequests,
+		Redirects,
+		FeedSubscribers,
+		FeedDroppedSubscribers,
+		WebhookDeliveries,
+	)
+
+	// Expose both results from the start, so rates can be computed before the first miss
+	Redirects.WithLabelValues("hit")
+	Redirects.WithLabelValues("miss")
+}
+
+// RedirectHit records a short URL that resolved to a link.
+func RedirectHit() {
+	Redirects.WithLabelValues("hit").Inc()
+}
+
+// RedirectMiss records a short URL that did not resolve to any link.
+func RedirectMiss() {
+	Redirects.WithLabelValues("miss").Inc()
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolAcquiredConns = prometheus.NewDesc(
+		"refero_db_pool_acquired_connections", "Number of connections currently in use.", []string{"pool"}, nil)
+	poolIdleConns = prometheus.NewDesc(
+		"refero_db_pool_idle_connections", "Number of idle connections in the pool.", []string{"pool"}, nil)
+	poolTotalConns = prometheus.NewDesc(
+		"refero_db_pool_total_connections", "Total number of connections in the pool.", []string{"pool"}, nil)
+	poolMaxConns = prometheus.NewDesc(
+		"refero_db_pool_max_connections", "Maximum size of the pool.", []string{"pool"}, nil)
+	poolAcquires = prometheus.NewDesc(
+		"refero_db_pool_acquires_total", "Number of successful connection acquires.", []string{"pool"}, nil)
+	poolEmptyAcquires = prometheus.NewDesc(
+		"refero_db_pool_empty_acquires_total", "Number of acquires that had to wait for a connection.", []string{"pool"}, nil)
+	poolAcquireWait = prometheus.NewDesc(
+		"refero_db_pool_acquire_wait_seconds_total", "Total time spent waiting for a connection.", []string{"pool"}, nil)
+)
+
+// poolCollector reads the pgxpool statistics on every scrape.
+type poolCollector struct {
+	name string
+	pool *pgxpool.Pool
+}
+
+// RegisterPool exposes the statistics of the pool under the given name, e.g. "primary" or "replica".
+func RegisterPool(name string, pool *pgxpool.Pool) error {
+	return Registry.Register(&poolCollector{name, pool})
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquires
+	ch <- poolEmptyAcquires
+	ch <- poolAcquireWait
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(stat.TotalConns()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolAcquireWait, prometheus.CounterValue, stat.AcquireDuration().Seconds(), c.name)
+}
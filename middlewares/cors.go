@@ -3,33 +3,165 @@ package middlewares
 import (
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
-	allowedOrigins := []string{"http://localhost:5173"}
+// CORSPolicy lists the cross-origin requests browsers are allowed to make.
+// Origins may be "*" or use a wildcard for subdomains, e.g. "https://*.example.com".
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORSRoute applies a policy to the requests whose path starts with Prefix.
+type CORSRoute struct {
+	Prefix string
+	Policy CORSPolicy
+}
+
+// corsPolicy is a CORSPolicy with its header values prepared once.
+type corsPolicy struct {
+	CORSPolicy
+	anyOrigin      bool
+	methods        string
+	headers        string
+	exposedHeaders string
+	maxAge         string
+}
+
+func newCORSPolicy(policy CORSPolicy) *corsPolicy {
+	return &corsPolicy{
+		CORSPolicy:     policy,
+		anyOrigin:      slices.Contains(policy.AllowedOrigins, "*"),
+		methods:        strings.Join(policy.AllowedMethods, ", "),
+		headers:        strings.Join(policy.AllowedHeaders, ", "),
+		exposedHeaders: strings.Join(policy.ExposedHeaders, ", "),
+		maxAge:         strconv.Itoa(int(policy.MaxAge.Seconds())),
+	}
+}
+
+// CORS applies the policy of the first route whose prefix matches the request path,
+// or the default policy. Preflight requests from allowed origins are answered with 204,
+// and those from other origins, or for methods that aren't allowed, with 403.
+func CORS(policy CORSPolicy, routes []CORSRoute) gin.HandlerFunc {
+	defaultPolicy := newCORSPolicy(policy)
+
+	prefixes := make([]string, len(routes))
+	policies := make([]*corsPolicy, len(routes))
+	for i, route := range routes {
+		prefixes[i] = route.Prefix
+		policies[i] = newCORSPolicy(route.Policy)
+	}
 
 	return func(c *gin.Context) {
-		isOriginAllowed := func(origin string, allowedOrigins []string) bool {
-			return slices.Contains(allowedOrigins, origin)
+		// Responses differ by origin, so caches must not share them across origins
+		c.Writer.Header().Add("Vary", "Origin")
+
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		policy := defaultPolicy
+		for i, prefix := range prefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				policy = policies[i]
+				break
+			}
 		}
 
-		origin := c.Request.Header.Get("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		allowed := policy.allowsOrigin(origin)
 
-		if isOriginAllowed(origin, allowedOrigins) {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-			c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, PUT, DELETE")
-			c.Writer.Header().Set("Access-Control-Max-Age", "86400")
+		if !preflight {
+			if allowed {
+				policy.setOriginHeaders(c, origin)
+				if policy.exposedHeaders != "" {
+					c.Header("Access-Control-Expose-Headers", policy.exposedHeaders)
+				}
+			}
+			c.Next()
+			return
 		}
 
-		// Handle preflight OPTIONS requests by aborting with status 204
-		if c.Request.Method == http.MethodOptions {
-			c.AbortWithStatus(http.StatusNoContent)
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		method := c.GetHeader("Access-Control-Request-Method")
+		if !allowed || !slices.Contains(policy.AllowedMethods, method) {
+			c.AbortWithStatus(http.StatusForbidden)
 			return
 		}
 
-		c.Next()
+		policy.setOriginHeaders(c, origin)
+		c.Header("Access-Control-Allow-Methods", policy.methods)
+		if policy.headers != "" {
+			c.Header("Access-Control-Allow-Headers", policy.headers)
+		}
+		c.Header("Access-Control-Max-Age", policy.maxAge)
+
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func (p *corsPolicy) setOriginHeaders(c *gin.Context, origin string) {
+	if p.anyOrigin && !p.AllowCredentials {
+		c.Header("Access-Control-Allow-Origin", "*")
+	} else {
+		c.Header("Access-Control-Allow-Origin", origin)
+	}
+
+	if p.AllowCredentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (p *corsPolicy) allowsOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+
+	for _, pattern := range p.AllowedOrigins {
+		if matchOrigin(pattern, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOrigin compares an origin with a pattern, where "https://*.example.com"
+// matches any subdomain of example.com over https, but not example.com itself.
+func matchOrigin(pattern, origin string) bool {
+	if strings.EqualFold(pattern, origin) {
+		return true
+	}
+
+	prefix, suffix, ok := strings.Cut(pattern, "*")
+	if !ok {
+		return false
+	}
+
+	origin = strings.ToLower(origin)
+	prefix, suffix = strings.ToLower(prefix), strings.ToLower(suffix)
+	if len(origin) <= len(prefix)+len(suffix) || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+
+	// The wildcard may only stand for subdomain labels
+	subdomain := origin[len(prefix) : len(origin)-len(suffix)]
+	for _, r := range subdomain {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,246 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"https://example.com", "https://example.com", true},
+		{"https://example.com", "https://EXAMPLE.com", true},
+		{"https://example.com", "https://app.example.com", false},
+		{"https://example.com", "http://example.com", false},
+		{"https://example.com", "https://example.com:8443", false},
+		{"https://example.com:8443", "https://example.com:8443", true},
+		{"https://example.com:8443", "https://example.com", false},
+
+		// Wildcards match subdomains at any depth, but not the apex domain
+		{"https://*.example.com", "https://app.example.com", true},
+		{"https://*.example.com", "https://App.Example.com", true},
+		{"https://*.example.com", "https://eu.app.example.com", true},
+		{"https://*.example.com", "https://my-app1.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://.example.com", false},
+
+		// The scheme and port must match
+		{"https://*.example.com", "http://app.example.com", false},
+		{"https://*.example.com", "https://app.example.com:8443", false},
+		{"https://*.example.com:8443", "https://app.example.com:8443", true},
+		{"https://*.example.com:8443", "https://app.example.com", false},
+
+		// Look-alike origins
+		{"https://*.example.com", "https://evilexample.com", false},
+		{"https://*.example.com", "https://app.example.com.evil.com", false},
+		{"https://*.example.com", "https://evil.com/.example.com", false},
+		{"https://*.example.com", "https://evil.com?.example.com", false},
+		{"https://*.example.com", "https://user@evil.com#.example.com", false},
+		{"https://*.example.com", "https://evil.com:1.example.com", false},
+		{"https://example.com", "https://example.com.evil.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchOrigin(tt.pattern, tt.origin); got != tt.want {
+			t.Errorf("matchOrigin(%q, %q): got %t, want %t", tt.pattern, tt.origin, got, tt.want)
+		}
+	}
+}
+
+func newCORSHandler(policy CORSPolicy, routes ...CORSRoute) http.Handler {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(CORS(policy, routes))
+
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	engine.GET("/api/v1/link", ok)
+	engine.DELETE("/api/v1/link", ok)
+	engine.GET("/api/v1/link/r/:shortUrl", ok)
+	engine.OPTIONS("/api/v1/link", ok)
+
+	return engine
+}
+
+// serveCORS sends a request from the origin, unless it is empty, as a preflight if method is set.
+func serveCORS(handler http.Handler, httpMethod, path, origin, method string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(httpMethod, path, nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if method != "" {
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", "content-type")
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+func TestCORS(t *testing.T) {
+	policy := CORSPolicy{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.preview.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "If-Match"},
+		ExposedHeaders: []string{"ETag"},
+		MaxAge:         time.Hour,
+	}
+	allowCredentials := policy
+	allowCredentials.AllowedOrigins = []string{"*"}
+	allowCredentials.AllowCredentials = true
+	anyOrigin := policy
+	anyOrigin.AllowedOrigins = []string{"*"}
+
+	handler := newCORSHandler(policy, CORSRoute{Prefix: "/api/v1/link/r", Policy: anyOrigin})
+
+	tests := []struct {
+		name      string
+		handler   http.Handler
+		method    string
+		path      string
+		origin    string
+		preflight string // Access-Control-Request-Method of a preflight request
+		status    int
+		headers   map[string]string // Expected response headers, empty for absent ones
+	}{
+		{
+			name:   "same origin request",
+			method: http.MethodGet, path: "/api/v1/link",
+			status:  http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "", "Vary": "Origin"},
+		},
+		{
+			name:   "allowed origin",
+			method: http.MethodGet, path: "/api/v1/link", origin: "https://app.example.com",
+			status: http.StatusOK,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Expose-Headers":    "ETag",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		{
+			name:   "wildcard subdomain",
+			method: http.MethodGet, path: "/api/v1/link", origin: "https://pr-42.preview.example.com",
+			status:  http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "https://pr-42.preview.example.com"},
+		},
+		{
+			name:   "disallowed origin",
+			method: http.MethodGet, path: "/api/v1/link", origin: "https://evilexample.com",
+			status:  http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Expose-Headers": ""},
+		},
+		{
+			name:   "preflight",
+			method: http.MethodOptions, path: "/api/v1/link", origin: "https://app.example.com", preflight: "POST",
+			status: http.StatusNoContent,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "Content-Type, If-Match",
+				"Access-Control-Max-Age":       "3600",
+			},
+		},
+		{
+			name:   "preflight from a disallowed origin",
+			method: http.MethodOptions, path: "/api/v1/link", origin: "https://preview.example.com", preflight: "POST",
+			status:  http.StatusForbidden,
+			headers: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+		},
+		{
+			name:   "preflight from another scheme",
+			method: http.MethodOptions, path: "/api/v1/link", origin: "http://app.example.com", preflight: "GET",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "preflight from another port",
+			method: http.MethodOptions, path: "/api/v1/link", origin: "https://app.example.com:8443", preflight: "GET",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "preflight for a disallowed method",
+			method: http.MethodOptions, path: "/api/v1/link", origin: "https://app.example.com", preflight: "DELETE",
+			status:  http.StatusForbidden,
+			headers: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "options without a preflight",
+			method: http.MethodOptions, path: "/api/v1/link", origin: "https://app.example.com",
+			status:  http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "https://app.example.com"},
+		},
+		{
+			name:   "route override",
+			method: http.MethodGet, path: "/api/v1/link/r/golang", origin: "https://evilexample.com",
+			status:  http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+		{
+			name:   "preflight of a route override",
+			method: http.MethodOptions, path: "/api/v1/link/r/golang", origin: "https://evilexample.com", preflight: "GET",
+			status:  http.StatusNoContent,
+			headers: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Methods": "GET, POST"},
+		},
+		{
+			name:    "credentials for any origin",
+			handler: newCORSHandler(allowCredentials),
+			method:  http.MethodGet, path: "/api/v1/link", origin: "https://evilexample.com",
+			status: http.StatusOK,
+			// Browsers reject "*" along with credentials, so the origin is echoed
+			headers: map[string]string{"Access-Control-Allow-Origin": "https://evilexample.com", "Access-Control-Allow-Credentials": "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.handler
+			if h == nil {
+				h = handler
+			}
+
+			res := serveCORS(h, tt.method, tt.path, tt.origin, tt.preflight)
+			if res.Code != tt.status {
+				t.Errorf("got status %d, want %d", res.Code, tt.status)
+			}
+			for name, want := range tt.headers {
+				if name == "Vary" {
+					if got := res.Header().Values(name); len(got) == 0 || got[0] != want {
+						t.Errorf("%s: got %q, want %q", name, got, want)
+					}
+					continue
+				}
+				if got := res.Header().Get(name); got != want {
+					t.Errorf("%s: got %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSPreflightVary(t *testing.T) {
+	handler := newCORSHandler(CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}, AllowedMethods: []string{"GET"}})
+
+	// Preflight responses depend on the requested method and headers, even when they are refused
+	for _, origin := range []string{"https://app.example.com", "https://evilexample.com"} {
+		res := serveCORS(handler, http.MethodOptions, "/api/v1/link", origin, "GET")
+		want := []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}
+		got := res.Header().Values("Vary")
+		if len(got) != len(want) {
+			t.Errorf("Vary from %s: got %q, want %q", origin, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Vary from %s: got %q, want %q", origin, got, want)
+			}
+		}
+	}
+}
@@ -1,35 +1,31 @@
 package middlewares
 
 import (
-	"log"
-	"net/http"
-
 	"github.com/OmprakashD20/refero-api/errors"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ErrorHandler is a middleware that handles errors which occurs during request processing.
-// If an error is found in the context, it logs the error and responds with an appropriate HTTP status code and message.
+// If an error is found in the context, it responds with an RFC 7807 problem details body.
+// The error itself is logged along with the request by the Logger middleware.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
 		err := c.Errors.Last()
 		if err != nil {
-			log.Println(err.Err.Error())
-
 			if e, ok := err.Err.(*errors.HTTPError); ok {
-				c.JSON(e.StatusCode, gin.H{
-					"error": e.ErrorMsg,
-				})
+				WriteProblem(c, e.Resolve())
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": "Internal Server Error",
-				})
+				WriteProblem(c, errors.InternalServerError())
 			}
-
-			c.Abort()
 		}
 	}
 }
+
+// WriteProblem aborts the request and responds with the error as application/problem+json.
+func WriteProblem(c *gin.Context, err *errors.HTTPError) {
+	c.Header("Content-Type", errors.ProblemContentType)
+	c.AbortWithStatusJSON(err.StatusCode, err.Problem(c.Request.URL.Path))
+}
@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	stderrors "errors"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/OmprakashD20/refero-api/errors"
+)
+
+// Logger logs one line per request, including the error and its cause when the request failed.
+// Server errors are logged at error level and client errors at warn level.
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+
+		if err := c.Errors.Last(); err != nil {
+			var httpErr *errors.HTTPError
+			if stderrors.As(err.Err, &httpErr) {
+				attrs = append(attrs, slog.String("error", httpErr.ErrorMsg), slog.String("code", httpErr.ErrorCode()))
+				if httpErr.Cause != nil {
+					attrs = append(attrs, slog.String("cause", httpErr.Cause.Error()))
+				}
+			} else {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
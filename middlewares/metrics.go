@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/OmprakashD20/refero-api/metrics"
+)
+
+// unmatchedRoute labels requests that did not match any route,
+// so that arbitrary paths don't create new series.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the duration and the number of in-flight requests per route template.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		inFlight := metrics.HTTPRequestsInFlight.WithLabelValues(c.Request.Method, route)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		c.Next()
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/logging"
+	"github.com/OmprakashD20/refero-api/ratelimit"
+)
+
+// RateLimit applies the policy of the first matching rule to every client, identified by its user ID
+// once it is authenticated, by the API key it sends in apiKeyHeader, if that is one of apiKeys,
+// or else by its IP address.
+// The state of the bucket is reported in the RateLimit-* headers of every response.
+// Requests are let through if the store fails, so an outage of a shared store doesn't take the API down.
+func RateLimit(store ratelimit.Store, rules []ratelimit.Rule, apiKeyHeader string, apiKeys []string) gin.HandlerFunc {
+	// Keep the keys themselves out of the store
+	known := make(map[string]struct{}, len(apiKeys))
+	for _, key := range apiKeys {
+		known[hashKey(key)] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		policy, ok := ratelimit.Match(rules, c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		key := policy.Name + ":" + clientKey(c, apiKeyHeader, known)
+		result, err := store.Take(c.Request.Context(), key, policy)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "rate limit store failed", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(int(result.Reset.Seconds())))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", policy.Requests, int(policy.Period.Seconds()), policy.Burst))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+			c.Error(errors.TooManyRequests(errors.ErrRateLimited))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context, apiKeyHeader string, known map[string]struct{}) string {
+	if id := logging.UserID(c.Request.Context()); id != "" {
+		return "user:" + id
+	}
+
+	if apiKeyHeader != "" {
+		if key := c.GetHeader(apiKeyHeader); key != "" {
+			// Unknown keys are limited by IP, or each made up key would get a bucket of its own
+			sum := hashKey(key)
+			if _, ok := known[sum]; ok {
+				return "key:" + sum
+			}
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
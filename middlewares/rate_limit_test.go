@@ -0,0 +1,176 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/OmprakashD20/refero-api/logging"
+	"github.com/OmprakashD20/refero-api/ratelimit"
+)
+
+// fakeLimiter records the keys it is asked for and returns the same result for all of them.
+type fakeLimiter struct {
+	result ratelimit.Result
+	err    error
+	keys   []string
+}
+
+func (s *fakeLimiter) Take(ctx context.Context, key string, policy ratelimit.Policy) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	return s.result, s.err
+}
+
+var testRules = []ratelimit.Rule{
+	{Prefix: "/api/v1/link", Methods: []string{http.MethodPost}, Policy: ratelimit.Policy{Name: "write", Requests: 30, Period: time.Minute, Burst: 10}},
+	{Prefix: "/api/v1/link", Policy: ratelimit.Policy{Name: "read", Requests: 300, Period: time.Minute, Burst: 60}},
+}
+
+func newRateLimitHandler(store ratelimit.Store) http.Handler {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(ErrorHandler())
+
+	// Stands in for authentication, which runs before the rate limit
+	engine.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			c.Request = c.Request.WithContext(logging.WithUserID(c.Request.Context(), id))
+		}
+	})
+	engine.Use(RateLimit(store, testRules, "X-API-Key", []string{"known-key"}))
+
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	engine.GET("/api/v1/link", ok)
+	engine.POST("/api/v1/link", ok)
+	engine.GET("/health", ok)
+
+	return engine
+}
+
+func serveRateLimited(handler http.Handler, method, path string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		header []string
+		want   string
+	}{
+		{"ip", http.MethodGet, nil, "read:ip:192.0.2.1"},
+		{"policy of the method", http.MethodPost, nil, "write:ip:192.0.2.1"},
+		{"known api key", http.MethodGet, []string{"X-API-Key", "known-key"}, "read:key:" + hashKey("known-key")},
+		{"unknown api key", http.MethodGet, []string{"X-API-Key", "made-up-key"}, "read:ip:192.0.2.1"},
+		{"user", http.MethodGet, []string{"X-Test-User", "user-1"}, "read:user:user-1"},
+		{"user with an api key", http.MethodGet, []string{"X-Test-User", "user-1", "X-API-Key", "known-key"}, "read:user:user-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeLimiter{result: ratelimit.Result{Allowed: true}}
+			serveRateLimited(newRateLimitHandler(store), tt.method, "/api/v1/link", tt.header...)
+
+			if len(store.keys) != 1 || store.keys[0] != tt.want {
+				t.Errorf("got keys %q, want %q", store.keys, tt.want)
+			}
+		})
+	}
+
+	// The API keys themselves are not stored
+	store := &fakeLimiter{result: ratelimit.Result{Allowed: true}}
+	serveRateLimited(newRateLimitHandler(store), http.MethodGet, "/api/v1/link", "X-API-Key", "known-key")
+	if len(store.keys) != 1 || store.keys[0] == "read:key:known-key" {
+		t.Errorf("got keys %q", store.keys)
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	store := &fakeLimiter{result: ratelimit.Result{Allowed: true, Limit: 60, Remaining: 59, Reset: time.Second}}
+	handler := newRateLimitHandler(store)
+
+	res := serveRateLimited(handler, http.MethodGet, "/api/v1/link")
+	if res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+	for name, want := range map[string]string{
+		"RateLimit-Limit":     "60",
+		"RateLimit-Remaining": "59",
+		"RateLimit-Reset":     "1",
+		"RateLimit-Policy":    "300;w=60;burst=60",
+		"Retry-After":         "",
+	} {
+		if got := res.Header().Get(name); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+
+	store.result = ratelimit.Result{Allowed: false, Limit: 10, Remaining: 0, Reset: 20 * time.Second, RetryAfter: 2 * time.Second}
+	res = serveRateLimited(handler, http.MethodPost, "/api/v1/link")
+	if res.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want %d: %s", res.Code, http.StatusTooManyRequests, res.Body)
+	}
+	for name, want := range map[string]string{
+		"RateLimit-Limit":     "10",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "20",
+		"RateLimit-Policy":    "30;w=60;burst=10",
+		"Retry-After":         "2",
+	} {
+		if got := res.Header().Get(name); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRateLimitPassThrough(t *testing.T) {
+	// Routes without a rule aren't limited
+	store := &fakeLimiter{result: ratelimit.Result{Allowed: false}}
+	res := serveRateLimited(newRateLimitHandler(store), http.MethodGet, "/health")
+	if res.Code != http.StatusOK || len(store.keys) != 0 {
+		t.Errorf("route without a rule: got status %d and keys %q", res.Code, store.keys)
+	}
+
+	// Requests are let through while the store fails
+	store = &fakeLimiter{err: errors.New("connection refused")}
+	res = serveRateLimited(newRateLimitHandler(store), http.MethodGet, "/api/v1/link")
+	if res.Code != http.StatusOK || res.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("failed store: got status %d and headers %v", res.Code, res.Header())
+	}
+}
+
+func TestRateLimitMemoryStore(t *testing.T) {
+	handler := newRateLimitHandler(ratelimit.NewMemoryStore())
+
+	// The write policy allows a burst of 10 requests per client
+	for i := range 10 {
+		if res := serveRateLimited(handler, http.MethodPost, "/api/v1/link"); res.Code != http.StatusOK {
+			t.Fatalf("request %d: got status %d", i+1, res.Code)
+		}
+	}
+	res := serveRateLimited(handler, http.MethodPost, "/api/v1/link")
+	if res.Code != http.StatusTooManyRequests || res.Header().Get("Retry-After") != "2" {
+		t.Errorf("request over the burst: got status %d, Retry-After %q", res.Code, res.Header().Get("Retry-After"))
+	}
+
+	// Other clients and other policies have buckets of their own
+	if res := serveRateLimited(handler, http.MethodPost, "/api/v1/link", "X-Test-User", "user-1"); res.Code != http.StatusOK {
+		t.Errorf("request of another client: got status %d", res.Code)
+	}
+	if res := serveRateLimited(handler, http.MethodGet, "/api/v1/link"); res.Code != http.StatusOK {
+		t.Errorf("request under another policy: got status %d", res.Code)
+	}
+}
@@ -1,10 +1,11 @@
 package middlewares
 
 import (
-	"log"
-	"net/http"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
 
-	// "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/errors"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,11 +17,14 @@ func RecoveryMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if rec := recover(); rec != nil {
-				log.Printf("PANIC RECOVERED: %v\n", rec)
+				slog.ErrorContext(c.Request.Context(), "panic recovered",
+					slog.Any("panic", rec),
+					slog.String("stack", string(debug.Stack())),
+				)
 
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": "Internal Server Error",
-				})
+				// Record the panic so the request log line carries it as the error
+				c.Error(fmt.Errorf("panic: %v", rec))
+				WriteProblem(c, errors.InternalServerError())
 			}
 		}()
 
@@ -0,0 +1,265 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemas generates the schemas of Go types, registering named structs as components.
+// Fields are named by their json tag, and the constraints of their binding tag are translated
+// to the matching JSON Schema keywords.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// of returns the schema of the type, with the constraints of the binding rules applied.
+// Rules following "dive" apply to the elements of slices and maps.
+func (g *schemas) of(t reflect.Type, rules []string) *Schema {
+	t = deref(t)
+	outer, inner := splitDive(rules)
+
+	var s *Schema
+	switch {
+	case t == timeType:
+		s = &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType || t.Kind() == reflect.Interface:
+		// Any JSON value
+		s = &Schema{}
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			s = &Schema{Type: "string"}
+		case reflect.Bool:
+			s = &Schema{Type: "boolean"}
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			s = &Schema{Type: "integer", Format: "int32"}
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+			s = &Schema{Type: "integer", Format: "int64"}
+		case reflect.Float32, reflect.Float64:
+			s = &Schema{Type: "number"}
+		case reflect.Slice, reflect.Array:
+			if t.Elem().Kind() == reflect.Uint8 {
+				s = &Schema{Type: "string", Format: "byte"}
+			} else {
+				s = &Schema{Type: "array", Items: g.of(t.Elem(), inner)}
+			}
+		case reflect.Map:
+			s = &Schema{Type: "object", AdditionalProperties: g.of(t.Elem(), inner)}
+		case reflect.Struct:
+			s = g.ref(t)
+		default:
+			s = &Schema{}
+		}
+	}
+
+	applyRules(s, t.Kind(), outer)
+	return s
+}
+
+// ref returns a reference to the component of a named struct, generating it on first use.
+// Anonymous structs are inlined.
+func (g *schemas) ref(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.object(t)
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, taken := g.components[name]; taken {
+			// Another package has a type with the same name
+			pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+			name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+		}
+		g.names[t] = name
+
+		// Register the name first, so that recursive types refer to themselves
+		g.components[name] = nil
+		g.components[name] = g.object(t)
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *schemas) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.fields(t, s)
+	return s
+}
+
+func (g *schemas) fields(t reflect.Type, s *Schema) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Fields of embedded structs are promoted, as encoding/json does
+		if field.Anonymous && name == "" && deref(field.Type).Kind() == reflect.Struct {
+			g.fields(deref(field.Type), s)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		rules := bindingRules(field)
+		prop := g.of(field.Type, rules)
+		if hasRule(rules, "required") {
+			s.Required = append(s.Required, name)
+		} else if field.Type.Kind() == reflect.Pointer {
+			prop = nullable(prop)
+		}
+
+		s.Properties[name] = prop
+	}
+}
+
+// parameters documents the fields of a params struct, named by the given tag ("uri" or "form").
+func (g *schemas) parameters(v any, in, tag string) []Parameter {
+	t := deref(reflect.TypeOf(v))
+
+	var params []Parameter
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		rules := bindingRules(field)
+		params = append(params, Parameter{
+			Name:     name,
+			In:       in,
+			Required: in == "path" || hasRule(rules, "required"),
+			Schema:   g.of(field.Type, rules),
+		})
+	}
+
+	return params
+}
+
+// nullable allows the value described by the schema to be null as well.
+func nullable(s *Schema) *Schema {
+	if typ, ok := s.Type.(string); ok && s.Ref == "" {
+		s.Type = []string{typ, "null"}
+		return s
+	}
+	return &Schema{OneOf: []*Schema{s, {Type: "null"}}}
+}
+
+// applyRules translates the validator tags to JSON Schema keywords.
+// Tags without an equivalent, such as "required", are left to the caller or ignored.
+func applyRules(s *Schema, kind reflect.Kind, rules []string) {
+	for _, rule := range rules {
+		tag, param, _ := strings.Cut(rule, "=")
+
+		switch tag {
+		case "uuid":
+			s.Format = "uuid"
+		case "url", "http_url", "uri":
+			s.Format = "uri"
+		case "email":
+			s.Format = "email"
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				s.Enum = append(s.Enum, value)
+			}
+		case "min", "gte":
+			setBound(s, kind, param, &s.MinLength, &s.MinItems, &s.Minimum)
+		case "max", "lte":
+			setBound(s, kind, param, &s.MaxLength, &s.MaxItems, &s.Maximum)
+		case "len":
+			setBound(s, kind, param, &s.MinLength, &s.MinItems, &s.Minimum)
+			setBound(s, kind, param, &s.MaxLength, &s.MaxItems, &s.Maximum)
+		case "gt":
+			if n, err := strconv.ParseFloat(param, 64); err == nil && isNumber(kind) {
+				s.ExclusiveMinimum = &n
+			}
+		case "lt":
+			if n, err := strconv.ParseFloat(param, 64); err == nil && isNumber(kind) {
+				s.ExclusiveMaximum = &n
+			}
+		}
+	}
+}
+
+// setBound sets the length, item count or value bound, depending on what the validator compares for the kind.
+func setBound(s *Schema, kind reflect.Kind, param string, length, items *(*int), value *(*float64)) {
+	switch {
+	case kind == reflect.String:
+		if n, err := strconv.Atoi(param); err == nil {
+			*length = &n
+		}
+	case kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map:
+		if n, err := strconv.Atoi(param); err == nil {
+			*items = &n
+		}
+	case isNumber(kind):
+		if n, err := strconv.ParseFloat(param, 64); err == nil {
+			*value = &n
+		}
+	}
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return reflect.Int <= kind && kind <= reflect.Float64
+}
+
+// bindingRules returns the validator tags of the field.
+func bindingRules(field reflect.StructField) []string {
+	tag := field.Tag.Get("binding")
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+// splitDive separates the rules of a collection from the rules of its elements.
+func splitDive(rules []string) (outer, inner []string) {
+	for i, rule := range rules {
+		if rule == "dive" {
+			return rules[:i], rules[i+1:]
+		}
+	}
+	return rules, nil
+}
+
+func hasRule(rules []string, name string) bool {
+	outer, _ := splitDive(rules)
+	for _, rule := range outer {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
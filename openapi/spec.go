@@ -0,0 +1,300 @@
+package openapi
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+)
+
+// Route documents an operation. Params and Query are the structs the route binds its path
+// and query parameters to, and Body is the payload it binds the request body to.
+type Route struct {
+	Method      string
+	Path        string // Relative to the group the route is registered on, in gin syntax
+	OperationID string // Derived from the method and path when empty
+	Summary     string
+	Description string
+	Params      any
+	Query       any
+	Headers     []Header // Request headers the route reads
+	Body        any
+	Responses   []Response
+}
+
+// Response documents a response of a route. A nil Body means the response has no content.
+// Status 0 documents the errors of the route, which are otherwise problem details objects.
+type Response struct {
+	Status      int
+	Description string
+	Headers     []Header
+	Body        any
+}
+
+type Header struct {
+	Name        string
+	Description string
+}
+
+// Content holds the bodies of a request or response which can be sent in several media types.
+// Bodies are otherwise sent as JSON.
+type Content map[string]any
+
+// JSONPatch is the schema of an RFC 6902 JSON Patch document.
+var JSONPatch = &Schema{
+	Type: "array",
+	Items: &Schema{
+		Type:     "object",
+		Required: []string{"op", "path"},
+		Properties: map[string]*Schema{
+			"op":    {Type: "string", Enum: []any{"add", "remove", "replace", "move", "copy", "test"}},
+			"path":  {Type: "string", Description: "JSON Pointer to the target location"},
+			"from":  {Type: "string", Description: "JSON Pointer to the source location of move and copy"},
+			"value": {},
+		},
+	},
+}
+
+// Spec collects the documentation of the routes, keyed by method and full path.
+// Routes are described where they are mounted, so that the generated document
+// only lists the routes an instance actually serves.
+type Spec struct {
+	info   Info
+	tags   []string
+	routes map[string]documented
+
+	once     sync.Once
+	document []byte
+}
+
+type documented struct {
+	tag   string
+	route Route
+}
+
+func New(info Info) *Spec {
+	return &Spec{info: info, routes: make(map[string]documented)}
+}
+
+// Describe documents routes registered on the group under the given tag.
+func (s *Spec) Describe(group interface{ BasePath() string }, tag string, routes ...Route) {
+	if !slices.Contains(s.tags, tag) {
+		s.tags = append(s.tags, tag)
+	}
+	for _, route := range routes {
+		s.routes[key(route.Method, joinPaths(group.BasePath(), route.Path))] = documented{tag, route}
+	}
+}
+
+// Missing lists the registered routes which have not been described.
+func (s *Spec) Missing(routes gin.RoutesInfo) []string {
+	var missing []string
+	for _, r := range routes {
+		if _, ok := s.routes[key(r.Method, r.Path)]; !ok {
+			missing = append(missing, key(r.Method, r.Path))
+		}
+	}
+	return missing
+}
+
+// Document generates the OpenAPI document of the registered routes.
+func (s *Spec) Document(routes gin.RoutesInfo) *Document {
+	g := newSchemas()
+	problem := g.of(reflect.TypeFor[errs.Problem](), nil)
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    s.info,
+		Paths:   make(map[string]PathItem),
+	}
+
+	used := make(map[string]bool)
+	for _, r := range routes {
+		d, ok := s.routes[key(r.Method, r.Path)]
+		if !ok {
+			continue
+		}
+		used[d.tag] = true
+
+		p := openAPIPath(r.Path)
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = make(PathItem)
+		}
+		doc.Paths[p][strings.ToLower(r.Method)] = d.operation(g, r.Method, p, problem)
+	}
+
+	for _, tag := range s.tags {
+		if used[tag] {
+			doc.Tags = append(doc.Tags, Tag{Name: tag})
+		}
+	}
+	doc.Components.Schemas = g.components
+
+	return doc
+}
+
+// Handler serves the document of the routes, which is generated on the first request
+// since routes are only all registered once the server starts.
+func (s *Spec) Handler(routes func() gin.RoutesInfo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.once.Do(func() {
+			// The document is made of plain values, so it always marshals
+			s.document, _ = json.Marshal(s.Document(routes()))
+		})
+		c.Data(http.StatusOK, "application/json", s.document)
+	}
+}
+
+func (d documented) operation(g *schemas, method, path string, problem *Schema) *Operation {
+	route := d.route
+
+	op := &Operation{
+		OperationID: route.OperationID,
+		Tags:        []string{d.tag},
+		Summary:     route.Summary,
+		Description: route.Description,
+		Responses:   make(map[string]*ResponseObject),
+	}
+	if op.OperationID == "" {
+		op.OperationID = operationID(method, path)
+	}
+
+	if route.Params != nil {
+		op.Parameters = append(op.Parameters, g.parameters(route.Params, "path", "uri")...)
+	}
+	// Path parameters bound by no struct, such as wildcards, are plain strings
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		if !slices.ContainsFunc(op.Parameters, func(p Parameter) bool { return p.In == "path" && p.Name == match[1] }) {
+			op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	if route.Query != nil {
+		op.Parameters = append(op.Parameters, g.parameters(route.Query, "query", "form")...)
+	}
+	for _, header := range route.Headers {
+		op.Parameters = append(op.Parameters, Parameter{Name: header.Name, In: "header", Description: header.Description, Schema: &Schema{Type: "string"}})
+	}
+
+	if route.Body != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: content(g, route.Body)}
+	}
+
+	for _, response := range route.Responses {
+		res := &ResponseObject{Description: response.Description}
+		if res.Description == "" {
+			res.Description = cmp.Or(http.StatusText(response.Status), "Error")
+		}
+		for _, header := range response.Headers {
+			if res.Headers == nil {
+				res.Headers = make(map[string]HeaderObject)
+			}
+			res.Headers[header.Name] = HeaderObject{Description: header.Description, Schema: &Schema{Type: "string"}}
+		}
+		if response.Body != nil {
+			res.Content = content(g, response.Body)
+		}
+		if response.Status == 0 {
+			op.Responses["default"] = res
+		} else {
+			op.Responses[strconv.Itoa(response.Status)] = res
+		}
+	}
+
+	// Every route may fail
+	if op.Responses["default"] == nil {
+		op.Responses["default"] = &ResponseObject{
+			Description: "Error",
+			Content:     map[string]MediaType{errs.ProblemContentType: {Schema: problem}},
+		}
+	}
+
+	return op
+}
+
+// content documents a body, which is either a value of the type sent as JSON,
+// a schema or the bodies of several media types.
+func content(g *schemas, body any) map[string]MediaType {
+	bodies, ok := body.(Content)
+	if !ok {
+		bodies = Content{"application/json": body}
+	}
+
+	media := make(map[string]MediaType, len(bodies))
+	for mediaType, body := range bodies {
+		schema, ok := body.(*Schema)
+		if !ok {
+			schema = g.of(reflect.TypeOf(body), nil)
+		}
+		media[mediaType] = MediaType{Schema: schema}
+	}
+	return media
+}
+
+var (
+	pathParam   = regexp.MustCompile(`\{([^}]+)\}`)
+	nonAlphaNum = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// openAPIPath converts the :name and *name parameters of a gin path to {name}.
+func openAPIPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID derives an identifier like getApiV1LinkById from the method and path.
+func operationID(method, path string) string {
+	var words, params []string
+	for _, segment := range strings.Split(path, "/") {
+		if match := pathParam.FindStringSubmatch(segment); match != nil {
+			// Parameters read as "By<Name>" after the resource
+			params = append(params, "By", match[1])
+		} else {
+			words = append(words, nonAlphaNum.Split(segment, -1)...)
+		}
+	}
+
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	for _, word := range append(words, params...) {
+		if word != "" {
+			id.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	if path == "/" {
+		id.WriteString("Root")
+	}
+
+	return id.String()
+}
+
+func key(method, path string) string {
+	return method + " " + path
+}
+
+// joinPaths joins the paths the way gin does, keeping the trailing slash of the relative path.
+func joinPaths(absolute, relative string) string {
+	if relative == "" {
+		return absolute
+	}
+
+	joined := path.Join(absolute, relative)
+	if strings.HasSuffix(relative, "/") && !strings.HasSuffix(joined, "/") {
+		return joined + "/"
+	}
+	return joined
+}
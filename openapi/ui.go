@@ -0,0 +1,48 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// initializer configures Swagger UI, replacing the one bundled with it which loads the petstore example.
+const initializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: %s,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// UI serves the Swagger UI bundled in the binary, browsing the document served at specURL.
+// It must be mounted on a wildcard route named filepath, such as /docs/*filepath.
+func UI(specURL string) gin.HandlerFunc {
+	url, _ := json.Marshal(specURL)
+	script := []byte(fmt.Sprintf(initializer, url))
+	files := http.FileServer(http.FS(swaggerFiles.FS))
+
+	return func(c *gin.Context) {
+		file := c.Param("filepath")
+		if file == "/swagger-initializer.js" {
+			c.Data(http.StatusOK, "text/javascript; charset=utf-8", script)
+			return
+		}
+
+		req := c.Request.Clone(c.Request.Context())
+		req.URL.Path = file
+		files.ServeHTTP(c.Writer, req)
+	}
+}
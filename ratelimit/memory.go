@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets that have refilled completely are dropped,
+// since they are indistinguishable from new ones.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	policy  Policy
+}
+
+// MemoryStore keeps token buckets in process memory.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now(), now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(policy.Burst), updated: now, policy: policy}
+		s.buckets[key] = b
+	}
+	b.refill(now)
+
+	result := Result{Limit: policy.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / policy.rate())
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((float64(policy.Burst) - b.tokens) / policy.rate())
+
+	return result, nil
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = min(float64(b.policy.Burst), b.tokens+elapsed*b.policy.rate())
+	b.updated = now
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.policy.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// seconds converts a fractional number of seconds to a duration, rounded up to the next second
+// as clients can only be told to wait whole seconds. It is rounded to the nanosecond first,
+// so that the error of the division can't add a second.
+func seconds(s float64) time.Duration {
+	d := time.Duration(math.Round(s * float64(time.Second)))
+	return (d + time.Second - 1) / time.Second * time.Second
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestStore returns a store whose clock only moves when the returned function is called.
+func newTestStore() (*MemoryStore, func(time.Duration)) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	s.lastSweep = now
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+func take(t *testing.T, s *MemoryStore, key string, policy Policy, want Result) {
+	t.Helper()
+
+	got, err := s.Take(context.Background(), key, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Take(%q): got %+v, want %+v", key, got, want)
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	s, advance := newTestStore()
+	// A token per second, up to three at once
+	policy := Policy{Name: "read", Requests: 60, Period: time.Minute, Burst: 3}
+
+	take(t, s, "a", policy, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second})
+	take(t, s, "a", policy, Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second})
+	take(t, s, "a", policy, Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second})
+	take(t, s, "a", policy, Result{Allowed: false, Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second})
+
+	// Other clients have buckets of their own
+	take(t, s, "b", policy, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second})
+
+	// Partial tokens are rounded up to whole seconds
+	advance(400 * time.Millisecond)
+	take(t, s, "a", policy, Result{Allowed: false, Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second})
+
+	advance(600 * time.Millisecond)
+	take(t, s, "a", policy, Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second})
+
+	// Buckets refill up to the burst only
+	advance(time.Hour)
+	take(t, s, "a", policy, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second})
+}
+
+func TestMemoryStoreSlowRefill(t *testing.T) {
+	s, advance := newTestStore()
+	// A token every 30 seconds
+	policy := Policy{Name: "write", Requests: 2, Period: time.Minute, Burst: 1}
+
+	take(t, s, "a", policy, Result{Allowed: true, Limit: 1, Remaining: 0, Reset: 30 * time.Second})
+	take(t, s, "a", policy, Result{Allowed: false, Limit: 1, Remaining: 0, Reset: 30 * time.Second, RetryAfter: 30 * time.Second})
+
+	advance(20 * time.Second)
+	take(t, s, "a", policy, Result{Allowed: false, Limit: 1, Remaining: 0, Reset: 10 * time.Second, RetryAfter: 10 * time.Second})
+
+	advance(10 * time.Second)
+	take(t, s, "a", policy, Result{Allowed: true, Limit: 1, Remaining: 0, Reset: 30 * time.Second})
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, advance := newTestStore()
+	fast := Policy{Name: "read", Requests: 60, Period: time.Minute, Burst: 3}
+	slow := Policy{Name: "write", Requests: 1, Period: time.Hour, Burst: 1}
+
+	take(t, s, "fast", fast, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second})
+	take(t, s, "slow", slow, Result{Allowed: true, Limit: 1, Remaining: 0, Reset: time.Hour})
+
+	// Buckets are only swept once the interval has passed
+	advance(sweepInterval - time.Second)
+	take(t, s, "other", fast, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second})
+	take(t, s, "other", fast, Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second})
+	take(t, s, "other", fast, Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second})
+	if got := len(s.buckets); got != 3 {
+		t.Errorf("buckets before the sweep: got %d, want 3", got)
+	}
+
+	// Full buckets are dropped, as they are the same as new ones
+	advance(time.Second)
+	take(t, s, "new", fast, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second})
+	if _, ok := s.buckets["fast"]; ok {
+		t.Error("the full bucket was not swept")
+	}
+	if _, ok := s.buckets["other"]; !ok {
+		t.Error("the bucket emptied a second ago was swept")
+	}
+	if _, ok := s.buckets["slow"]; !ok {
+		t.Error("the empty bucket was swept")
+	}
+
+	// The slow bucket keeps its state
+	take(t, s, "slow", slow, Result{Allowed: false, Limit: 1, Remaining: 0, Reset: 59 * time.Minute, RetryAfter: 59 * time.Minute})
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Policy allows Requests requests per Period on average, with bursts of up to Burst requests.
+type Policy struct {
+	Name     string
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// Result describes the state of a client's bucket after taking a token.
+type Result struct {
+	Allowed    bool
+	Limit      int           // Size of the bucket
+	Remaining  int           // Tokens left in the bucket
+	Reset      time.Duration // Until the bucket is full again
+	RetryAfter time.Duration // Until the next request is allowed, zero when Allowed
+}
+
+// Store keeps the token buckets of every client.
+// The in-memory store suits a single instance; a shared backend such as Redis
+// can implement the same interface when the API is scaled out.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+// rate returns how many tokens are added to a bucket per second.
+func (p Policy) rate() float64 {
+	return float64(p.Requests) / p.Period.Seconds()
+}
+
+// Rule applies a policy to the requests whose route template starts with Prefix
+// and, if Methods is not empty, whose method is one of Methods.
+type Rule struct {
+	Prefix  string
+	Methods []string
+	Policy  Policy
+}
+
+// Match returns the policy of the first rule matching the request.
+func Match(rules []Rule, method, route string) (Policy, bool) {
+	for _, rule := range rules {
+		if !strings.HasPrefix(route, rule.Prefix) {
+			continue
+		}
+		if len(rule.Methods) > 0 && !slices.Contains(rule.Methods, method) {
+			continue
+		}
+		return rule.Policy, true
+	}
+
+	return Policy{}, false
+}
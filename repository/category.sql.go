@@ -33,42 +33,147 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 	return id, err
 }
 
-const deleteCategory = `-- name: DeleteCategory :execrows
-DELETE FROM category WHERE id = $1
+const deleteCategory = `-- name: DeleteCategory :many
+WITH RECURSIVE subtree AS (
+    SELECT c.id FROM category c
+    WHERE c.id = $1 AND c.deleted_at IS NULL AND ($2::integer IS NULL OR c.version = $2)
+    UNION ALL
+    SELECT child.id FROM category child
+    JOIN subtree ON child.parent_id = subtree.id
+    WHERE child.deleted_at IS NULL
+)
+UPDATE category 
+SET deleted_at = now(), version = version + 1 
+WHERE id IN (SELECT id FROM subtree)
+RETURNING id, parent_id
 `
 
-// Delete category
+type DeleteCategoryParams struct {
+	ID      pgtype.UUID `db:"id" json:"id"`
+	Version *int32      `db:"version" json:"version"`
+}
+
+type DeleteCategoryRow struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	ParentID pgtype.UUID `db:"parent_id" json:"parentId"`
+}
+
+// Move a category and its subcategories to the trash, optionally only if it is still at the given version.
+// Returns the trashed categories.
 //
-//  DELETE FROM category WHERE id = $1
-func (q *Queries) DeleteCategory(ctx context.Context, id pgtype.UUID) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteCategory, id)
+//  WITH RECURSIVE subtree AS (
+//      SELECT c.id FROM category c
+//      WHERE c.id = $1 AND c.deleted_at IS NULL AND ($2::integer IS NULL OR c.version = $2)
+//      UNION ALL
+//      SELECT child.id FROM category child
+//      JOIN subtree ON child.parent_id = subtree.id
+//      WHERE child.deleted_at IS NULL
+//  )
+//  UPDATE category
+//  SET deleted_at = now(), version = version + 1
+//  WHERE id IN (SELECT id FROM subtree)
+//  RETURNING id, parent_id
+func (q *Queries) DeleteCategory(ctx context.Context, arg DeleteCategoryParams) ([]DeleteCategoryRow, error) {
+	rows, err := q.db.Query(ctx, deleteCategory, arg.ID, arg.Version)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return result.RowsAffected(), nil
+	defer rows.Close()
+	var items []DeleteCategoryRow
+	for rows.Next() {
+		var i DeleteCategoryRow
+		if err := rows.Scan(&i.ID, &i.ParentID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getAllCategories = `-- name: GetAllCategories :many
-SELECT id, name, parent_id, description, created_at, updated_at FROM category
+SELECT id, name, parent_id, description, version, created_at, updated_at FROM category
+WHERE deleted_at IS NULL
 `
 
+type GetAllCategoriesRow struct {
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Name        string           `db:"name" json:"name"`
+	ParentID    pgtype.UUID      `db:"parent_id" json:"parentId"`
+	Description *string          `db:"description" json:"description"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
 // Get all categories
 //
-//  SELECT id, name, parent_id, description, created_at, updated_at FROM category
-func (q *Queries) GetAllCategories(ctx context.Context) ([]Category, error) {
+//  SELECT id, name, parent_id, description, version, created_at, updated_at FROM category
+//  WHERE deleted_at IS NULL
+func (q *Queries) GetAllCategories(ctx context.Context) ([]GetAllCategoriesRow, error) {
 	rows, err := q.db.Query(ctx, getAllCategories)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Category
+	var items []GetAllCategoriesRow
 	for rows.Next() {
-		var i Category
+		var i GetAllCategoriesRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
 			&i.ParentID,
 			&i.Description,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCategoriesByIDs = `-- name: GetCategoriesByIDs :many
+SELECT id, name, parent_id, description, version, created_at, updated_at FROM category
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+`
+
+type GetCategoriesByIDsRow struct {
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Name        string           `db:"name" json:"name"`
+	ParentID    pgtype.UUID      `db:"parent_id" json:"parentId"`
+	Description *string          `db:"description" json:"description"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Get the categories with the given IDs
+//
+//  SELECT id, name, parent_id, description, version, created_at, updated_at FROM category
+//  WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+func (q *Queries) GetCategoriesByIDs(ctx context.Context, ids []pgtype.UUID) ([]GetCategoriesByIDsRow, error) {
+	rows, err := q.db.Query(ctx, getCategoriesByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCategoriesByIDsRow
+	for rows.Next() {
+		var i GetCategoriesByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ParentID,
+			&i.Description,
+			&i.Version,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -83,8 +188,8 @@ func (q *Queries) GetAllCategories(ctx context.Context) ([]Category, error) {
 }
 
 const getCategoryByID = `-- name: GetCategoryByID :one
-SELECT id, name, parent_id, description FROM category 
-WHERE id = $1
+SELECT id, name, parent_id, description, version FROM category 
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 type GetCategoryByIDRow struct {
@@ -92,12 +197,13 @@ type GetCategoryByIDRow struct {
 	Name        string      `db:"name" json:"name"`
 	ParentID    pgtype.UUID `db:"parent_id" json:"parentId"`
 	Description *string     `db:"description" json:"description"`
+	Version     int32       `db:"version" json:"version"`
 }
 
 // Get category by ID
 //
-//  SELECT id, name, parent_id, description FROM category
-//  WHERE id = $1
+//  SELECT id, name, parent_id, description, version FROM category
+//  WHERE id = $1 AND deleted_at IS NULL
 func (q *Queries) GetCategoryByID(ctx context.Context, id pgtype.UUID) (GetCategoryByIDRow, error) {
 	row := q.db.QueryRow(ctx, getCategoryByID, id)
 	var i GetCategoryByIDRow
@@ -106,13 +212,14 @@ func (q *Queries) GetCategoryByID(ctx context.Context, id pgtype.UUID) (GetCateg
 		&i.Name,
 		&i.ParentID,
 		&i.Description,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getCategoryByName = `-- name: GetCategoryByName :one
 SELECT id, name, parent_id, description FROM category 
-WHERE name = $1
+WHERE name = $1 AND deleted_at IS NULL
 `
 
 type GetCategoryByNameRow struct {
@@ -125,7 +232,7 @@ type GetCategoryByNameRow struct {
 // Get category by name
 //
 //  SELECT id, name, parent_id, description FROM category
-//  WHERE name = $1
+//  WHERE name = $1 AND deleted_at IS NULL
 func (q *Queries) GetCategoryByName(ctx context.Context, name string) (GetCategoryByNameRow, error) {
 	row := q.db.QueryRow(ctx, getCategoryByName, name)
 	var i GetCategoryByNameRow
@@ -141,7 +248,7 @@ func (q *Queries) GetCategoryByName(ctx context.Context, name string) (GetCatego
 const getSubcategories = `-- name: GetSubcategories :many
 SELECT id, name, description, created_at, updated_at 
 FROM category 
-WHERE parent_id = $1
+WHERE parent_id = $1 AND deleted_at IS NULL
 `
 
 type GetSubcategoriesRow struct {
@@ -156,7 +263,7 @@ type GetSubcategoriesRow struct {
 //
 //  SELECT id, name, description, created_at, updated_at
 //  FROM category
-//  WHERE parent_id = $1
+//  WHERE parent_id = $1 AND deleted_at IS NULL
 func (q *Queries) GetSubcategories(ctx context.Context, parentID pgtype.UUID) ([]GetSubcategoriesRow, error) {
 	rows, err := q.db.Query(ctx, getSubcategories, parentID)
 	if err != nil {
@@ -183,33 +290,137 @@ func (q *Queries) GetSubcategories(ctx context.Context, parentID pgtype.UUID) ([
 	return items, nil
 }
 
-const updateCategory = `-- name: UpdateCategory :execrows
-UPDATE category 
-SET name = $1, parent_id = $2, description = $3, updated_at = now() 
-WHERE id = $4
+const getSubcategoriesOfCategories = `-- name: GetSubcategoriesOfCategories :many
+SELECT id, name, parent_id, description, version, created_at, updated_at
+FROM category
+WHERE parent_id = ANY($1::uuid[]) AND deleted_at IS NULL
+`
+
+type GetSubcategoriesOfCategoriesRow struct {
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Name        string           `db:"name" json:"name"`
+	ParentID    pgtype.UUID      `db:"parent_id" json:"parentId"`
+	Description *string          `db:"description" json:"description"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Get the subcategories of several categories at once
+//
+//  SELECT id, name, parent_id, description, version, created_at, updated_at
+//  FROM category
+//  WHERE parent_id = ANY($1::uuid[]) AND deleted_at IS NULL
+func (q *Queries) GetSubcategoriesOfCategories(ctx context.Context, parentIds []pgtype.UUID) ([]GetSubcategoriesOfCategoriesRow, error) {
+	rows, err := q.db.Query(ctx, getSubcategoriesOfCategories, parentIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSubcategoriesOfCategoriesRow
+	for rows.Next() {
+		var i GetSubcategoriesOfCategoriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ParentID,
+			&i.Description,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLiveCategories = `-- name: LockLiveCategories :many
+SELECT id FROM category
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+FOR SHARE
+`
+
+// Lock the categories with the given IDs that are not in the trash, so they aren't trashed
+// before the transaction commits
+//
+//  SELECT id FROM category
+//  WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+//  FOR SHARE
+func (q *Queries) LockLiveCategories(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, lockLiveCategories, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLiveCategory = `-- name: LockLiveCategory :one
+SELECT id FROM category
+WHERE id = $1 AND deleted_at IS NULL
+FOR SHARE
+`
+
+// Lock a category that is not in the trash, so it isn't trashed before the transaction commits
+//
+//  SELECT id FROM category
+//  WHERE id = $1 AND deleted_at IS NULL
+//  FOR SHARE
+func (q *Queries) LockLiveCategory(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, lockLiveCategory, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updateCategory = `-- name: UpdateCategory :one
+UPDATE category c
+SET name = $1, parent_id = $2, description = $3, version = c.version + 1, updated_at = now() 
+FROM (SELECT p.id, p.parent_id FROM category p WHERE p.id = $5 FOR UPDATE) old
+WHERE c.id = old.id AND c.deleted_at IS NULL AND ($4::integer IS NULL OR c.version = $4)
+RETURNING old.parent_id
 `
 
 type UpdateCategoryParams struct {
 	Name        string      `db:"name" json:"name"`
 	ParentID    pgtype.UUID `db:"parent_id" json:"parentId"`
 	Description *string     `db:"description" json:"description"`
+	Version     *int32      `db:"version" json:"version"`
 	ID          pgtype.UUID `db:"id" json:"id"`
 }
 
-// Update category details
+// Update category details, optionally only if it is still at the given version.
+// Returns the parent the category had before, which a move takes it away from.
 //
-//  UPDATE category
-//  SET name = $1, parent_id = $2, description = $3, updated_at = now()
-//  WHERE id = $4
-func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (int64, error) {
-	result, err := q.db.Exec(ctx, updateCategory,
+//  UPDATE category c
+//  SET name = $1, parent_id = $2, description = $3, version = c.version + 1, updated_at = now()
+//  FROM (SELECT p.id, p.parent_id FROM category p WHERE p.id = $5 FOR UPDATE) old
+//  WHERE c.id = old.id AND c.deleted_at IS NULL AND ($4::integer IS NULL OR c.version = $4)
+//  RETURNING old.parent_id
+func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, updateCategory,
 		arg.Name,
 		arg.ParentID,
 		arg.Description,
+		arg.Version,
 		arg.ID,
 	)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+	var parent_id pgtype.UUID
+	err := row.Scan(&parent_id)
+	return parent_id, err
 }
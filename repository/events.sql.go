@@ -0,0 +1,170 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: events.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getEventByID = `-- name: GetEventByID :one
+SELECT id, type, source, category_ids, data FROM events
+WHERE id = $1
+`
+
+type GetEventByIDRow struct {
+	ID          int64         `db:"id" json:"id"`
+	Type        string        `db:"type" json:"type"`
+	Source      string        `db:"source" json:"source"`
+	CategoryIds []pgtype.UUID `db:"category_ids" json:"categoryIds"`
+	Data        []byte        `db:"data" json:"data"`
+}
+
+// Get an event by its ID
+//
+//  SELECT id, type, source, category_ids, data FROM events
+//  WHERE id = $1
+func (q *Queries) GetEventByID(ctx context.Context, id int64) (GetEventByIDRow, error) {
+	row := q.db.QueryRow(ctx, getEventByID, id)
+	var i GetEventByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Source,
+		&i.CategoryIds,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getLatestEventID = `-- name: GetLatestEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint FROM events
+`
+
+// Get the ID of the latest event
+//
+//  SELECT COALESCE(MAX(id), 0)::bigint FROM events
+func (q *Queries) GetLatestEventID(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getLatestEventID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const insertEvent = `-- name: InsertEvent :one
+INSERT INTO events (type, source, category_ids, data)
+VALUES ($1, $2, $3::uuid[], $4)
+RETURNING id
+`
+
+type InsertEventParams struct {
+	Type        string        `db:"type" json:"type"`
+	Source      string        `db:"source" json:"source"`
+	CategoryIds []pgtype.UUID `db:"category_ids" json:"categoryIds"`
+	Data        []byte        `db:"data" json:"data"`
+}
+
+// Append an event to the outbox
+//
+//  INSERT INTO events (type, source, category_ids, data)
+//  VALUES ($1, $2, $3::uuid[], $4)
+//  RETURNING id
+func (q *Queries) InsertEvent(ctx context.Context, arg InsertEventParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertEvent,
+		arg.Type,
+		arg.Source,
+		arg.CategoryIds,
+		arg.Data,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listEventsAfter = `-- name: ListEventsAfter :many
+SELECT id, type, source, category_ids, data FROM events
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListEventsAfterParams struct {
+	AfterID   int64 `db:"after_id" json:"afterId"`
+	MaxEvents int32 `db:"max_events" json:"maxEvents"`
+}
+
+type ListEventsAfterRow struct {
+	ID          int64         `db:"id" json:"id"`
+	Type        string        `db:"type" json:"type"`
+	Source      string        `db:"source" json:"source"`
+	CategoryIds []pgtype.UUID `db:"category_ids" json:"categoryIds"`
+	Data        []byte        `db:"data" json:"data"`
+}
+
+// Get the events published after the given ID, oldest first
+//
+//  SELECT id, type, source, category_ids, data FROM events
+//  WHERE id > $1
+//  ORDER BY id
+//  LIMIT $2
+func (q *Queries) ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]ListEventsAfterRow, error) {
+	rows, err := q.db.Query(ctx, listEventsAfter, arg.AfterID, arg.MaxEvents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventsAfterRow
+	for rows.Next() {
+		var i ListEventsAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Source,
+			&i.CategoryIds,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockEvents = `-- name: LockEvents :exec
+SELECT pg_advisory_xact_lock(hashtext('refero_events'))
+`
+
+// Serialize the transactions publishing events until they commit, so that event IDs are
+// assigned in commit order and clients resuming after an ID can't miss an event.
+// The lock is global: every mutation publishes an event, so writes commit one at a time,
+// and write throughput is bounded by the latency of a single transaction.
+//
+//  SELECT pg_advisory_xact_lock(hashtext('refero_events'))
+func (q *Queries) LockEvents(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockEvents)
+	return err
+}
+
+const purgeEvents = `-- name: PurgeEvents :execrows
+DELETE FROM events
+WHERE created_at < now() - $1::bigint * interval '1 second'
+`
+
+// Delete events older than the retention period
+//
+//  DELETE FROM events
+//  WHERE created_at < now() - $1::bigint * interval '1 second'
+func (q *Queries) PurgeEvents(ctx context.Context, retentionSeconds int64) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeEvents, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -20,7 +20,7 @@ const getCategoriesForLink = `-- name: GetCategoriesForLink :many
 SELECT c.id, c.name, c.description 
 FROM category c 
 JOIN link_category_map lcm ON c.id = lcm.category_id 
-WHERE lcm.link_id = $1
+WHERE lcm.link_id = $1 AND c.deleted_at IS NULL
 `
 
 type GetCategoriesForLinkRow struct {
@@ -34,7 +34,7 @@ type GetCategoriesForLinkRow struct {
 //  SELECT c.id, c.name, c.description
 //  FROM category c
 //  JOIN link_category_map lcm ON c.id = lcm.category_id
-//  WHERE lcm.link_id = $1
+//  WHERE lcm.link_id = $1 AND c.deleted_at IS NULL
 func (q *Queries) GetCategoriesForLink(ctx context.Context, linkID pgtype.UUID) ([]GetCategoriesForLinkRow, error) {
 	rows, err := q.db.Query(ctx, getCategoriesForLink, linkID)
 	if err != nil {
@@ -55,28 +55,146 @@ func (q *Queries) GetCategoriesForLink(ctx context.Context, linkID pgtype.UUID)
 	return items, nil
 }
 
+const getCategoriesForLinks = `-- name: GetCategoriesForLinks :many
+SELECT lcm.link_id, c.id, c.name, c.parent_id, c.description, c.version, c.created_at, c.updated_at
+FROM category c
+JOIN link_category_map lcm ON c.id = lcm.category_id
+WHERE lcm.link_id = ANY($1::uuid[]) AND c.deleted_at IS NULL
+`
+
+type GetCategoriesForLinksRow struct {
+	LinkID      pgtype.UUID      `db:"link_id" json:"linkId"`
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Name        string           `db:"name" json:"name"`
+	ParentID    pgtype.UUID      `db:"parent_id" json:"parentId"`
+	Description *string          `db:"description" json:"description"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Get the categories of several links at once
+//
+//  SELECT lcm.link_id, c.id, c.name, c.parent_id, c.description, c.version, c.created_at, c.updated_at
+//  FROM category c
+//  JOIN link_category_map lcm ON c.id = lcm.category_id
+//  WHERE lcm.link_id = ANY($1::uuid[]) AND c.deleted_at IS NULL
+func (q *Queries) GetCategoriesForLinks(ctx context.Context, linkIds []pgtype.UUID) ([]GetCategoriesForLinksRow, error) {
+	rows, err := q.db.Query(ctx, getCategoriesForLinks, linkIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCategoriesForLinksRow
+	for rows.Next() {
+		var i GetCategoriesForLinksRow
+		if err := rows.Scan(
+			&i.LinkID,
+			&i.ID,
+			&i.Name,
+			&i.ParentID,
+			&i.Description,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLinksForCategories = `-- name: GetLinksForCategories :many
+SELECT lcm.category_id, l.id, l.url, l.title, l.description, l.short_url, l.version, l.created_at, l.updated_at
+FROM links l
+JOIN link_category_map lcm ON l.id = lcm.link_id
+WHERE lcm.category_id = ANY($1::uuid[]) AND l.deleted_at IS NULL
+`
+
+type GetLinksForCategoriesRow struct {
+	CategoryID  pgtype.UUID      `db:"category_id" json:"categoryId"`
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Url         string           `db:"url" json:"url"`
+	Title       string           `db:"title" json:"title"`
+	Description string           `db:"description" json:"description"`
+	ShortUrl    string           `db:"short_url" json:"shortUrl"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Get the links in several categories at once
+//
+//  SELECT lcm.category_id, l.id, l.url, l.title, l.description, l.short_url, l.version, l.created_at, l.updated_at
+//  FROM links l
+//  JOIN link_category_map lcm ON l.id = lcm.link_id
+//  WHERE lcm.category_id = ANY($1::uuid[]) AND l.deleted_at IS NULL
+func (q *Queries) GetLinksForCategories(ctx context.Context, categoryIds []pgtype.UUID) ([]GetLinksForCategoriesRow, error) {
+	rows, err := q.db.Query(ctx, getLinksForCategories, categoryIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLinksForCategoriesRow
+	for rows.Next() {
+		var i GetLinksForCategoriesRow
+		if err := rows.Scan(
+			&i.CategoryID,
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.ShortUrl,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLinksForCategory = `-- name: GetLinksForCategory :many
 SELECT l.id, l.url, l.title, l.description, l.short_url, l.created_at, l.updated_at 
 FROM links l 
 JOIN link_category_map lcm ON l.id = lcm.link_id 
-WHERE lcm.category_id = $1
+WHERE lcm.category_id = $1 AND l.deleted_at IS NULL
 `
 
+type GetLinksForCategoryRow struct {
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Url         string           `db:"url" json:"url"`
+	Title       string           `db:"title" json:"title"`
+	Description string           `db:"description" json:"description"`
+	ShortUrl    string           `db:"short_url" json:"shortUrl"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
 // Get all links in a category
 //
 //  SELECT l.id, l.url, l.title, l.description, l.short_url, l.created_at, l.updated_at
 //  FROM links l
 //  JOIN link_category_map lcm ON l.id = lcm.link_id
-//  WHERE lcm.category_id = $1
-func (q *Queries) GetLinksForCategory(ctx context.Context, categoryID pgtype.UUID) ([]Link, error) {
+//  WHERE lcm.category_id = $1 AND l.deleted_at IS NULL
+func (q *Queries) GetLinksForCategory(ctx context.Context, categoryID pgtype.UUID) ([]GetLinksForCategoryRow, error) {
 	rows, err := q.db.Query(ctx, getLinksForCategory, categoryID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Link
+	var items []GetLinksForCategoryRow
 	for rows.Next() {
-		var i Link
+		var i GetLinksForCategoryRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Url,
@@ -97,23 +215,25 @@ func (q *Queries) GetLinksForCategory(ctx context.Context, categoryID pgtype.UUI
 }
 
 const getUncategorizedLinks = `-- name: GetUncategorizedLinks :many
-SELECT id, url, title, description, short_url, created_at, updated_at 
+SELECT id, url, title, description, short_url, created_at, updated_at, version, deleted_at 
 FROM links l
-WHERE NOT EXISTS (
+WHERE l.deleted_at IS NULL AND NOT EXISTS (
     SELECT 1 
     FROM link_category_map lcm 
-    WHERE l.id = lcm.link_id
+    JOIN category c ON c.id = lcm.category_id 
+    WHERE l.id = lcm.link_id AND c.deleted_at IS NULL
 )
 `
 
 // Get all uncategorized links
 //
-//  SELECT id, url, title, description, short_url, created_at, updated_at
+//  SELECT id, url, title, description, short_url, created_at, updated_at, version, deleted_at
 //  FROM links l
-//  WHERE NOT EXISTS (
+//  WHERE l.deleted_at IS NULL AND NOT EXISTS (
 //      SELECT 1
 //      FROM link_category_map lcm
-//      WHERE l.id = lcm.link_id
+//      JOIN category c ON c.id = lcm.category_id
+//      WHERE l.id = lcm.link_id AND c.deleted_at IS NULL
 //  )
 func (q *Queries) GetUncategorizedLinks(ctx context.Context) ([]Link, error) {
 	rows, err := q.db.Query(ctx, getUncategorizedLinks)
@@ -132,6 +252,8 @@ func (q *Queries) GetUncategorizedLinks(ctx context.Context) ([]Link, error) {
 			&i.ShortUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -12,9 +12,9 @@ import (
 )
 
 const checkIfLinkExistsByURL = `-- name: CheckIfLinkExistsByURL :one
-SELECT id, true AS exists FROM links l WHERE l.url = $1
+SELECT id, true AS exists FROM links l WHERE l.url = $1 AND l.deleted_at IS NULL
 UNION ALL
-SELECT NULL, false AS exists WHERE NOT EXISTS (SELECT 1 FROM links WHERE links.url = $1)
+SELECT NULL, false AS exists WHERE NOT EXISTS (SELECT 1 FROM links WHERE links.url = $1 AND links.deleted_at IS NULL)
 LIMIT 1
 `
 
@@ -25,9 +25,9 @@ type CheckIfLinkExistsByURLRow struct {
 
 // Check if link exists by URL
 //
-//  SELECT id, true AS exists FROM links l WHERE l.url = $1
+//  SELECT id, true AS exists FROM links l WHERE l.url = $1 AND l.deleted_at IS NULL
 //  UNION ALL
-//  SELECT NULL, false AS exists WHERE NOT EXISTS (SELECT 1 FROM links WHERE links.url = $1)
+//  SELECT NULL, false AS exists WHERE NOT EXISTS (SELECT 1 FROM links WHERE links.url = $1 AND links.deleted_at IS NULL)
 //  LIMIT 1
 func (q *Queries) CheckIfLinkExistsByURL(ctx context.Context, url string) (CheckIfLinkExistsByURLRow, error) {
 	row := q.db.QueryRow(ctx, checkIfLinkExistsByURL, url)
@@ -64,43 +64,67 @@ func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) (pgtype.
 	return id, err
 }
 
-const deleteLink = `-- name: DeleteLink :execrows
-DELETE FROM links WHERE id = $1
+const deleteLink = `-- name: DeleteLink :one
+UPDATE links 
+SET deleted_at = now(), version = version + 1 
+WHERE id = $1 AND deleted_at IS NULL AND ($2::integer IS NULL OR version = $2)
+RETURNING short_url
 `
 
-// Delete link
+type DeleteLinkParams struct {
+	ID      pgtype.UUID `db:"id" json:"id"`
+	Version *int32      `db:"version" json:"version"`
+}
+
+// Move link to the trash, optionally only if it is still at the given version
 //
-//  DELETE FROM links WHERE id = $1
-func (q *Queries) DeleteLink(ctx context.Context, id pgtype.UUID) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteLink, id)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+//  UPDATE links
+//  SET deleted_at = now(), version = version + 1
+//  WHERE id = $1 AND deleted_at IS NULL AND ($2::integer IS NULL OR version = $2)
+//  RETURNING short_url
+func (q *Queries) DeleteLink(ctx context.Context, arg DeleteLinkParams) (string, error) {
+	row := q.db.QueryRow(ctx, deleteLink, arg.ID, arg.Version)
+	var short_url string
+	err := row.Scan(&short_url)
+	return short_url, err
 }
 
 const getAllLinks = `-- name: GetAllLinks :many
-SELECT id, url, title, description, short_url, created_at, updated_at FROM links
+SELECT id, url, title, description, short_url, version, created_at, updated_at FROM links
+WHERE deleted_at IS NULL
 `
 
+type GetAllLinksRow struct {
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Url         string           `db:"url" json:"url"`
+	Title       string           `db:"title" json:"title"`
+	Description string           `db:"description" json:"description"`
+	ShortUrl    string           `db:"short_url" json:"shortUrl"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
 // Get all public links
 //
-//  SELECT id, url, title, description, short_url, created_at, updated_at FROM links
-func (q *Queries) GetAllLinks(ctx context.Context) ([]Link, error) {
+//  SELECT id, url, title, description, short_url, version, created_at, updated_at FROM links
+//  WHERE deleted_at IS NULL
+func (q *Queries) GetAllLinks(ctx context.Context) ([]GetAllLinksRow, error) {
 	rows, err := q.db.Query(ctx, getAllLinks)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Link
+	var items []GetAllLinksRow
 	for rows.Next() {
-		var i Link
+		var i GetAllLinksRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Url,
 			&i.Title,
 			&i.Description,
 			&i.ShortUrl,
+			&i.Version,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -115,25 +139,37 @@ func (q *Queries) GetAllLinks(ctx context.Context) ([]Link, error) {
 }
 
 const getLinkByID = `-- name: GetLinkByID :one
-SELECT id, url, title, description, short_url, created_at, updated_at 
+SELECT id, url, title, description, short_url, version, created_at, updated_at 
 FROM links 
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
+type GetLinkByIDRow struct {
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Url         string           `db:"url" json:"url"`
+	Title       string           `db:"title" json:"title"`
+	Description string           `db:"description" json:"description"`
+	ShortUrl    string           `db:"short_url" json:"shortUrl"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
 // Get link by ID
 //
-//  SELECT id, url, title, description, short_url, created_at, updated_at
+//  SELECT id, url, title, description, short_url, version, created_at, updated_at
 //  FROM links
-//  WHERE id = $1
-func (q *Queries) GetLinkByID(ctx context.Context, id pgtype.UUID) (Link, error) {
+//  WHERE id = $1 AND deleted_at IS NULL
+func (q *Queries) GetLinkByID(ctx context.Context, id pgtype.UUID) (GetLinkByIDRow, error) {
 	row := q.db.QueryRow(ctx, getLinkByID, id)
-	var i Link
+	var i GetLinkByIDRow
 	err := row.Scan(
 		&i.ID,
 		&i.Url,
 		&i.Title,
 		&i.Description,
 		&i.ShortUrl,
+		&i.Version,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -142,7 +178,7 @@ func (q *Queries) GetLinkByID(ctx context.Context, id pgtype.UUID) (Link, error)
 
 const getLinkByShortURL = `-- name: GetLinkByShortURL :one
 SELECT id, url, title, description, short_url FROM links 
-WHERE short_url = $1
+WHERE short_url = $1 AND deleted_at IS NULL
 `
 
 type GetLinkByShortURLRow struct {
@@ -156,7 +192,7 @@ type GetLinkByShortURLRow struct {
 // Get link by short URL
 //
 //  SELECT id, url, title, description, short_url FROM links
-//  WHERE short_url = $1
+//  WHERE short_url = $1 AND deleted_at IS NULL
 func (q *Queries) GetLinkByShortURL(ctx context.Context, shortUrl string) (GetLinkByShortURLRow, error) {
 	row := q.db.QueryRow(ctx, getLinkByShortURL, shortUrl)
 	var i GetLinkByShortURLRow
@@ -171,7 +207,7 @@ func (q *Queries) GetLinkByShortURL(ctx context.Context, shortUrl string) (GetLi
 }
 
 const getLinkByURL = `-- name: GetLinkByURL :one
-SELECT id, url, title, description, short_url FROM links WHERE url = $1
+SELECT id, url, title, description, short_url FROM links WHERE url = $1 AND deleted_at IS NULL
 `
 
 type GetLinkByURLRow struct {
@@ -184,7 +220,7 @@ type GetLinkByURLRow struct {
 
 // Get link by URL
 //
-//  SELECT id, url, title, description, short_url FROM links WHERE url = $1
+//  SELECT id, url, title, description, short_url FROM links WHERE url = $1 AND deleted_at IS NULL
 func (q *Queries) GetLinkByURL(ctx context.Context, url string) (GetLinkByURLRow, error) {
 	row := q.db.QueryRow(ctx, getLinkByURL, url)
 	var i GetLinkByURLRow
@@ -198,39 +234,44 @@ func (q *Queries) GetLinkByURL(ctx context.Context, url string) (GetLinkByURLRow
 	return i, err
 }
 
-const getLinksPaginated = `-- name: GetLinksPaginated :many
-SELECT id, url, title, description, short_url, created_at, updated_at 
-FROM links 
-ORDER BY created_at DESC 
-LIMIT $1 OFFSET $2
+const getLinksByIDs = `-- name: GetLinksByIDs :many
+SELECT id, url, title, description, short_url, version, created_at, updated_at
+FROM links
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
 `
 
-type GetLinksPaginatedParams struct {
-	Limit  int32 `db:"limit" json:"limit"`
-	Offset int32 `db:"offset" json:"offset"`
+type GetLinksByIDsRow struct {
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Url         string           `db:"url" json:"url"`
+	Title       string           `db:"title" json:"title"`
+	Description string           `db:"description" json:"description"`
+	ShortUrl    string           `db:"short_url" json:"shortUrl"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
 }
 
-// Get paginated links
+// Get the links with the given IDs
 //
-//  SELECT id, url, title, description, short_url, created_at, updated_at
+//  SELECT id, url, title, description, short_url, version, created_at, updated_at
 //  FROM links
-//  ORDER BY created_at DESC
-//  LIMIT $1 OFFSET $2
-func (q *Queries) GetLinksPaginated(ctx context.Context, arg GetLinksPaginatedParams) ([]Link, error) {
-	rows, err := q.db.Query(ctx, getLinksPaginated, arg.Limit, arg.Offset)
+//  WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+func (q *Queries) GetLinksByIDs(ctx context.Context, ids []pgtype.UUID) ([]GetLinksByIDsRow, error) {
+	rows, err := q.db.Query(ctx, getLinksByIDs, ids)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Link
+	var items []GetLinksByIDsRow
 	for rows.Next() {
-		var i Link
+		var i GetLinksByIDsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Url,
 			&i.Title,
 			&i.Description,
 			&i.ShortUrl,
+			&i.Version,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -244,27 +285,120 @@ func (q *Queries) GetLinksPaginated(ctx context.Context, arg GetLinksPaginatedPa
 	return items, nil
 }
 
-const updateLink = `-- name: UpdateLink :execrows
+const searchLinks = `-- name: SearchLinks :many
+SELECT id, url, title, description, short_url, version, created_at, updated_at
+FROM links
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR title ILIKE $1 OR url ILIKE $1 OR description ILIKE $1)
+ORDER BY created_at DESC, id
+LIMIT $3::integer OFFSET $2::integer
+`
+
+type SearchLinksParams struct {
+	Pattern  *string `db:"pattern" json:"pattern"`
+	Skip     int32   `db:"skip" json:"skip"`
+	MaxLinks *int32  `db:"max_links" json:"maxLinks"`
+}
+
+type SearchLinksRow struct {
+	ID          pgtype.UUID      `db:"id" json:"id"`
+	Url         string           `db:"url" json:"url"`
+	Title       string           `db:"title" json:"title"`
+	Description string           `db:"description" json:"description"`
+	ShortUrl    string           `db:"short_url" json:"shortUrl"`
+	Version     int32            `db:"version" json:"version"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Get a page of links matching the pattern in their title, URL or description, newest first.
+// All matching links are returned when no limit is given.
+//
+//  SELECT id, url, title, description, short_url, version, created_at, updated_at
+//  FROM links
+//  WHERE deleted_at IS NULL
+//    AND ($1::text IS NULL OR title ILIKE $1 OR url ILIKE $1 OR description ILIKE $1)
+//  ORDER BY created_at DESC, id
+//  LIMIT $3::integer OFFSET $2::integer
+func (q *Queries) SearchLinks(ctx context.Context, arg SearchLinksParams) ([]SearchLinksRow, error) {
+	rows, err := q.db.Query(ctx, searchLinks, arg.Pattern, arg.Skip, arg.MaxLinks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchLinksRow
+	for rows.Next() {
+		var i SearchLinksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.ShortUrl,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchLink = `-- name: TouchLink :one
 UPDATE links 
-SET title = $1, description = $2, updated_at = now() 
-WHERE id = $3
+SET version = version + 1, updated_at = now() 
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING short_url
+`
+
+// Bump the version of a link whose categories changed
+//
+//  UPDATE links
+//  SET version = version + 1, updated_at = now()
+//  WHERE id = $1 AND deleted_at IS NULL
+//  RETURNING short_url
+func (q *Queries) TouchLink(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, touchLink, id)
+	var short_url string
+	err := row.Scan(&short_url)
+	return short_url, err
+}
+
+const updateLink = `-- name: UpdateLink :one
+UPDATE links 
+SET title = $1, url = $2, description = $3, version = version + 1, updated_at = now() 
+WHERE id = $4 AND deleted_at IS NULL AND ($5::integer IS NULL OR version = $5)
+RETURNING short_url
 `
 
 type UpdateLinkParams struct {
 	Title       string      `db:"title" json:"title"`
+	Url         string      `db:"url" json:"url"`
 	Description string      `db:"description" json:"description"`
 	ID          pgtype.UUID `db:"id" json:"id"`
+	Version     *int32      `db:"version" json:"version"`
 }
 
-// Update link details
+// Update link details, optionally only if it is still at the given version
 //
 //  UPDATE links
-//  SET title = $1, description = $2, updated_at = now()
-//  WHERE id = $3
-func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) (int64, error) {
-	result, err := q.db.Exec(ctx, updateLink, arg.Title, arg.Description, arg.ID)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+//  SET title = $1, url = $2, description = $3, version = version + 1, updated_at = now()
+//  WHERE id = $4 AND deleted_at IS NULL AND ($5::integer IS NULL OR version = $5)
+//  RETURNING short_url
+func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) (string, error) {
+	row := q.db.QueryRow(ctx, updateLink,
+		arg.Title,
+		arg.Url,
+		arg.Description,
+		arg.ID,
+		arg.Version,
+	)
+	var short_url string
+	err := row.Scan(&short_url)
+	return short_url, err
 }
@@ -15,6 +15,8 @@ type Category struct {
 	Description *string          `db:"description" json:"description"`
 	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
 	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+	Version     int32            `db:"version" json:"version"`
+	DeletedAt   pgtype.Timestamp `db:"deleted_at" json:"deletedAt"`
 }
 
 type Link struct {
@@ -25,4 +27,6 @@ type Link struct {
 	ShortUrl    string           `db:"short_url" json:"shortUrl"`
 	CreatedAt   pgtype.Timestamp `db:"created_at" json:"createdAt"`
 	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+	Version     int32            `db:"version" json:"version"`
+	DeletedAt   pgtype.Timestamp `db:"deleted_at" json:"deletedAt"`
 }
@@ -0,0 +1,27 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: notify.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const notify = `-- name: Notify :exec
+SELECT pg_notify($1::text, $2::text)
+`
+
+type NotifyParams struct {
+	Channel string `db:"channel" json:"channel"`
+	Payload string `db:"payload" json:"payload"`
+}
+
+// Send a notification to the listeners of a channel, delivered once the transaction commits
+//
+//  SELECT pg_notify($1::text, $2::text)
+func (q *Queries) Notify(ctx context.Context, arg NotifyParams) error {
+	_, err := q.db.Exec(ctx, notify, arg.Channel, arg.Payload)
+	return err
+}
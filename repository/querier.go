@@ -17,11 +17,27 @@ type Querier interface {
 	AddLinkToCategory(ctx context.Context, arg []AddLinkToCategoryParams) (int64, error)
 	// Check if link exists by URL
 	//
-	//  SELECT id, true AS exists FROM links l WHERE l.url = $1
+	//  SELECT id, true AS exists FROM links l WHERE l.url = $1 AND l.deleted_at IS NULL
 	//  UNION ALL
-	//  SELECT NULL, false AS exists WHERE NOT EXISTS (SELECT 1 FROM links WHERE links.url = $1)
+	//  SELECT NULL, false AS exists WHERE NOT EXISTS (SELECT 1 FROM links WHERE links.url = $1 AND links.deleted_at IS NULL)
 	//  LIMIT 1
 	CheckIfLinkExistsByURL(ctx context.Context, url string) (CheckIfLinkExistsByURLRow, error)
+	// Lease the deliveries that are due, so that no other instance attempts them until the lease expires
+	//
+	//  WITH due AS (
+	//      SELECT d.id FROM webhook_deliveries d
+	//      JOIN webhooks w ON w.id = d.webhook_id
+	//      WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND w.enabled
+	//      ORDER BY d.next_attempt_at
+	//      LIMIT $2
+	//      FOR UPDATE OF d SKIP LOCKED
+	//  )
+	//  UPDATE webhook_deliveries d
+	//  SET next_attempt_at = now() + $1::bigint * interval '1 second'
+	//  FROM due, webhooks w
+	//  WHERE d.id = due.id AND w.id = d.webhook_id
+	//  RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.attempts, d.created_at, w.url, w.secret
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
 	// Create a new category
 	//
 	//  INSERT INTO category (name, parent_id, description)
@@ -32,101 +48,345 @@ type Querier interface {
 	//  INSERT INTO links (url, title, description, short_url)
 	//  VALUES ($1, $2, $3, $4) RETURNING id
 	CreateLink(ctx context.Context, arg CreateLinkParams) (pgtype.UUID, error)
-	// Delete category
+	// Register a webhook
+	//
+	//  INSERT INTO webhooks (url, description, secret, event_types)
+	//  VALUES ($1, $2, $3, $4::text[])
+	//  RETURNING id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (CreateWebhookRow, error)
+	// Move a category and its subcategories to the trash, optionally only if it is still at the given version.
+	// Returns the trashed categories.
+	//
+	//  WITH RECURSIVE subtree AS (
+	//      SELECT c.id FROM category c
+	//      WHERE c.id = $1 AND c.deleted_at IS NULL AND ($2::integer IS NULL OR c.version = $2)
+	//      UNION ALL
+	//      SELECT child.id FROM category child
+	//      JOIN subtree ON child.parent_id = subtree.id
+	//      WHERE child.deleted_at IS NULL
+	//  )
+	//  UPDATE category
+	//  SET deleted_at = now(), version = version + 1
+	//  WHERE id IN (SELECT id FROM subtree)
+	//  RETURNING id, parent_id
+	DeleteCategory(ctx context.Context, arg DeleteCategoryParams) ([]DeleteCategoryRow, error)
+	// Move link to the trash, optionally only if it is still at the given version
+	//
+	//  UPDATE links
+	//  SET deleted_at = now(), version = version + 1
+	//  WHERE id = $1 AND deleted_at IS NULL AND ($2::integer IS NULL OR version = $2)
+	//  RETURNING short_url
+	DeleteLink(ctx context.Context, arg DeleteLinkParams) (string, error)
+	// Delete a webhook along with its deliveries
 	//
-	//  DELETE FROM category WHERE id = $1
-	DeleteCategory(ctx context.Context, id pgtype.UUID) (int64, error)
-	// Delete link
+	//  DELETE FROM webhooks WHERE id = $1
+	DeleteWebhook(ctx context.Context, id pgtype.UUID) (int64, error)
+	// Queue a delivery of the event for every enabled webhook subscribed to its type
 	//
-	//  DELETE FROM links WHERE id = $1
-	DeleteLink(ctx context.Context, id pgtype.UUID) (int64, error)
+	//  INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+	//  SELECT id, $1, $2::text, $3
+	//  FROM webhooks
+	//  WHERE enabled AND (
+	//      $2::text = ANY(event_types)
+	//      OR split_part($2::text, '.', 1) || '.*' = ANY(event_types)
+	//  )
+	EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error)
 	// Get all categories
 	//
-	//  SELECT id, name, parent_id, description, created_at, updated_at FROM category
-	GetAllCategories(ctx context.Context) ([]Category, error)
+	//  SELECT id, name, parent_id, description, version, created_at, updated_at FROM category
+	//  WHERE deleted_at IS NULL
+	GetAllCategories(ctx context.Context) ([]GetAllCategoriesRow, error)
 	// Get all public links
 	//
-	//  SELECT id, url, title, description, short_url, created_at, updated_at FROM links
-	GetAllLinks(ctx context.Context) ([]Link, error)
+	//  SELECT id, url, title, description, short_url, version, created_at, updated_at FROM links
+	//  WHERE deleted_at IS NULL
+	GetAllLinks(ctx context.Context) ([]GetAllLinksRow, error)
+	// Get all webhooks
+	//
+	//  SELECT id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at FROM webhooks
+	//  ORDER BY created_at
+	GetAllWebhooks(ctx context.Context) ([]GetAllWebhooksRow, error)
+	// Get the categories with the given IDs
+	//
+	//  SELECT id, name, parent_id, description, version, created_at, updated_at FROM category
+	//  WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+	GetCategoriesByIDs(ctx context.Context, ids []pgtype.UUID) ([]GetCategoriesByIDsRow, error)
 	// Get all categories linked to a specific link
 	//
 	//  SELECT c.id, c.name, c.description
 	//  FROM category c
 	//  JOIN link_category_map lcm ON c.id = lcm.category_id
-	//  WHERE lcm.link_id = $1
+	//  WHERE lcm.link_id = $1 AND c.deleted_at IS NULL
 	GetCategoriesForLink(ctx context.Context, linkID pgtype.UUID) ([]GetCategoriesForLinkRow, error)
+	// Get the categories of several links at once
+	//
+	//  SELECT lcm.link_id, c.id, c.name, c.parent_id, c.description, c.version, c.created_at, c.updated_at
+	//  FROM category c
+	//  JOIN link_category_map lcm ON c.id = lcm.category_id
+	//  WHERE lcm.link_id = ANY($1::uuid[]) AND c.deleted_at IS NULL
+	GetCategoriesForLinks(ctx context.Context, linkIds []pgtype.UUID) ([]GetCategoriesForLinksRow, error)
 	// Get category by ID
 	//
-	//  SELECT id, name, parent_id, description FROM category
-	//  WHERE id = $1
+	//  SELECT id, name, parent_id, description, version FROM category
+	//  WHERE id = $1 AND deleted_at IS NULL
 	GetCategoryByID(ctx context.Context, id pgtype.UUID) (GetCategoryByIDRow, error)
 	// Get category by name
 	//
 	//  SELECT id, name, parent_id, description FROM category
-	//  WHERE name = $1
+	//  WHERE name = $1 AND deleted_at IS NULL
 	GetCategoryByName(ctx context.Context, name string) (GetCategoryByNameRow, error)
+	// Get an event by its ID
+	//
+	//  SELECT id, type, source, category_ids, data FROM events
+	//  WHERE id = $1
+	GetEventByID(ctx context.Context, id int64) (GetEventByIDRow, error)
+	// Get the ID of the latest event
+	//
+	//  SELECT COALESCE(MAX(id), 0)::bigint FROM events
+	GetLatestEventID(ctx context.Context) (int64, error)
 	// Get link by ID
 	//
-	//  SELECT id, url, title, description, short_url, created_at, updated_at
+	//  SELECT id, url, title, description, short_url, version, created_at, updated_at
 	//  FROM links
-	//  WHERE id = $1
-	GetLinkByID(ctx context.Context, id pgtype.UUID) (Link, error)
+	//  WHERE id = $1 AND deleted_at IS NULL
+	GetLinkByID(ctx context.Context, id pgtype.UUID) (GetLinkByIDRow, error)
 	// Get link by short URL
 	//
 	//  SELECT id, url, title, description, short_url FROM links
-	//  WHERE short_url = $1
+	//  WHERE short_url = $1 AND deleted_at IS NULL
 	GetLinkByShortURL(ctx context.Context, shortUrl string) (GetLinkByShortURLRow, error)
 	// Get link by URL
 	//
-	//  SELECT id, url, title, description, short_url FROM links WHERE url = $1
+	//  SELECT id, url, title, description, short_url FROM links WHERE url = $1 AND deleted_at IS NULL
 	GetLinkByURL(ctx context.Context, url string) (GetLinkByURLRow, error)
+	// Get the links with the given IDs
+	//
+	//  SELECT id, url, title, description, short_url, version, created_at, updated_at
+	//  FROM links
+	//  WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+	GetLinksByIDs(ctx context.Context, ids []pgtype.UUID) ([]GetLinksByIDsRow, error)
+	// Get the links in several categories at once
+	//
+	//  SELECT lcm.category_id, l.id, l.url, l.title, l.description, l.short_url, l.version, l.created_at, l.updated_at
+	//  FROM links l
+	//  JOIN link_category_map lcm ON l.id = lcm.link_id
+	//  WHERE lcm.category_id = ANY($1::uuid[]) AND l.deleted_at IS NULL
+	GetLinksForCategories(ctx context.Context, categoryIds []pgtype.UUID) ([]GetLinksForCategoriesRow, error)
 	// Get all links in a category
 	//
 	//  SELECT l.id, l.url, l.title, l.description, l.short_url, l.created_at, l.updated_at
 	//  FROM links l
 	//  JOIN link_category_map lcm ON l.id = lcm.link_id
-	//  WHERE lcm.category_id = $1
-	GetLinksForCategory(ctx context.Context, categoryID pgtype.UUID) ([]Link, error)
-	// Get paginated links
-	//
-	//  SELECT id, url, title, description, short_url, created_at, updated_at
-	//  FROM links
-	//  ORDER BY created_at DESC
-	//  LIMIT $1 OFFSET $2
-	GetLinksPaginated(ctx context.Context, arg GetLinksPaginatedParams) ([]Link, error)
+	//  WHERE lcm.category_id = $1 AND l.deleted_at IS NULL
+	GetLinksForCategory(ctx context.Context, categoryID pgtype.UUID) ([]GetLinksForCategoryRow, error)
 	// Get subcategories of a category
 	//
 	//  SELECT id, name, description, created_at, updated_at
 	//  FROM category
-	//  WHERE parent_id = $1
+	//  WHERE parent_id = $1 AND deleted_at IS NULL
 	GetSubcategories(ctx context.Context, parentID pgtype.UUID) ([]GetSubcategoriesRow, error)
+	// Get the subcategories of several categories at once
+	//
+	//  SELECT id, name, parent_id, description, version, created_at, updated_at
+	//  FROM category
+	//  WHERE parent_id = ANY($1::uuid[]) AND deleted_at IS NULL
+	GetSubcategoriesOfCategories(ctx context.Context, parentIds []pgtype.UUID) ([]GetSubcategoriesOfCategoriesRow, error)
+	// Get all categories in the trash
+	//
+	//  SELECT id, name, parent_id, description, created_at, updated_at, version, deleted_at
+	//  FROM category
+	//  WHERE deleted_at IS NOT NULL
+	//  ORDER BY deleted_at DESC
+	GetTrashedCategories(ctx context.Context) ([]Category, error)
+	// Get all links in the trash
+	//
+	//  SELECT id, url, title, description, short_url, created_at, updated_at, version, deleted_at
+	//  FROM links
+	//  WHERE deleted_at IS NOT NULL
+	//  ORDER BY deleted_at DESC
+	GetTrashedLinks(ctx context.Context) ([]Link, error)
 	// Get all uncategorized links
 	//
-	//  SELECT id, url, title, description, short_url, created_at, updated_at
+	//  SELECT id, url, title, description, short_url, created_at, updated_at, version, deleted_at
 	//  FROM links l
-	//  WHERE NOT EXISTS (
+	//  WHERE l.deleted_at IS NULL AND NOT EXISTS (
 	//      SELECT 1
 	//      FROM link_category_map lcm
-	//      WHERE l.id = lcm.link_id
+	//      JOIN category c ON c.id = lcm.category_id
+	//      WHERE l.id = lcm.link_id AND c.deleted_at IS NULL
 	//  )
 	GetUncategorizedLinks(ctx context.Context) ([]Link, error)
+	// Get a webhook by its ID
+	//
+	//  SELECT id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at FROM webhooks
+	//  WHERE id = $1
+	GetWebhookByID(ctx context.Context, id pgtype.UUID) (GetWebhookByIDRow, error)
+	// Get the latest deliveries of a webhook
+	//
+	//  SELECT id, webhook_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, response_body, error, created_at
+	//  FROM webhook_deliveries
+	//  WHERE webhook_id = $1
+	//  ORDER BY created_at DESC
+	//  LIMIT $2
+	GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]GetWebhookDeliveriesRow, error)
+	// Append an event to the outbox
+	//
+	//  INSERT INTO events (type, source, category_ids, data)
+	//  VALUES ($1, $2, $3::uuid[], $4)
+	//  RETURNING id
+	InsertEvent(ctx context.Context, arg InsertEventParams) (int64, error)
+	// Get the events published after the given ID, oldest first
+	//
+	//  SELECT id, type, source, category_ids, data FROM events
+	//  WHERE id > $1
+	//  ORDER BY id
+	//  LIMIT $2
+	ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]ListEventsAfterRow, error)
+	// Serialize the transactions publishing events until they commit, so that event IDs are
+	// assigned in commit order and clients resuming after an ID can't miss an event.
+	// The lock is global: every mutation publishes an event, so writes commit one at a time,
+	// and write throughput is bounded by the latency of a single transaction.
+	//
+	//  SELECT pg_advisory_xact_lock(hashtext('refero_events'))
+	LockEvents(ctx context.Context) error
+	// Lock the categories with the given IDs that are not in the trash, so they aren't trashed
+	// before the transaction commits
+	//
+	//  SELECT id FROM category
+	//  WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+	//  FOR SHARE
+	LockLiveCategories(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error)
+	// Lock a category that is not in the trash, so it isn't trashed before the transaction commits
+	//
+	//  SELECT id FROM category
+	//  WHERE id = $1 AND deleted_at IS NULL
+	//  FOR SHARE
+	LockLiveCategory(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
+	// Send a notification to the listeners of a channel, delivered once the transaction commits
+	//
+	//  SELECT pg_notify($1::text, $2::text)
+	Notify(ctx context.Context, arg NotifyParams) error
+	// Delete events older than the retention period
+	//
+	//  DELETE FROM events
+	//  WHERE created_at < now() - $1::bigint * interval '1 second'
+	PurgeEvents(ctx context.Context, retentionSeconds int64) (int64, error)
+	// Permanently delete categories that have been in the trash for longer than the retention period
+	//
+	//  DELETE FROM category
+	//  WHERE deleted_at IS NOT NULL AND deleted_at < now() - $1::bigint * interval '1 second'
+	PurgeTrashedCategories(ctx context.Context, retentionSeconds int64) (int64, error)
+	// Permanently delete links that have been in the trash for longer than the retention period
+	//
+	//  DELETE FROM links
+	//  WHERE deleted_at IS NOT NULL AND deleted_at < now() - $1::bigint * interval '1 second'
+	PurgeTrashedLinks(ctx context.Context, retentionSeconds int64) (int64, error)
+	// Delete deliveries that are done and older than the retention period
+	//
+	//  DELETE FROM webhook_deliveries
+	//  WHERE status <> 'pending' AND created_at < now() - $1::bigint * interval '1 second'
+	PurgeWebhookDeliveries(ctx context.Context, retentionSeconds int64) (int64, error)
+	// Record the outcome of a delivery attempt
+	//
+	//  UPDATE webhook_deliveries
+	//  SET status = $1, attempts = attempts + 1, last_attempt_at = now(),
+	//      next_attempt_at = now() + $2::bigint * interval '1 second',
+	//      response_status = $3, response_body = $4, error = $5
+	//  WHERE id = $6
+	RecordWebhookAttempt(ctx context.Context, arg RecordWebhookAttemptParams) error
+	// Count a failed delivery attempt, and disable the webhook once it failed too often in a row
+	//
+	//  UPDATE webhooks
+	//  SET failure_count = failure_count + 1,
+	//      enabled = enabled AND failure_count + 1 < $1::integer,
+	//      disabled_at = CASE WHEN enabled AND failure_count + 1 >= $1::integer THEN now() ELSE disabled_at END
+	//  WHERE id = $2
+	//  RETURNING enabled
+	RecordWebhookFailure(ctx context.Context, arg RecordWebhookFailureParams) (bool, error)
+	// Queue a delivery of the same event again
+	//
+	//  INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+	//  SELECT d.webhook_id, d.event_id, d.event_type, d.payload FROM webhook_deliveries d
+	//  WHERE d.id = $1 AND d.webhook_id = $2
+	//  RETURNING id, webhook_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, response_body, error, created_at
+	RedeliverWebhookDelivery(ctx context.Context, arg RedeliverWebhookDeliveryParams) (RedeliverWebhookDeliveryRow, error)
 	// Remove a link from a category
 	//
 	//  DELETE FROM link_category_map
 	//  WHERE link_id = $1 AND category_id = $2
 	RemoveLinkFromCategory(ctx context.Context, arg []RemoveLinkFromCategoryParams) *RemoveLinkFromCategoryBatchResults
-	// Update category details
+	// Reset the consecutive failures of a webhook after a successful delivery
+	//
+	//  UPDATE webhooks SET failure_count = 0 WHERE id = $1 AND failure_count > 0
+	ResetWebhookFailures(ctx context.Context, id pgtype.UUID) error
+	// Restore a category and the subcategories that were trashed along with it.
+	// The parent category, if any, has to be restored first.
 	//
+	//  WITH RECURSIVE subtree AS (
+	//      SELECT c.id, c.deleted_at FROM category c
+	//      WHERE c.id = $1 AND c.deleted_at IS NOT NULL AND NOT EXISTS (
+	//          SELECT 1 FROM category p WHERE p.id = c.parent_id AND p.deleted_at IS NOT NULL
+	//      )
+	//      UNION ALL
+	//      SELECT child.id, child.deleted_at FROM category child
+	//      JOIN subtree ON child.parent_id = subtree.id
+	//      WHERE child.deleted_at = subtree.deleted_at
+	//  )
 	//  UPDATE category
-	//  SET name = $1, parent_id = $2, description = $3, updated_at = now()
-	//  WHERE id = $4
-	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (int64, error)
-	// Update link details
+	//  SET deleted_at = NULL, version = version + 1, updated_at = now()
+	//  WHERE id IN (SELECT id FROM subtree)
+	//  RETURNING id, parent_id
+	RestoreCategory(ctx context.Context, id pgtype.UUID) ([]RestoreCategoryRow, error)
+	// Restore a link from the trash, along with its category mappings
 	//
 	//  UPDATE links
-	//  SET title = $1, description = $2, updated_at = now()
-	//  WHERE id = $3
-	UpdateLink(ctx context.Context, arg UpdateLinkParams) (int64, error)
+	//  SET deleted_at = NULL, version = version + 1, updated_at = now()
+	//  WHERE id = $1 AND deleted_at IS NOT NULL
+	//  RETURNING short_url
+	RestoreLink(ctx context.Context, id pgtype.UUID) (string, error)
+	// Get a page of links matching the pattern in their title, URL or description, newest first.
+	// All matching links are returned when no limit is given.
+	//
+	//  SELECT id, url, title, description, short_url, version, created_at, updated_at
+	//  FROM links
+	//  WHERE deleted_at IS NULL
+	//    AND ($1::text IS NULL OR title ILIKE $1 OR url ILIKE $1 OR description ILIKE $1)
+	//  ORDER BY created_at DESC, id
+	//  LIMIT $3::integer OFFSET $2::integer
+	SearchLinks(ctx context.Context, arg SearchLinksParams) ([]SearchLinksRow, error)
+	// Bump the version of a link whose categories changed
+	//
+	//  UPDATE links
+	//  SET version = version + 1, updated_at = now()
+	//  WHERE id = $1 AND deleted_at IS NULL
+	//  RETURNING short_url
+	TouchLink(ctx context.Context, id pgtype.UUID) (string, error)
+	// Update category details, optionally only if it is still at the given version.
+	// Returns the parent the category had before, which a move takes it away from.
+	//
+	//  UPDATE category c
+	//  SET name = $1, parent_id = $2, description = $3, version = c.version + 1, updated_at = now()
+	//  FROM (SELECT p.id, p.parent_id FROM category p WHERE p.id = $5 FOR UPDATE) old
+	//  WHERE c.id = old.id AND c.deleted_at IS NULL AND ($4::integer IS NULL OR c.version = $4)
+	//  RETURNING old.parent_id
+	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (pgtype.UUID, error)
+	// Update link details, optionally only if it is still at the given version
+	//
+	//  UPDATE links
+	//  SET title = $1, url = $2, description = $3, version = version + 1, updated_at = now()
+	//  WHERE id = $4 AND deleted_at IS NULL AND ($5::integer IS NULL OR version = $5)
+	//  RETURNING short_url
+	UpdateLink(ctx context.Context, arg UpdateLinkParams) (string, error)
+	// Update a webhook. Enabling it again resets its failures.
+	//
+	//  UPDATE webhooks
+	//  SET url = $1, description = $2, event_types = $3::text[], enabled = $4,
+	//      failure_count = CASE WHEN $4 AND NOT enabled THEN 0 ELSE failure_count END,
+	//      disabled_at = CASE WHEN $4 THEN NULL ELSE disabled_at END,
+	//      updated_at = now()
+	//  WHERE id = $5
+	//  RETURNING id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at
+	UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (UpdateWebhookRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,704 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/OmprakashD20/refero-api/database/dbtest"
+	"github.com/OmprakashD20/refero-api/repository"
+)
+
+// The queries of each test run in a transaction which is rolled back once it ends,
+// so every statement of a test sees the same now().
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func setup(t *testing.T) (*repository.Queries, pgx.Tx) {
+	t.Helper()
+
+	txn := dbtest.Tx(t)
+	return repository.New(txn), txn
+}
+
+func check(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createLink(t *testing.T, q *repository.Queries, url, shortURL string) pgtype.UUID {
+	t.Helper()
+
+	id, err := q.CreateLink(context.Background(), repository.CreateLinkParams{
+		Url:         url,
+		Title:       "Title of " + url,
+		Description: "Description of " + url,
+		ShortUrl:    shortURL,
+	})
+	check(t, err)
+	return id
+}
+
+func createCategory(t *testing.T, q *repository.Queries, name string, parentID pgtype.UUID) pgtype.UUID {
+	t.Helper()
+
+	description := "Description of " + name
+	id, err := q.CreateCategory(context.Background(), repository.CreateCategoryParams{Name: name, ParentID: parentID, Description: &description})
+	check(t, err)
+	return id
+}
+
+func addToCategory(t *testing.T, q *repository.Queries, linkID, categoryID pgtype.UUID) {
+	t.Helper()
+
+	rows, err := q.AddLinkToCategory(context.Background(), []repository.AddLinkToCategoryParams{{LinkID: linkID, CategoryID: categoryID}})
+	check(t, err)
+	if rows != 1 {
+		t.Fatalf("added %d mappings, want 1", rows)
+	}
+}
+
+func pgCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+func ids[T any](rows []T, id func(T) pgtype.UUID) []pgtype.UUID {
+	list := make([]pgtype.UUID, len(rows))
+	for i, row := range rows {
+		list[i] = id(row)
+	}
+	return list
+}
+
+func sameIDs(got, want []pgtype.UUID) bool {
+	compare := func(a, b pgtype.UUID) int { return slices.Compare(a.Bytes[:], b.Bytes[:]) }
+	got, want = slices.Clone(got), slices.Clone(want)
+	slices.SortFunc(got, compare)
+	slices.SortFunc(want, compare)
+	return slices.Equal(got, want)
+}
+
+func TestLinkQueries(t *testing.T) {
+	q, _ := setup(t)
+	ctx := context.Background()
+
+	id := createLink(t, q, "https://go.dev", "go")
+
+	link, err := q.GetLinkByID(ctx, id)
+	check(t, err)
+	if link.Url != "https://go.dev" || link.ShortUrl != "go" || link.Version != 1 || !link.CreatedAt.Valid {
+		t.Errorf("GetLinkByID: got %+v", link)
+	}
+
+	byURL, err := q.GetLinkByURL(ctx, "https://go.dev")
+	check(t, err)
+	if byURL.ID != id {
+		t.Errorf("GetLinkByURL: got %v, want %v", byURL.ID, id)
+	}
+
+	byShortURL, err := q.GetLinkByShortURL(ctx, "go")
+	check(t, err)
+	if byShortURL.ID != id || byShortURL.Url != "https://go.dev" {
+		t.Errorf("GetLinkByShortURL: got %+v", byShortURL)
+	}
+
+	exists, err := q.CheckIfLinkExistsByURL(ctx, "https://go.dev")
+	check(t, err)
+	if !exists.Exists || exists.ID != id {
+		t.Errorf("CheckIfLinkExistsByURL: got %+v for an existing link", exists)
+	}
+	missing, err := q.CheckIfLinkExistsByURL(ctx, "https://example.com")
+	check(t, err)
+	if missing.Exists || missing.ID.Valid {
+		t.Errorf("CheckIfLinkExistsByURL: got %+v for a missing link", missing)
+	}
+
+	// URLs of links which aren't in the trash and short URLs are unique
+	_, err = q.CreateLink(ctx, repository.CreateLinkParams{Url: "https://go.dev", Title: "Go", Description: "Go", ShortUrl: "go2"})
+	if pgCode(err) != "23505" {
+		t.Errorf("CreateLink with a duplicate URL: got %v, want a unique violation", err)
+	}
+}
+
+func TestListLinkQueries(t *testing.T) {
+	q, txn := setup(t)
+	ctx := context.Background()
+
+	first := createLink(t, q, "https://go.dev", "go")
+	second := createLink(t, q, "https://pkg.go.dev", "pkg")
+	third := createLink(t, q, "https://www.rust-lang.org", "rust")
+	trashed := createLink(t, q, "https://golang.org", "golang")
+	_, err := q.DeleteLink(ctx, repository.DeleteLinkParams{ID: trashed})
+	check(t, err)
+
+	// Every statement of the transaction has the same now(), so creation times are set explicitly
+	dbtest.Exec(t, txn, `UPDATE links SET created_at = now() - interval '1 hour' WHERE id = $1`, first)
+	dbtest.Exec(t, txn, `UPDATE links SET created_at = now() - interval '1 minute' WHERE id = $1`, second)
+
+	all, err := q.GetAllLinks(ctx)
+	check(t, err)
+	if got := ids(all, func(l repository.GetAllLinksRow) pgtype.UUID { return l.ID }); !sameIDs(got, []pgtype.UUID{first, second, third}) {
+		t.Errorf("GetAllLinks: got %v", got)
+	}
+
+	byIDs, err := q.GetLinksByIDs(ctx, []pgtype.UUID{first, trashed, {}})
+	check(t, err)
+	if got := ids(byIDs, func(l repository.GetLinksByIDsRow) pgtype.UUID { return l.ID }); !sameIDs(got, []pgtype.UUID{first}) {
+		t.Errorf("GetLinksByIDs: got %v", got)
+	}
+
+	pattern := func(p string) *string { return &p }
+	limit := func(n int32) *int32 { return &n }
+	tests := []struct {
+		name   string
+		params repository.SearchLinksParams
+		want   []pgtype.UUID
+	}{
+		{"all", repository.SearchLinksParams{}, []pgtype.UUID{third, second, first}},
+		{"case insensitive", repository.SearchLinksParams{Pattern: pattern("%GO.DEV%")}, []pgtype.UUID{second, first}},
+		{"description", repository.SearchLinksParams{Pattern: pattern("%description of https://www%")}, []pgtype.UUID{third}},
+		{"page", repository.SearchLinksParams{Skip: 1, MaxLinks: limit(1)}, []pgtype.UUID{second}},
+		{"trashed", repository.SearchLinksParams{Pattern: pattern("%golang.org%")}, nil},
+	}
+	for _, tt := range tests {
+		links, err := q.SearchLinks(ctx, tt.params)
+		check(t, err)
+		if got := ids(links, func(l repository.SearchLinksRow) pgtype.UUID { return l.ID }); !slices.Equal(got, tt.want) {
+			t.Errorf("SearchLinks %s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateAndDeleteLink(t *testing.T) {
+	q, _ := setup(t)
+	ctx := context.Background()
+
+	id := createLink(t, q, "https://go.dev", "go")
+	stale := int32(2)
+	current := int32(1)
+
+	update := repository.UpdateLinkParams{ID: id, Title: "Go", Url: "https://go.dev/doc", Description: "Documentation", Version: &stale}
+	if _, err := q.UpdateLink(ctx, update); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("UpdateLink at a stale version: got %v, want no rows", err)
+	}
+
+	update.Version = &current
+	shortURL, err := q.UpdateLink(ctx, update)
+	check(t, err)
+	if shortURL != "go" {
+		t.Errorf("UpdateLink: got short URL %q", shortURL)
+	}
+
+	link, err := q.GetLinkByID(ctx, id)
+	check(t, err)
+	if link.Url != "https://go.dev/doc" || link.Version != 2 {
+		t.Errorf("GetLinkByID after the update: got %+v", link)
+	}
+
+	// Unconditional writes don't check the version
+	_, err = q.UpdateLink(ctx, repository.UpdateLinkParams{ID: id, Title: "Go", Url: "https://go.dev", Description: "Home"})
+	check(t, err)
+
+	if _, err := q.DeleteLink(ctx, repository.DeleteLinkParams{ID: id, Version: &current}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("DeleteLink at a stale version: got %v, want no rows", err)
+	}
+	shortURL, err = q.DeleteLink(ctx, repository.DeleteLinkParams{ID: id})
+	check(t, err)
+	if shortURL != "go" {
+		t.Errorf("DeleteLink: got short URL %q", shortURL)
+	}
+
+	if _, err := q.GetLinkByID(ctx, id); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("GetLinkByID of a trashed link: got %v, want no rows", err)
+	}
+	if _, err := q.GetLinkByShortURL(ctx, "go"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("GetLinkByShortURL of a trashed link: got %v, want no rows", err)
+	}
+	if _, err := q.DeleteLink(ctx, repository.DeleteLinkParams{ID: id}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("DeleteLink of a trashed link: got %v, want no rows", err)
+	}
+
+	// The URL of a trashed link can be saved again
+	createLink(t, q, "https://go.dev", "go-again")
+}
+
+func TestCategoryQueries(t *testing.T) {
+	q, _ := setup(t)
+	ctx := context.Background()
+
+	languages := createCategory(t, q, "languages", pgtype.UUID{})
+	golang := createCategory(t, q, "golang", languages)
+	rust := createCategory(t, q, "rust", languages)
+
+	category, err := q.GetCategoryByID(ctx, golang)
+	check(t, err)
+	if category.Name != "golang" || category.ParentID != languages || category.Version != 1 {
+		t.Errorf("GetCategoryByID: got %+v", category)
+	}
+
+	byName, err := q.GetCategoryByName(ctx, "golang")
+	check(t, err)
+	if byName.ID != golang {
+		t.Errorf("GetCategoryByName: got %v, want %v", byName.ID, golang)
+	}
+
+	all, err := q.GetAllCategories(ctx)
+	check(t, err)
+	if got := ids(all, func(c repository.GetAllCategoriesRow) pgtype.UUID { return c.ID }); !sameIDs(got, []pgtype.UUID{languages, golang, rust}) {
+		t.Errorf("GetAllCategories: got %v", got)
+	}
+
+	byIDs, err := q.GetCategoriesByIDs(ctx, []pgtype.UUID{languages, rust})
+	check(t, err)
+	if got := ids(byIDs, func(c repository.GetCategoriesByIDsRow) pgtype.UUID { return c.ID }); !sameIDs(got, []pgtype.UUID{languages, rust}) {
+		t.Errorf("GetCategoriesByIDs: got %v", got)
+	}
+
+	subcategories, err := q.GetSubcategories(ctx, languages)
+	check(t, err)
+	if got := ids(subcategories, func(c repository.GetSubcategoriesRow) pgtype.UUID { return c.ID }); !sameIDs(got, []pgtype.UUID{golang, rust}) {
+		t.Errorf("GetSubcategories: got %v", got)
+	}
+
+	ofCategories, err := q.GetSubcategoriesOfCategories(ctx, []pgtype.UUID{languages, golang})
+	check(t, err)
+	for _, c := range ofCategories {
+		if c.ParentID != languages {
+			t.Errorf("GetSubcategoriesOfCategories: got %s with parent %v", c.Name, c.ParentID)
+		}
+	}
+	if len(ofCategories) != 2 {
+		t.Errorf("GetSubcategoriesOfCategories: got %d subcategories, want 2", len(ofCategories))
+	}
+
+	// Names of categories which aren't in the trash are unique, and parents have to exist
+	_, err = q.CreateCategory(ctx, repository.CreateCategoryParams{Name: "golang"})
+	if pgCode(err) != "23505" {
+		t.Errorf("CreateCategory with a duplicate name: got %v, want a unique violation", err)
+	}
+}
+
+func TestCategoryConstraints(t *testing.T) {
+	q, txn := setup(t)
+
+	missing := createCategory(t, q, "missing", pgtype.UUID{})
+	dbtest.Exec(t, txn, `DELETE FROM category WHERE id = $1`, missing)
+
+	_, err := q.CreateCategory(context.Background(), repository.CreateCategoryParams{Name: "orphan", ParentID: missing})
+	if pgCode(err) != "23503" {
+		t.Errorf("CreateCategory with a missing parent: got %v, want a foreign key violation", err)
+	}
+}
+
+func TestUpdateAndDeleteCategory(t *testing.T) {
+	q, _ := setup(t)
+	ctx := context.Background()
+
+	languages := createCategory(t, q, "languages", pgtype.UUID{})
+	golang := createCategory(t, q, "golang", languages)
+	generics := createCategory(t, q, "generics", golang)
+	stale := int32(2)
+	current := int32(1)
+
+	// Moving golang to the top level returns the parent it is moved away from
+	update := repository.UpdateCategoryParams{ID: golang, Name: "go", Version: &stale}
+	if _, err := q.UpdateCategory(ctx, update); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("UpdateCategory at a stale version: got %v, want no rows", err)
+	}
+
+	update.Version = &current
+	oldParent, err := q.UpdateCategory(ctx, update)
+	check(t, err)
+	if oldParent != languages {
+		t.Errorf("UpdateCategory: got old parent %v, want %v", oldParent, languages)
+	}
+
+	category, err := q.GetCategoryByID(ctx, golang)
+	check(t, err)
+	if category.Name != "go" || category.Version != 2 || category.Description != nil || category.ParentID.Valid {
+		t.Errorf("GetCategoryByID after the update: got %+v", category)
+	}
+
+	// Move it back, so the subcategories are trashed along with the category
+	update = repository.UpdateCategoryParams{ID: golang, Name: "go", ParentID: languages}
+	oldParent, err = q.UpdateCategory(ctx, update)
+	check(t, err)
+	if oldParent.Valid {
+		t.Errorf("UpdateCategory of a top level category: got old parent %v", oldParent)
+	}
+
+	trashed, err := q.DeleteCategory(ctx, repository.DeleteCategoryParams{ID: languages, Version: &stale})
+	check(t, err)
+	if len(trashed) != 0 {
+		t.Errorf("DeleteCategory at a stale version deleted %d rows", len(trashed))
+	}
+
+	trashed, err = q.DeleteCategory(ctx, repository.DeleteCategoryParams{ID: languages, Version: &current})
+	check(t, err)
+	if got := ids(trashed, func(c repository.DeleteCategoryRow) pgtype.UUID { return c.ID }); !sameIDs(got, []pgtype.UUID{languages, golang, generics}) {
+		t.Errorf("DeleteCategory: got %v, want the category and its subcategories", got)
+	}
+	for _, id := range []pgtype.UUID{languages, golang, generics} {
+		if _, err := q.GetCategoryByID(ctx, id); !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("GetCategoryByID of a trashed category: got %v, want no rows", err)
+		}
+	}
+
+	if _, err := q.UpdateCategory(ctx, repository.UpdateCategoryParams{ID: languages, Name: "languages"}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("UpdateCategory of a trashed category: got %v, want no rows", err)
+	}
+
+	// The name of a trashed category can be used again
+	createCategory(t, q, "languages", pgtype.UUID{})
+}
+
+func TestLinkCategoryQueries(t *testing.T) {
+	q, _ := setup(t)
+	ctx := context.Background()
+
+	golang := createCategory(t, q, "golang", pgtype.UUID{})
+	tools := createCategory(t, q, "tools", pgtype.UUID{})
+	trashedCategory := createCategory(t, q, "trashed", pgtype.UUID{})
+	goDev := createLink(t, q, "https://go.dev", "go")
+	gopls := createLink(t, q, "https://go.dev/gopls", "gopls")
+	uncategorized := createLink(t, q, "https://example.com", "example")
+	onlyTrashed := createLink(t, q, "https://example.org", "example-org")
+
+	rows, err := q.AddLinkToCategory(ctx, []repository.AddLinkToCategoryParams{
+		{LinkID: goDev, CategoryID: golang},
+		{LinkID: gopls, CategoryID: golang},
+		{LinkID: gopls, CategoryID: tools},
+		{LinkID: goDev, CategoryID: trashedCategory},
+		{LinkID: onlyTrashed, CategoryID: trashedCategory},
+	})
+	check(t, err)
+	if rows != 5 {
+		t.Errorf("AddLinkToCategory added %d mappings, want 5", rows)
+	}
+	_, err = q.DeleteCategory(ctx, repository.DeleteCategoryParams{ID: trashedCategory})
+	check(t, err)
+
+	categories, err := q.GetCategoriesForLink(ctx, goDev)
+	check(t, err)
+	if got := ids(categories, func(c repository.GetCategoriesForLinkRow) pgtype.UUID { return c.ID }); !sameIDs(got, []pgtype.UUID{golang}) {
+		t.Errorf("GetCategoriesForLink: got %v", got)
+	}
+
+	forLinks, err := q.GetCategoriesForLinks(ctx, []pgtype.UUID{goDev, gopls})
+	check(t, err)
+	if len(forLinks) != 3 {
+		t.Errorf("GetCategoriesForLinks: got %d mappings, want 3", len(forLinks))
+	}
+
+	links, err := q.GetLinksForCategory(ctx, golang)
+	check(t, err)
+	if got := ids(links, func(l repository.GetLinksForCategoryRow) pgtype.UUID { return l.ID }); !sameIDs(got, []pgtype.UUID{goDev, gopls}) {
+		t.Errorf("GetLinksForCategory: got %v", got)
+	}
+
+	forCategories, err := q.GetLinksForCategories(ctx, []pgtype.UUID{golang, tools})
+	check(t, err)
+	if len(forCategories) != 3 {
+		t.Errorf("GetLinksForCategories: got %d mappings, want 3", len(forCategories))
+	}
+
+	// Links only in trashed categories are uncategorized
+	uncategorizedLinks, err := q.GetUncategorizedLinks(ctx)
+	check(t, err)
+	if got := ids(uncategorizedLinks, func(l repository.Link) pgtype.UUID { return l.ID }); !sameIDs(got, []pgtype.UUID{uncategorized, onlyTrashed}) {
+		t.Errorf("GetUncategorizedLinks: got %v", got)
+	}
+
+	results := q.RemoveLinkFromCategory(ctx, []repository.RemoveLinkFromCategoryParams{
+		{LinkID: gopls, CategoryID: golang},
+		{LinkID: gopls, CategoryID: golang}, // Removing a missing mapping is not an error
+	})
+	var batchErr error
+	results.Exec(func(i int, err error) {
+		if err != nil {
+			batchErr = err
+		}
+	})
+	check(t, results.Close())
+	check(t, batchErr)
+
+	links, err = q.GetLinksForCategory(ctx, golang)
+	check(t, err)
+	if got := ids(links, func(l repository.GetLinksForCategoryRow) pgtype.UUID { return l.ID }); !sameIDs(got, []pgtype.UUID{goDev}) {
+		t.Errorf("GetLinksForCategory after the removal: got %v", got)
+	}
+}
+
+func TestLinkCategoryConstraints(t *testing.T) {
+	q, txn := setup(t)
+	ctx := context.Background()
+
+	golang := createCategory(t, q, "golang", pgtype.UUID{})
+	link := createLink(t, q, "https://go.dev", "go")
+	addToCategory(t, q, link, golang)
+
+	// A failed copy aborts the transaction, so every case runs in a savepoint
+	cases := map[string]struct {
+		mapping repository.AddLinkToCategoryParams
+		code    string
+	}{
+		"duplicate":        {repository.AddLinkToCategoryParams{LinkID: link, CategoryID: golang}, "23505"},
+		"missing category": {repository.AddLinkToCategoryParams{LinkID: link, CategoryID: link}, "23503"},
+		"missing link":     {repository.AddLinkToCategoryParams{LinkID: golang, CategoryID: golang}, "23503"},
+	}
+	for name, tt := range cases {
+		savepoint, err := txn.Begin(ctx)
+		check(t, err)
+
+		_, err = repository.New(savepoint).AddLinkToCategory(ctx, []repository.AddLinkToCategoryParams{tt.mapping})
+		if pgCode(err) != tt.code {
+			t.Errorf("AddLinkToCategory %s: got %v, want code %s", name, err, tt.code)
+		}
+		check(t, savepoint.Rollback(ctx))
+	}
+}
+
+func TestTrashQueries(t *testing.T) {
+	q, txn := setup(t)
+	ctx := context.Background()
+
+	languages := createCategory(t, q, "languages", pgtype.UUID{})
+	golang := createCategory(t, q, "golang", languages)
+	link := createLink(t, q, "https://go.dev", "go")
+	oldLink := createLink(t, q, "https://golang.org", "golang")
+
+	_, err := q.DeleteLink(ctx, repository.DeleteLinkParams{ID: link})
+	check(t, err)
+	_, err = q.DeleteLink(ctx, repository.DeleteLinkParams{ID: oldLink})
+	check(t, err)
+	_, err = q.DeleteCategory(ctx, repository.DeleteCategoryParams{ID: languages})
+	check(t, err)
+
+	links, err := q.GetTrashedLinks(ctx)
+	check(t, err)
+	if got := ids(links, func(l repository.Link) pgtype.UUID { return l.ID }); !sameIDs(got, []pgtype.UUID{link, oldLink}) {
+		t.Errorf("GetTrashedLinks: got %v", got)
+	}
+	categories, err := q.GetTrashedCategories(ctx)
+	check(t, err)
+	if got := ids(categories, func(c repository.Category) pgtype.UUID { return c.ID }); !sameIDs(got, []pgtype.UUID{languages, golang}) {
+		t.Errorf("GetTrashedCategories: got %v", got)
+	}
+
+	shortURL, err := q.RestoreLink(ctx, link)
+	check(t, err)
+	if shortURL != "go" {
+		t.Errorf("RestoreLink: got short URL %q, want go", shortURL)
+	}
+	if _, err = q.RestoreLink(ctx, link); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("RestoreLink of a link which is not in the trash: got %v, want %v", err, pgx.ErrNoRows)
+	}
+
+	// Subcategories can only be restored after their parent, which restores them along with it
+	restored, err := q.RestoreCategory(ctx, golang)
+	check(t, err)
+	if len(restored) != 0 {
+		t.Errorf("RestoreCategory restored %d categories while the parent is in the trash", len(restored))
+	}
+	restored, err = q.RestoreCategory(ctx, languages)
+	check(t, err)
+	if got := ids(restored, func(c repository.RestoreCategoryRow) pgtype.UUID { return c.ID }); !sameIDs(got, []pgtype.UUID{languages, golang}) {
+		t.Errorf("RestoreCategory: got %v, want the category and its subcategory", got)
+	}
+
+	// Trashed rows are purged once the retention period has passed
+	dbtest.Exec(t, txn, `UPDATE links SET deleted_at = now() - interval '2 days' WHERE id = $1`, oldLink)
+	_, err = q.DeleteCategory(ctx, repository.DeleteCategoryParams{ID: golang})
+	check(t, err)
+
+	retention := int64(60 * 60 * 24)
+	purged, err := q.PurgeTrashedLinks(ctx, retention)
+	check(t, err)
+	if purged != 1 {
+		t.Errorf("PurgeTrashedLinks purged %d links, want 1", purged)
+	}
+	purged, err = q.PurgeTrashedCategories(ctx, retention)
+	check(t, err)
+	if purged != 0 {
+		t.Errorf("PurgeTrashedCategories purged %d recently trashed categories", purged)
+	}
+	purged, err = q.PurgeTrashedCategories(ctx, -1)
+	check(t, err)
+	if purged != 1 {
+		t.Errorf("PurgeTrashedCategories purged %d categories, want 1", purged)
+	}
+}
+
+func TestEventQueries(t *testing.T) {
+	q, txn := setup(t)
+	ctx := context.Background()
+
+	latest, err := q.GetLatestEventID(ctx)
+	check(t, err)
+
+	check(t, q.LockEvents(ctx))
+
+	category := createCategory(t, q, "golang", pgtype.UUID{})
+	data, _ := json.Marshal(map[string]string{"id": "1"})
+	first, err := q.InsertEvent(ctx, repository.InsertEventParams{Type: "link.created", Source: "test", CategoryIds: []pgtype.UUID{category}, Data: data})
+	check(t, err)
+	second, err := q.InsertEvent(ctx, repository.InsertEventParams{Type: "link.deleted", Source: "test", CategoryIds: []pgtype.UUID{}, Data: data})
+	check(t, err)
+	if first <= latest || second <= first {
+		t.Errorf("InsertEvent: got IDs %d and %d after %d, want them increasing", first, second, latest)
+	}
+
+	if got, err := q.GetLatestEventID(ctx); err != nil || got != second {
+		t.Errorf("GetLatestEventID: got %d, %v, want %d", got, err, second)
+	}
+
+	event, err := q.GetEventByID(ctx, first)
+	check(t, err)
+	if event.Type != "link.created" || event.Source != "test" || len(event.CategoryIds) != 1 || event.CategoryIds[0] != category {
+		t.Errorf("GetEventByID: got %+v", event)
+	}
+
+	events, err := q.ListEventsAfter(ctx, repository.ListEventsAfterParams{AfterID: latest, MaxEvents: 1})
+	check(t, err)
+	if len(events) != 1 || events[0].ID != first || events[0].Type != "link.created" || len(events[0].CategoryIds) != 1 || events[0].CategoryIds[0] != category {
+		t.Errorf("ListEventsAfter: got %+v", events)
+	}
+	events, err = q.ListEventsAfter(ctx, repository.ListEventsAfterParams{AfterID: first, MaxEvents: 10})
+	check(t, err)
+	if len(events) != 1 || events[0].ID != second {
+		t.Errorf("ListEventsAfter the first event: got %+v", events)
+	}
+
+	dbtest.Exec(t, txn, `UPDATE events SET created_at = now() - interval '2 days' WHERE id = $1`, first)
+	purged, err := q.PurgeEvents(ctx, 60*60*24)
+	check(t, err)
+	if purged != 1 {
+		t.Errorf("PurgeEvents purged %d events, want 1", purged)
+	}
+
+	// Notifications are only delivered on commit, which the rollback of the test prevents
+	check(t, q.Notify(ctx, repository.NotifyParams{Channel: "refero_test", Payload: "{}"}))
+}
+
+func TestWebhookQueries(t *testing.T) {
+	q, _ := setup(t)
+	ctx := context.Background()
+
+	created, err := q.CreateWebhook(ctx, repository.CreateWebhookParams{
+		Url:        "https://hooks.example.com/links",
+		Secret:     "secret",
+		EventTypes: []string{"link.*"},
+	})
+	check(t, err)
+	if !created.Enabled || created.FailureCount != 0 || !slices.Equal(created.EventTypes, []string{"link.*"}) {
+		t.Errorf("CreateWebhook: got %+v", created)
+	}
+	other, err := q.CreateWebhook(ctx, repository.CreateWebhookParams{Url: "https://hooks.example.com/categories", Secret: "secret", EventTypes: []string{"category.created"}})
+	check(t, err)
+
+	webhook, err := q.GetWebhookByID(ctx, created.ID)
+	check(t, err)
+	if webhook.Url != created.Url {
+		t.Errorf("GetWebhookByID: got %+v", webhook)
+	}
+	all, err := q.GetAllWebhooks(ctx)
+	check(t, err)
+	if len(all) != 2 {
+		t.Errorf("GetAllWebhooks: got %d webhooks, want 2", len(all))
+	}
+
+	// Deliveries are queued for the webhooks subscribed to the type, or its wildcard
+	queued, err := q.EnqueueWebhookDeliveries(ctx, repository.EnqueueWebhookDeliveriesParams{EventID: 1, EventType: "link.created", Payload: []byte(`{}`)})
+	check(t, err)
+	if queued != 1 {
+		t.Errorf("EnqueueWebhookDeliveries queued %d deliveries, want 1", queued)
+	}
+	queued, err = q.EnqueueWebhookDeliveries(ctx, repository.EnqueueWebhookDeliveriesParams{EventID: 2, EventType: "category.deleted", Payload: []byte(`{}`)})
+	check(t, err)
+	if queued != 0 {
+		t.Errorf("EnqueueWebhookDeliveries queued %d deliveries for an unsubscribed type", queued)
+	}
+
+	claimed, err := q.ClaimWebhookDeliveries(ctx, repository.ClaimWebhookDeliveriesParams{LeaseSeconds: 60, MaxDeliveries: 10})
+	check(t, err)
+	if len(claimed) != 1 || claimed[0].WebhookID != created.ID || claimed[0].Secret != "secret" || claimed[0].EventType != "link.created" {
+		t.Fatalf("ClaimWebhookDeliveries: got %+v", claimed)
+	}
+	// Leased deliveries are not claimed again
+	if again, err := q.ClaimWebhookDeliveries(ctx, repository.ClaimWebhookDeliveriesParams{LeaseSeconds: 60, MaxDeliveries: 10}); err != nil || len(again) != 0 {
+		t.Errorf("ClaimWebhookDeliveries of leased deliveries: got %+v, %v", again, err)
+	}
+
+	status := int32(500)
+	check(t, q.RecordWebhookAttempt(ctx, repository.RecordWebhookAttemptParams{
+		ID:             claimed[0].ID,
+		Status:         "failed",
+		ResponseStatus: &status,
+	}))
+	enabled, err := q.RecordWebhookFailure(ctx, repository.RecordWebhookFailureParams{ID: created.ID, MaxFailures: 2})
+	check(t, err)
+	if !enabled {
+		t.Error("RecordWebhookFailure disabled the webhook after the first failure")
+	}
+	check(t, q.ResetWebhookFailures(ctx, created.ID))
+	for range 2 {
+		enabled, err = q.RecordWebhookFailure(ctx, repository.RecordWebhookFailureParams{ID: created.ID, MaxFailures: 2})
+		check(t, err)
+	}
+	if enabled {
+		t.Error("RecordWebhookFailure didn't disable the webhook after failing twice in a row")
+	}
+
+	deliveries, err := q.GetWebhookDeliveries(ctx, repository.GetWebhookDeliveriesParams{WebhookID: created.ID, MaxDeliveries: 10})
+	check(t, err)
+	if len(deliveries) != 1 || deliveries[0].Status != "failed" || deliveries[0].Attempts != 1 || *deliveries[0].ResponseStatus != 500 {
+		t.Fatalf("GetWebhookDeliveries: got %+v", deliveries)
+	}
+
+	redelivery, err := q.RedeliverWebhookDelivery(ctx, repository.RedeliverWebhookDeliveryParams{ID: claimed[0].ID, WebhookID: created.ID})
+	check(t, err)
+	if redelivery.Status != "pending" || redelivery.EventID != 1 || redelivery.Attempts != 0 {
+		t.Errorf("RedeliverWebhookDelivery: got %+v", redelivery)
+	}
+	if _, err := q.RedeliverWebhookDelivery(ctx, repository.RedeliverWebhookDeliveryParams{ID: claimed[0].ID, WebhookID: other.ID}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("RedeliverWebhookDelivery of another webhook: got %v, want no rows", err)
+	}
+
+	// Only completed deliveries are purged
+	purged, err := q.PurgeWebhookDeliveries(ctx, -1)
+	check(t, err)
+	if purged != 1 {
+		t.Errorf("PurgeWebhookDeliveries purged %d deliveries, want 1", purged)
+	}
+
+	// Enabling the webhook again resets its failures
+	updated, err := q.UpdateWebhook(ctx, repository.UpdateWebhookParams{ID: created.ID, Url: created.Url, EventTypes: []string{"link.created"}, Enabled: true})
+	check(t, err)
+	if !updated.Enabled || updated.FailureCount != 0 || updated.DisabledAt.Valid {
+		t.Errorf("UpdateWebhook: got %+v", updated)
+	}
+
+	rows, err := q.DeleteWebhook(ctx, created.ID)
+	check(t, err)
+	if rows != 1 {
+		t.Errorf("DeleteWebhook deleted %d webhooks, want 1", rows)
+	}
+	if _, err := q.GetWebhookByID(ctx, created.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("GetWebhookByID of a deleted webhook: got %v, want no rows", err)
+	}
+}
@@ -0,0 +1,210 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: trash.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getTrashedCategories = `-- name: GetTrashedCategories :many
+SELECT id, name, parent_id, description, created_at, updated_at, version, deleted_at 
+FROM category 
+WHERE deleted_at IS NOT NULL 
+ORDER BY deleted_at DESC
+`
+
+// Get all categories in the trash
+//
+//  SELECT id, name, parent_id, description, created_at, updated_at, version, deleted_at
+//  FROM category
+//  WHERE deleted_at IS NOT NULL
+//  ORDER BY deleted_at DESC
+func (q *Queries) GetTrashedCategories(ctx context.Context) ([]Category, error) {
+	rows, err := q.db.Query(ctx, getTrashedCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ParentID,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedLinks = `-- name: GetTrashedLinks :many
+SELECT id, url, title, description, short_url, created_at, updated_at, version, deleted_at 
+FROM links 
+WHERE deleted_at IS NOT NULL 
+ORDER BY deleted_at DESC
+`
+
+// Get all links in the trash
+//
+//  SELECT id, url, title, description, short_url, created_at, updated_at, version, deleted_at
+//  FROM links
+//  WHERE deleted_at IS NOT NULL
+//  ORDER BY deleted_at DESC
+func (q *Queries) GetTrashedLinks(ctx context.Context) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getTrashedLinks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Link
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.ShortUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeTrashedCategories = `-- name: PurgeTrashedCategories :execrows
+DELETE FROM category 
+WHERE deleted_at IS NOT NULL AND deleted_at < now() - $1::bigint * interval '1 second'
+`
+
+// Permanently delete categories that have been in the trash for longer than the retention period
+//
+//  DELETE FROM category
+//  WHERE deleted_at IS NOT NULL AND deleted_at < now() - $1::bigint * interval '1 second'
+func (q *Queries) PurgeTrashedCategories(ctx context.Context, retentionSeconds int64) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeTrashedCategories, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeTrashedLinks = `-- name: PurgeTrashedLinks :execrows
+DELETE FROM links 
+WHERE deleted_at IS NOT NULL AND deleted_at < now() - $1::bigint * interval '1 second'
+`
+
+// Permanently delete links that have been in the trash for longer than the retention period
+//
+//  DELETE FROM links
+//  WHERE deleted_at IS NOT NULL AND deleted_at < now() - $1::bigint * interval '1 second'
+func (q *Queries) PurgeTrashedLinks(ctx context.Context, retentionSeconds int64) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeTrashedLinks, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreCategory = `-- name: RestoreCategory :many
+WITH RECURSIVE subtree AS (
+    SELECT c.id, c.deleted_at FROM category c
+    WHERE c.id = $1 AND c.deleted_at IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM category p WHERE p.id = c.parent_id AND p.deleted_at IS NOT NULL
+    )
+    UNION ALL
+    SELECT child.id, child.deleted_at FROM category child
+    JOIN subtree ON child.parent_id = subtree.id
+    WHERE child.deleted_at = subtree.deleted_at
+)
+UPDATE category 
+SET deleted_at = NULL, version = version + 1, updated_at = now() 
+WHERE id IN (SELECT id FROM subtree)
+RETURNING id, parent_id
+`
+
+type RestoreCategoryRow struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	ParentID pgtype.UUID `db:"parent_id" json:"parentId"`
+}
+
+// Restore a category and the subcategories that were trashed along with it.
+// The parent category, if any, has to be restored first.
+//
+//  WITH RECURSIVE subtree AS (
+//      SELECT c.id, c.deleted_at FROM category c
+//      WHERE c.id = $1 AND c.deleted_at IS NOT NULL AND NOT EXISTS (
+//          SELECT 1 FROM category p WHERE p.id = c.parent_id AND p.deleted_at IS NOT NULL
+//      )
+//      UNION ALL
+//      SELECT child.id, child.deleted_at FROM category child
+//      JOIN subtree ON child.parent_id = subtree.id
+//      WHERE child.deleted_at = subtree.deleted_at
+//  )
+//  UPDATE category
+//  SET deleted_at = NULL, version = version + 1, updated_at = now()
+//  WHERE id IN (SELECT id FROM subtree)
+//  RETURNING id, parent_id
+func (q *Queries) RestoreCategory(ctx context.Context, id pgtype.UUID) ([]RestoreCategoryRow, error) {
+	rows, err := q.db.Query(ctx, restoreCategory, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RestoreCategoryRow
+	for rows.Next() {
+		var i RestoreCategoryRow
+		if err := rows.Scan(&i.ID, &i.ParentID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreLink = `-- name: RestoreLink :one
+UPDATE links 
+SET deleted_at = NULL, version = version + 1, updated_at = now() 
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING short_url
+`
+
+// Restore a link from the trash, along with its category mappings
+//
+//  UPDATE links
+//  SET deleted_at = NULL, version = version + 1, updated_at = now()
+//  WHERE id = $1 AND deleted_at IS NOT NULL
+//  RETURNING short_url
+func (q *Queries) RestoreLink(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, restoreLink, id)
+	var short_url string
+	err := row.Scan(&short_url)
+	return short_url, err
+}
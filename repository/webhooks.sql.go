@@ -0,0 +1,556 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: webhooks.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+WITH due AS (
+    SELECT d.id FROM webhook_deliveries d
+    JOIN webhooks w ON w.id = d.webhook_id
+    WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND w.enabled
+    ORDER BY d.next_attempt_at
+    LIMIT $2
+    FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = now() + $1::bigint * interval '1 second'
+FROM due, webhooks w
+WHERE d.id = due.id AND w.id = d.webhook_id
+RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.attempts, d.created_at, w.url, w.secret
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseSeconds  int64 `db:"lease_seconds" json:"leaseSeconds"`
+	MaxDeliveries int32 `db:"max_deliveries" json:"maxDeliveries"`
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID        pgtype.UUID      `db:"id" json:"id"`
+	WebhookID pgtype.UUID      `db:"webhook_id" json:"webhookId"`
+	EventID   int64            `db:"event_id" json:"eventId"`
+	EventType string           `db:"event_type" json:"eventType"`
+	Payload   []byte           `db:"payload" json:"payload"`
+	Attempts  int32            `db:"attempts" json:"attempts"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	Url       string           `db:"url" json:"url"`
+	Secret    string           `db:"secret" json:"secret"`
+}
+
+// Lease the deliveries that are due, so that no other instance attempts them until the lease expires
+//
+//  WITH due AS (
+//      SELECT d.id FROM webhook_deliveries d
+//      JOIN webhooks w ON w.id = d.webhook_id
+//      WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND w.enabled
+//      ORDER BY d.next_attempt_at
+//      LIMIT $2
+//      FOR UPDATE OF d SKIP LOCKED
+//  )
+//  UPDATE webhook_deliveries d
+//  SET next_attempt_at = now() + $1::bigint * interval '1 second'
+//  FROM due, webhooks w
+//  WHERE d.id = due.id AND w.id = d.webhook_id
+//  RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.attempts, d.created_at, w.url, w.secret
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, claimWebhookDeliveries, arg.LeaseSeconds, arg.MaxDeliveries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimWebhookDeliveriesRow
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Attempts,
+			&i.CreatedAt,
+			&i.Url,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks (url, description, secret, event_types)
+VALUES ($1, $2, $3, $4::text[])
+RETURNING id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at
+`
+
+type CreateWebhookParams struct {
+	Url         string   `db:"url" json:"url"`
+	Description string   `db:"description" json:"description"`
+	Secret      string   `db:"secret" json:"secret"`
+	EventTypes  []string `db:"event_types" json:"eventTypes"`
+}
+
+type CreateWebhookRow struct {
+	ID           pgtype.UUID      `db:"id" json:"id"`
+	Url          string           `db:"url" json:"url"`
+	Description  string           `db:"description" json:"description"`
+	EventTypes   []string         `db:"event_types" json:"eventTypes"`
+	Enabled      bool             `db:"enabled" json:"enabled"`
+	FailureCount int32            `db:"failure_count" json:"failureCount"`
+	DisabledAt   pgtype.Timestamp `db:"disabled_at" json:"disabledAt"`
+	CreatedAt    pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt    pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Register a webhook
+//
+//  INSERT INTO webhooks (url, description, secret, event_types)
+//  VALUES ($1, $2, $3, $4::text[])
+//  RETURNING id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (CreateWebhookRow, error) {
+	row := q.db.QueryRow(ctx, createWebhook,
+		arg.Url,
+		arg.Description,
+		arg.Secret,
+		arg.EventTypes,
+	)
+	var i CreateWebhookRow
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Description,
+		&i.EventTypes,
+		&i.Enabled,
+		&i.FailureCount,
+		&i.DisabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :execrows
+DELETE FROM webhooks WHERE id = $1
+`
+
+// Delete a webhook along with its deliveries
+//
+//  DELETE FROM webhooks WHERE id = $1
+func (q *Queries) DeleteWebhook(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhook, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+SELECT id, $1, $2::text, $3
+FROM webhooks
+WHERE enabled AND (
+    $2::text = ANY(event_types)
+    OR split_part($2::text, '.', 1) || '.*' = ANY(event_types)
+)
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	EventID   int64  `db:"event_id" json:"eventId"`
+	EventType string `db:"event_type" json:"eventType"`
+	Payload   []byte `db:"payload" json:"payload"`
+}
+
+// Queue a delivery of the event for every enabled webhook subscribed to its type
+//
+//  INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+//  SELECT id, $1, $2::text, $3
+//  FROM webhooks
+//  WHERE enabled AND (
+//      $2::text = ANY(event_types)
+//      OR split_part($2::text, '.', 1) || '.*' = ANY(event_types)
+//  )
+func (q *Queries) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enqueueWebhookDeliveries, arg.EventID, arg.EventType, arg.Payload)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getAllWebhooks = `-- name: GetAllWebhooks :many
+SELECT id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at FROM webhooks
+ORDER BY created_at
+`
+
+type GetAllWebhooksRow struct {
+	ID           pgtype.UUID      `db:"id" json:"id"`
+	Url          string           `db:"url" json:"url"`
+	Description  string           `db:"description" json:"description"`
+	EventTypes   []string         `db:"event_types" json:"eventTypes"`
+	Enabled      bool             `db:"enabled" json:"enabled"`
+	FailureCount int32            `db:"failure_count" json:"failureCount"`
+	DisabledAt   pgtype.Timestamp `db:"disabled_at" json:"disabledAt"`
+	CreatedAt    pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt    pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Get all webhooks
+//
+//  SELECT id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at FROM webhooks
+//  ORDER BY created_at
+func (q *Queries) GetAllWebhooks(ctx context.Context) ([]GetAllWebhooksRow, error) {
+	rows, err := q.db.Query(ctx, getAllWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllWebhooksRow
+	for rows.Next() {
+		var i GetAllWebhooksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Description,
+			&i.EventTypes,
+			&i.Enabled,
+			&i.FailureCount,
+			&i.DisabledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhookByID = `-- name: GetWebhookByID :one
+SELECT id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at FROM webhooks
+WHERE id = $1
+`
+
+type GetWebhookByIDRow struct {
+	ID           pgtype.UUID      `db:"id" json:"id"`
+	Url          string           `db:"url" json:"url"`
+	Description  string           `db:"description" json:"description"`
+	EventTypes   []string         `db:"event_types" json:"eventTypes"`
+	Enabled      bool             `db:"enabled" json:"enabled"`
+	FailureCount int32            `db:"failure_count" json:"failureCount"`
+	DisabledAt   pgtype.Timestamp `db:"disabled_at" json:"disabledAt"`
+	CreatedAt    pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt    pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Get a webhook by its ID
+//
+//  SELECT id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at FROM webhooks
+//  WHERE id = $1
+func (q *Queries) GetWebhookByID(ctx context.Context, id pgtype.UUID) (GetWebhookByIDRow, error) {
+	row := q.db.QueryRow(ctx, getWebhookByID, id)
+	var i GetWebhookByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Description,
+		&i.EventTypes,
+		&i.Enabled,
+		&i.FailureCount,
+		&i.DisabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWebhookDeliveries = `-- name: GetWebhookDeliveries :many
+SELECT id, webhook_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, response_body, error, created_at
+FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type GetWebhookDeliveriesParams struct {
+	WebhookID     pgtype.UUID `db:"webhook_id" json:"webhookId"`
+	MaxDeliveries int32       `db:"max_deliveries" json:"maxDeliveries"`
+}
+
+type GetWebhookDeliveriesRow struct {
+	ID             pgtype.UUID      `db:"id" json:"id"`
+	WebhookID      pgtype.UUID      `db:"webhook_id" json:"webhookId"`
+	EventID        int64            `db:"event_id" json:"eventId"`
+	EventType      string           `db:"event_type" json:"eventType"`
+	Status         string           `db:"status" json:"status"`
+	Attempts       int32            `db:"attempts" json:"attempts"`
+	NextAttemptAt  pgtype.Timestamp `db:"next_attempt_at" json:"nextAttemptAt"`
+	LastAttemptAt  pgtype.Timestamp `db:"last_attempt_at" json:"lastAttemptAt"`
+	ResponseStatus *int32           `db:"response_status" json:"responseStatus"`
+	ResponseBody   *string          `db:"response_body" json:"responseBody"`
+	Error          *string          `db:"error" json:"error"`
+	CreatedAt      pgtype.Timestamp `db:"created_at" json:"createdAt"`
+}
+
+// Get the latest deliveries of a webhook
+//
+//  SELECT id, webhook_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, response_body, error, created_at
+//  FROM webhook_deliveries
+//  WHERE webhook_id = $1
+//  ORDER BY created_at DESC
+//  LIMIT $2
+func (q *Queries) GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]GetWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, getWebhookDeliveries, arg.WebhookID, arg.MaxDeliveries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWebhookDeliveriesRow
+	for rows.Next() {
+		var i GetWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventID,
+			&i.EventType,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.ResponseStatus,
+			&i.ResponseBody,
+			&i.Error,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeWebhookDeliveries = `-- name: PurgeWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries
+WHERE status <> 'pending' AND created_at < now() - $1::bigint * interval '1 second'
+`
+
+// Delete deliveries that are done and older than the retention period
+//
+//  DELETE FROM webhook_deliveries
+//  WHERE status <> 'pending' AND created_at < now() - $1::bigint * interval '1 second'
+func (q *Queries) PurgeWebhookDeliveries(ctx context.Context, retentionSeconds int64) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeWebhookDeliveries, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordWebhookAttempt = `-- name: RecordWebhookAttempt :exec
+UPDATE webhook_deliveries
+SET status = $1, attempts = attempts + 1, last_attempt_at = now(),
+    next_attempt_at = now() + $2::bigint * interval '1 second',
+    response_status = $3, response_body = $4, error = $5
+WHERE id = $6
+`
+
+type RecordWebhookAttemptParams struct {
+	Status         string      `db:"status" json:"status"`
+	RetryInSeconds int64       `db:"retry_in_seconds" json:"retryInSeconds"`
+	ResponseStatus *int32      `db:"response_status" json:"responseStatus"`
+	ResponseBody   *string     `db:"response_body" json:"responseBody"`
+	Error          *string     `db:"error" json:"error"`
+	ID             pgtype.UUID `db:"id" json:"id"`
+}
+
+// Record the outcome of a delivery attempt
+//
+//  UPDATE webhook_deliveries
+//  SET status = $1, attempts = attempts + 1, last_attempt_at = now(),
+//      next_attempt_at = now() + $2::bigint * interval '1 second',
+//      response_status = $3, response_body = $4, error = $5
+//  WHERE id = $6
+func (q *Queries) RecordWebhookAttempt(ctx context.Context, arg RecordWebhookAttemptParams) error {
+	_, err := q.db.Exec(ctx, recordWebhookAttempt,
+		arg.Status,
+		arg.RetryInSeconds,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.Error,
+		arg.ID,
+	)
+	return err
+}
+
+const recordWebhookFailure = `-- name: RecordWebhookFailure :one
+UPDATE webhooks
+SET failure_count = failure_count + 1,
+    enabled = enabled AND failure_count + 1 < $1::integer,
+    disabled_at = CASE WHEN enabled AND failure_count + 1 >= $1::integer THEN now() ELSE disabled_at END
+WHERE id = $2
+RETURNING enabled
+`
+
+type RecordWebhookFailureParams struct {
+	MaxFailures int32       `db:"max_failures" json:"maxFailures"`
+	ID          pgtype.UUID `db:"id" json:"id"`
+}
+
+// Count a failed delivery attempt, and disable the webhook once it failed too often in a row
+//
+//  UPDATE webhooks
+//  SET failure_count = failure_count + 1,
+//      enabled = enabled AND failure_count + 1 < $1::integer,
+//      disabled_at = CASE WHEN enabled AND failure_count + 1 >= $1::integer THEN now() ELSE disabled_at END
+//  WHERE id = $2
+//  RETURNING enabled
+func (q *Queries) RecordWebhookFailure(ctx context.Context, arg RecordWebhookFailureParams) (bool, error) {
+	row := q.db.QueryRow(ctx, recordWebhookFailure, arg.MaxFailures, arg.ID)
+	var enabled bool
+	err := row.Scan(&enabled)
+	return enabled, err
+}
+
+const redeliverWebhookDelivery = `-- name: RedeliverWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+SELECT d.webhook_id, d.event_id, d.event_type, d.payload FROM webhook_deliveries d
+WHERE d.id = $1 AND d.webhook_id = $2
+RETURNING id, webhook_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, response_body, error, created_at
+`
+
+type RedeliverWebhookDeliveryParams struct {
+	ID        pgtype.UUID `db:"id" json:"id"`
+	WebhookID pgtype.UUID `db:"webhook_id" json:"webhookId"`
+}
+
+type RedeliverWebhookDeliveryRow struct {
+	ID             pgtype.UUID      `db:"id" json:"id"`
+	WebhookID      pgtype.UUID      `db:"webhook_id" json:"webhookId"`
+	EventID        int64            `db:"event_id" json:"eventId"`
+	EventType      string           `db:"event_type" json:"eventType"`
+	Status         string           `db:"status" json:"status"`
+	Attempts       int32            `db:"attempts" json:"attempts"`
+	NextAttemptAt  pgtype.Timestamp `db:"next_attempt_at" json:"nextAttemptAt"`
+	LastAttemptAt  pgtype.Timestamp `db:"last_attempt_at" json:"lastAttemptAt"`
+	ResponseStatus *int32           `db:"response_status" json:"responseStatus"`
+	ResponseBody   *string          `db:"response_body" json:"responseBody"`
+	Error          *string          `db:"error" json:"error"`
+	CreatedAt      pgtype.Timestamp `db:"created_at" json:"createdAt"`
+}
+
+// Queue a delivery of the same event again
+//
+//  INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+//  SELECT d.webhook_id, d.event_id, d.event_type, d.payload FROM webhook_deliveries d
+//  WHERE d.id = $1 AND d.webhook_id = $2
+//  RETURNING id, webhook_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, response_body, error, created_at
+func (q *Queries) RedeliverWebhookDelivery(ctx context.Context, arg RedeliverWebhookDeliveryParams) (RedeliverWebhookDeliveryRow, error) {
+	row := q.db.QueryRow(ctx, redeliverWebhookDelivery, arg.ID, arg.WebhookID)
+	var i RedeliverWebhookDeliveryRow
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.EventID,
+		&i.EventType,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.Error,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const resetWebhookFailures = `-- name: ResetWebhookFailures :exec
+UPDATE webhooks SET failure_count = 0 WHERE id = $1 AND failure_count > 0
+`
+
+// Reset the consecutive failures of a webhook after a successful delivery
+//
+//  UPDATE webhooks SET failure_count = 0 WHERE id = $1 AND failure_count > 0
+func (q *Queries) ResetWebhookFailures(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, resetWebhookFailures, id)
+	return err
+}
+
+const updateWebhook = `-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = $1, description = $2, event_types = $3::text[], enabled = $4,
+    failure_count = CASE WHEN $4 AND NOT enabled THEN 0 ELSE failure_count END,
+    disabled_at = CASE WHEN $4 THEN NULL ELSE disabled_at END,
+    updated_at = now()
+WHERE id = $5
+RETURNING id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at
+`
+
+type UpdateWebhookParams struct {
+	Url         string      `db:"url" json:"url"`
+	Description string      `db:"description" json:"description"`
+	EventTypes  []string    `db:"event_types" json:"eventTypes"`
+	Enabled     bool        `db:"enabled" json:"enabled"`
+	ID          pgtype.UUID `db:"id" json:"id"`
+}
+
+type UpdateWebhookRow struct {
+	ID           pgtype.UUID      `db:"id" json:"id"`
+	Url          string           `db:"url" json:"url"`
+	Description  string           `db:"description" json:"description"`
+	EventTypes   []string         `db:"event_types" json:"eventTypes"`
+	Enabled      bool             `db:"enabled" json:"enabled"`
+	FailureCount int32            `db:"failure_count" json:"failureCount"`
+	DisabledAt   pgtype.Timestamp `db:"disabled_at" json:"disabledAt"`
+	CreatedAt    pgtype.Timestamp `db:"created_at" json:"createdAt"`
+	UpdatedAt    pgtype.Timestamp `db:"updated_at" json:"updatedAt"`
+}
+
+// Update a webhook. Enabling it again resets its failures.
+//
+//  UPDATE webhooks
+//  SET url = $1, description = $2, event_types = $3::text[], enabled = $4,
+//      failure_count = CASE WHEN $4 AND NOT enabled THEN 0 ELSE failure_count END,
+//      disabled_at = CASE WHEN $4 THEN NULL ELSE disabled_at END,
+//      updated_at = now()
+//  WHERE id = $5
+//  RETURNING id, url, description, event_types, enabled, failure_count, disabled_at, created_at, updated_at
+func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (UpdateWebhookRow, error) {
+	row := q.db.QueryRow(ctx, updateWebhook,
+		arg.Url,
+		arg.Description,
+		arg.EventTypes,
+		arg.Enabled,
+		arg.ID,
+	)
+	var i UpdateWebhookRow
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Description,
+		&i.EventTypes,
+		&i.Enabled,
+		&i.FailureCount,
+		&i.DisabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,92 @@
+package category
+
+import (
+	"net/http"
+
+	"github.com/OmprakashD20/refero-api/openapi"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// Docs describes the category routes for the OpenAPI document.
+var Docs = []openapi.Route{
+	{
+		Method:      http.MethodPost,
+		Path:        "/",
+		OperationID: "createCategory",
+		Summary:     "Create a category",
+		Body:        validator.CreateCategoryPayload{},
+		Responses:   []openapi.Response{{Status: http.StatusCreated}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/",
+		OperationID: "listCategories",
+		Summary:     "List the categories",
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: []types.CategoryDTO{}}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/:id",
+		OperationID: "getCategory",
+		Summary:     "Get a category",
+		Params:      validator.GetCategoryByIDParam{},
+		Headers:     []openapi.Header{ifNoneMatch},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Headers: []openapi.Header{etag}, Body: types.CategoryDTO{}},
+			{Status: http.StatusNotModified, Description: "The category still has the version in If-None-Match", Headers: []openapi.Header{etag}},
+		},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/:id/links",
+		OperationID: "listCategoryLinks",
+		Summary:     "List the links of a category",
+		Params:      validator.GetLinksForCategoryParams{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: []types.LinkDTO{}},
+			{Status: http.StatusNoContent, Description: "The category has no links"},
+		},
+	},
+	{
+		Method:      http.MethodPut,
+		Path:        "/:id",
+		OperationID: "updateCategory",
+		Summary:     "Replace a category",
+		Params:      validator.UpdateCategoryByIDParam{},
+		Headers:     []openapi.Header{ifMatch},
+		Body:        validator.UpdateCategoryPayload{},
+		Responses:   []openapi.Response{{Status: http.StatusOK}},
+	},
+	{
+		Method:      http.MethodPatch,
+		Path:        "/:id",
+		OperationID: "patchCategory",
+		Summary:     "Patch a category",
+		Description: "Applies a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) to the category, then validates the result as a full update.",
+		Params:      validator.PatchCategoryByIDParam{},
+		Headers:     []openapi.Header{ifMatch},
+		Body: openapi.Content{
+			validator.MergePatchMediaType: mergePatch,
+			"application/json":            mergePatch,
+			validator.JSONPatchMediaType:  openapi.JSONPatch,
+		},
+		Responses: []openapi.Response{{Status: http.StatusOK}},
+	},
+	{
+		Method:      http.MethodDelete,
+		Path:        "/:id",
+		OperationID: "deleteCategory",
+		Summary:     "Move a category to the trash",
+		Params:      validator.DeleteCategoryByIDParam{},
+		Headers:     []openapi.Header{ifMatch},
+		Responses:   []openapi.Response{{Status: http.StatusOK}},
+	},
+}
+
+var (
+	mergePatch  = &openapi.Schema{Type: "object", Description: "Fields of the category to replace, null removes optional ones"}
+	etag        = openapi.Header{Name: "ETag", Description: "Version of the category"}
+	ifNoneMatch = openapi.Header{Name: "If-None-Match", Description: "ETag of the cached category"}
+	ifMatch     = openapi.Header{Name: "If-Match", Description: "ETag the category must still have, for the write to succeed"}
+)
@@ -1,11 +1,13 @@
 package category
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	errs "github.com/OmprakashD20/refero-api/errors"
 	"github.com/OmprakashD20/refero-api/types"
+	"github.com/OmprakashD20/refero-api/utils"
 	validator "github.com/OmprakashD20/refero-api/validations"
 
 	"github.com/gin-gonic/gin"
@@ -27,6 +29,7 @@ func (s *CategoryService) SetupCategoryRoutes(api *gin.RouterGroup) {
 	api.GET("/:id/links", validator.ValidateParams[validator.GetLinksForCategoryParams](), s.GetLinksForCategoryHandler)
 
 	api.PUT("/:id", validator.ValidateParams[validator.UpdateCategoryByIDParam](), validator.ValidateBody[validator.UpdateCategoryPayload](), s.UpdateCategoryByIDHandler)
+	api.PATCH("/:id", validator.ValidateParams[validator.PatchCategoryByIDParam](), validator.ValidatePatchBody(), s.PatchCategoryByIDHandler)
 
 	api.DELETE("/:id", validator.ValidateParams[validator.DeleteCategoryByIDParam](), s.DeleteCategoryByIDHandler)
 }
@@ -40,25 +43,32 @@ func (s *CategoryService) CreateCategoryHandler(c *gin.Context) {
 		return
 	}
 
+	if err := s.CreateCategory(ctx, category); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, nil)
+}
+
+// CreateCategory creates the category, unless one with the same name exists.
+func (s *CategoryService) CreateCategory(ctx context.Context, category validator.CreateCategoryPayload) error {
 	// Check if the category exists
 	exists, err := s.store.CheckIfCategoryExistsByName(ctx, category.Name)
 	if err != nil {
-		c.Error(errs.InternalServerError(errs.WithCause(err)))
-		return
+		return errs.InternalServerError(errs.WithCause(err))
 	}
 	// If exists, return error
 	if exists {
-		c.Error(errs.Conflict(errs.ErrCategoryExists))
-		return
+		return errs.Conflict(errs.ErrCategoryExists)
 	}
 
 	// Create the category
 	if err := s.store.CreateCategory(ctx, category); err != nil {
-		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToCreateCategory), errs.WithCause(err)))
-		return
+		return errs.InternalServerError(errs.WithError(errs.ErrFailedToCreateCategory), errs.WithCause(err))
 	}
 
-	c.JSON(http.StatusCreated, nil)
+	return nil
 }
 
 func (s *CategoryService) GetCategoriesHandler(c *gin.Context) {
@@ -102,6 +112,14 @@ func (s *CategoryService) GetCategoryByIDHandler(c *gin.Context) {
 		return
 	}
 
+	// Skip the body if the client already has the current version
+	etag := utils.FormatETag(category.Version)
+	c.Header("ETag", etag)
+	if utils.MatchETag(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, category)
 }
 
@@ -120,15 +138,70 @@ func (s *CategoryService) UpdateCategoryByIDHandler(c *gin.Context) {
 		return
 	}
 
-	// Update the category
-	if err := s.store.UpdateCategoryByID(ctx, params.ID, category); err != nil {
-		// If category doesn't exists
-		if errors.Is(err, errs.ErrCategoryNotFound) {
-			c.Error(errs.NotFound(errs.ErrCategoryNotFound))
-			return
-		}
+	version, err := s.checkPrecondition(c, params.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToUpdateCategory), errs.WithCause(err)))
+	if err := s.UpdateCategory(ctx, params.ID, category, version); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func (s *CategoryService) PatchCategoryByIDHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, ok := validator.GetValidatedData[validator.PatchCategoryByIDParam](c, validator.ValidatedParamKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	patch, ok := validator.GetValidatedData[validator.PatchPayload](c, validator.ValidatedBodyKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	// Get the current state of the category
+	existing, err := s.store.GetCategoryByID(ctx, params.ID)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+	if existing == nil {
+		c.Error(errs.NotFound(errs.ErrCategoryNotFound))
+		return
+	}
+
+	// Only apply the patch on top of the version the client has seen
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && !utils.MatchStrongETag(ifMatch, utils.FormatETag(existing.Version)) {
+		c.Error(errs.PreconditionFailed(errs.ErrPreconditionFailed))
+		return
+	}
+
+	current := validator.UpdateCategoryPayload{
+		Name:        existing.Name,
+		Description: existing.Description,
+	}
+	if existing.ParentID != nil {
+		current.ParentId = *existing.ParentID
+	}
+
+	// Apply the patch and validate the result
+	category, err := validator.ApplyPatch(current, patch)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Update the category, unless it was modified since it was read
+	if err := s.UpdateCategory(ctx, params.ID, category, &existing.Version); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -144,15 +217,14 @@ func (s *CategoryService) DeleteCategoryByIDHandler(c *gin.Context) {
 		return
 	}
 
-	// Delete the category
-	if err := s.store.DeleteCategoryByID(ctx, params.ID); err != nil {
-		// If category doesn't exists
-		if errors.Is(err, errs.ErrCategoryNotFound) {
-			c.Error(errs.NotFound(errs.ErrCategoryNotFound))
-			return
-		}
+	version, err := s.checkPrecondition(c, params.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToDeleteCategory), errs.WithCause(err)))
+	if err := s.DeleteCategory(ctx, params.ID, version); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -192,3 +264,64 @@ func (s *CategoryService) GetLinksForCategoryHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, links)
 }
+
+// UpdateCategory replaces the category details.
+// If version is set, the update only succeeds while the category is still at that version.
+func (s *CategoryService) UpdateCategory(ctx context.Context, id string, category validator.UpdateCategoryPayload, version *int32) error {
+	if err := s.store.UpdateCategoryByID(ctx, id, category, version); err != nil {
+		// If category doesn't exists
+		if errors.Is(err, errs.ErrCategoryNotFound) {
+			return errs.NotFound(errs.ErrCategoryNotFound)
+		}
+		// If category was modified concurrently
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return errs.PreconditionFailed(errs.ErrPreconditionFailed)
+		}
+
+		return errs.InternalServerError(errs.WithError(errs.ErrFailedToUpdateCategory), errs.WithCause(err))
+	}
+
+	return nil
+}
+
+// DeleteCategory moves the category to the trash.
+// If version is set, it only succeeds while the category is still at that version.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id string, version *int32) error {
+	if err := s.store.DeleteCategoryByID(ctx, id, version); err != nil {
+		// If category doesn't exists
+		if errors.Is(err, errs.ErrCategoryNotFound) {
+			return errs.NotFound(errs.ErrCategoryNotFound)
+		}
+		// If category was modified concurrently
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return errs.PreconditionFailed(errs.ErrPreconditionFailed)
+		}
+
+		return errs.InternalServerError(errs.WithError(errs.ErrFailedToDeleteCategory), errs.WithCause(err))
+	}
+
+	return nil
+}
+
+// checkPrecondition evaluates the If-Match header against the current version of the category.
+// It returns the version the write has to match, or nil if the request is unconditional.
+func (s *CategoryService) checkPrecondition(c *gin.Context, id string) (*int32, error) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		return nil, nil
+	}
+
+	category, err := s.store.GetCategoryByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if category == nil {
+		return nil, errs.NotFound(errs.ErrCategoryNotFound)
+	}
+
+	if !utils.MatchStrongETag(ifMatch, utils.FormatETag(category.Version)) {
+		return nil, errs.PreconditionFailed(errs.ErrPreconditionFailed)
+	}
+
+	return &category.Version, nil
+}
@@ -0,0 +1,210 @@
+package category
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/OmprakashD20/refero-api/apitest"
+	"github.com/OmprakashD20/refero-api/memstore"
+	"github.com/OmprakashD20/refero-api/types"
+	"github.com/OmprakashD20/refero-api/utils"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// newTestServer serves the category routes over an in-memory store.
+func newTestServer(t *testing.T) (*memstore.Store, http.Handler) {
+	t.Helper()
+
+	store := memstore.New()
+	handler := apitest.Handler(t, "/api/v1/category", func(api *gin.RouterGroup) {
+		NewService(store).SetupCategoryRoutes(api)
+	})
+
+	return store, handler
+}
+
+func createCategory(t *testing.T, handler http.Handler, payload validator.CreateCategoryPayload) types.CategoryDTO {
+	t.Helper()
+
+	if res := apitest.Serve(t, handler, http.MethodPost, "/api/v1/category/", payload); res.Code != http.StatusCreated {
+		t.Fatalf("creating %s: got status %d: %s", payload.Name, res.Code, res.Body)
+	}
+
+	for _, category := range apitest.Decode[[]types.CategoryDTO](t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/", nil)) {
+		if category.Name == payload.Name {
+			return category
+		}
+	}
+	t.Fatalf("created category %s is not listed", payload.Name)
+	return types.CategoryDTO{}
+}
+
+func TestCreateCategory(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	languages := createCategory(t, handler, validator.CreateCategoryPayload{Name: "languages"})
+	golang := createCategory(t, handler, validator.CreateCategoryPayload{Name: "golang", ParentId: languages.ID})
+	if golang.ParentID == nil || *golang.ParentID != languages.ID {
+		t.Errorf("got parent %v, want %s", golang.ParentID, languages.ID)
+	}
+
+	res := apitest.Serve(t, handler, http.MethodPost, "/api/v1/category/", validator.CreateCategoryPayload{Name: "golang"})
+	apitest.ExpectProblem(t, res, http.StatusConflict, "category_exists")
+
+	res = apitest.Serve(t, handler, http.MethodPost, "/api/v1/category/", validator.CreateCategoryPayload{Name: "rustlang", ParentId: apitest.UnknownID})
+	problem := apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "invalid_reference")
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "parentId" {
+		t.Errorf("got field errors %+v, want parentId", problem.Errors)
+	}
+
+	res = apitest.Serve(t, handler, http.MethodPost, "/api/v1/category/", validator.CreateCategoryPayload{Name: "go"})
+	apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "validation_failed")
+}
+
+func TestGetCategoryByID(t *testing.T) {
+	_, handler := newTestServer(t)
+	category := createCategory(t, handler, validator.CreateCategoryPayload{Name: "golang"})
+
+	res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+category.ID, nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+	if got := apitest.Decode[types.CategoryDTO](t, res); got.ID != category.ID || got.Name != "golang" || got.Version != 1 {
+		t.Errorf("got %+v, want %+v", got, category)
+	}
+
+	etag := res.Header().Get("ETag")
+	if res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+category.ID, nil, "If-None-Match", etag); res.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: got status %d, want %d", res.Code, http.StatusNotModified)
+	}
+
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+apitest.UnknownID, nil), http.StatusNotFound, "category_not_found")
+}
+
+func TestGetLinksForCategory(t *testing.T) {
+	store, handler := newTestServer(t)
+	ctx := context.Background()
+	category := createCategory(t, handler, validator.CreateCategoryPayload{Name: "golang"})
+
+	// An empty category has no content
+	if res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+category.ID+"/links", nil); res.Code != http.StatusNoContent {
+		t.Errorf("got status %d, want %d", res.Code, http.StatusNoContent)
+	}
+
+	description := "The Go programming language"
+	linkID, err := store.CreateLink(ctx, validator.CreateLinkPayload{Title: "Go", URL: "https://go.dev", Description: &description}, "go", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddLinkToCategory(ctx, []types.LinkCategoryDTO{{LinkID: *linkID, CategoryID: category.ID}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+category.ID+"/links", nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+	if links := apitest.Decode[[]types.LinkDTO](t, res); len(links) != 1 || links[0].ID != *linkID {
+		t.Errorf("got links %+v, want %s", links, *linkID)
+	}
+
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+apitest.UnknownID+"/links", nil), http.StatusNotFound, "category_not_found")
+}
+
+func TestUpdateCategory(t *testing.T) {
+	_, handler := newTestServer(t)
+	category := createCategory(t, handler, validator.CreateCategoryPayload{Name: "golang"})
+	createCategory(t, handler, validator.CreateCategoryPayload{Name: "rustlang"})
+
+	update := validator.UpdateCategoryPayload{Name: "go-lang"}
+	res := apitest.Serve(t, handler, http.MethodPut, "/api/v1/category/"+category.ID, update, "If-Match", utils.FormatETag(2))
+	apitest.ExpectProblem(t, res, http.StatusPreconditionFailed, "precondition_failed")
+
+	// If-Match uses the strong comparison, so a weak tag of the current version doesn't match either
+	res = apitest.Serve(t, handler, http.MethodPut, "/api/v1/category/"+category.ID, update, "If-Match", "W/"+utils.FormatETag(1))
+	apitest.ExpectProblem(t, res, http.StatusPreconditionFailed, "precondition_failed")
+
+	res = apitest.Serve(t, handler, http.MethodPut, "/api/v1/category/"+category.ID, update, "If-Match", utils.FormatETag(1))
+	if res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+	got := apitest.Decode[types.CategoryDTO](t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+category.ID, nil))
+	if got.Name != "go-lang" || got.Version != 2 {
+		t.Errorf("got %+v after the update", got)
+	}
+
+	// Names are unique
+	res = apitest.Serve(t, handler, http.MethodPut, "/api/v1/category/"+category.ID, validator.UpdateCategoryPayload{Name: "rustlang"})
+	apitest.ExpectProblem(t, res, http.StatusConflict, "duplicate_value")
+
+	res = apitest.Serve(t, handler, http.MethodPut, "/api/v1/category/"+apitest.UnknownID, update)
+	apitest.ExpectProblem(t, res, http.StatusNotFound, "category_not_found")
+}
+
+func TestPatchCategory(t *testing.T) {
+	_, handler := newTestServer(t)
+	parent := createCategory(t, handler, validator.CreateCategoryPayload{Name: "languages"})
+	category := createCategory(t, handler, validator.CreateCategoryPayload{Name: "golang"})
+
+	patch := func(body string, header ...string) *httptest.ResponseRecorder {
+		return apitest.Serve(t, handler, http.MethodPatch, "/api/v1/category/"+category.ID, body, append([]string{"Content-Type", validator.MergePatchMediaType}, header...)...)
+	}
+
+	if res := patch(`{"parentId": "` + parent.ID + `", "description": "The Go programming language"}`); res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+	got := apitest.Decode[types.CategoryDTO](t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+category.ID, nil))
+	if got.Name != "golang" || got.ParentID == nil || *got.ParentID != parent.ID || got.Description == nil || got.Version != 2 {
+		t.Errorf("got %+v after the patch", got)
+	}
+
+	apitest.ExpectProblem(t, patch(`{"name": "go"}`), http.StatusUnprocessableEntity, "validation_failed")
+	apitest.ExpectProblem(t, patch(`{"name": "golang"}`, "If-Match", utils.FormatETag(1)), http.StatusPreconditionFailed, "precondition_failed")
+
+	t.Run("json patch", func(t *testing.T) {
+		jsonPatch := func(body string) *httptest.ResponseRecorder {
+			return apitest.Serve(t, handler, http.MethodPatch, "/api/v1/category/"+category.ID, body, "Content-Type", validator.JSONPatchMediaType)
+		}
+
+		res := jsonPatch(`[{"op": "test", "path": "/name", "value": "golang"}, {"op": "replace", "path": "/name", "value": "golang-dev"}, {"op": "remove", "path": "/parentId"}]`)
+		if res.Code != http.StatusOK {
+			t.Fatalf("got status %d: %s", res.Code, res.Body)
+		}
+		got := apitest.Decode[types.CategoryDTO](t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+category.ID, nil))
+		if got.Name != "golang-dev" || got.ParentID != nil || got.Version != 3 {
+			t.Errorf("got %+v after the patch", got)
+		}
+
+		// The merged category is validated, not the operations
+		apitest.ExpectProblem(t, jsonPatch(`[{"op": "replace", "path": "/name", "value": "go"}]`), http.StatusUnprocessableEntity, "validation_failed")
+		apitest.ExpectProblem(t, jsonPatch(`[{"op": "test", "path": "/name", "value": "rustlang"}]`), http.StatusUnprocessableEntity, "invalid_patch")
+		apitest.ExpectProblem(t, jsonPatch(`[{"op": "remove", "path": "/missing"}]`), http.StatusUnprocessableEntity, "invalid_patch")
+	})
+}
+
+func TestDeleteCategory(t *testing.T) {
+	_, handler := newTestServer(t)
+	parent := createCategory(t, handler, validator.CreateCategoryPayload{Name: "languages"})
+	child := createCategory(t, handler, validator.CreateCategoryPayload{Name: "golang", ParentId: parent.ID})
+
+	if res := apitest.Serve(t, handler, http.MethodDelete, "/api/v1/category/"+parent.ID, nil, "If-Match", utils.FormatETag(1)); res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+
+	// Subcategories are trashed along with their parent
+	for _, id := range []string{parent.ID, child.ID} {
+		apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/category/"+id, nil), http.StatusNotFound, "category_not_found")
+	}
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodDelete, "/api/v1/category/"+parent.ID, nil), http.StatusNotFound, "category_not_found")
+
+	// Categories can't be added to a trashed parent, as purging it would delete them
+	res := apitest.Serve(t, handler, http.MethodPost, "/api/v1/category/", validator.CreateCategoryPayload{Name: "rustlang", ParentId: parent.ID})
+	apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "invalid_reference")
+
+	// The name can be used again once the category is in the trash
+	createCategory(t, handler, validator.CreateCategoryPayload{Name: "languages"})
+}
@@ -2,10 +2,15 @@ package category
 
 import (
 	"context"
+	"errors"
+	"slices"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/OmprakashD20/refero-api/database"
 	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
 	"github.com/OmprakashD20/refero-api/repository"
 	"github.com/OmprakashD20/refero-api/types"
 	"github.com/OmprakashD20/refero-api/utils"
@@ -15,10 +20,23 @@ import (
 type Store struct {
 	conn *pgxpool.Pool
 	db   *repository.Queries
+	read *repository.Queries // Read-only queries that tolerate replication lag
+	txns *database.Store
 }
 
 func NewStore(conn *pgxpool.Pool) *Store {
-	return &Store{conn: conn, db: repository.New(conn)}
+	db := repository.New(conn)
+	return &Store{conn: conn, db: db, read: db, txns: database.NewTransactionStore(conn)}
+}
+
+// WithReplica sends the category list and the batch loaders of categories, subcategories
+// and their links to the replica pool. Lookups by ID and name stay on the primary,
+// as they back the existence and version checks of writes.
+func (s *Store) WithReplica(replica *pgxpool.Pool) *Store {
+	if replica != nil {
+		s.read = repository.New(replica)
+	}
+	return s
 }
 
 func (s *Store) CheckIfCategoryExistsByName(ctx context.Context, name string) (bool, error) {
@@ -48,15 +66,43 @@ func (s *Store) CreateCategory(ctx context.Context, category validator.CreateCat
 		ParentID:    utils.ToPgUUID(category.ParentId),
 	}
 
-	if categoryID, err := s.db.CreateCategory(ctx, args); !categoryID.Valid {
-		return err
+	// Mutations publish their event in the same transaction, so it is only delivered once they commit
+	return s.txns.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+		if err := lockParent(ctx, q, category.ParentId); err != nil {
+			return err
+		}
+
+		categoryID, err := q.CreateCategory(ctx, args)
+		if !categoryID.Valid {
+			return errs.TranslatePgError(err)
+		}
+
+		id := categoryID.String()
+		return events.Publish(ctx, q, events.CategoryCreated, events.CategoryChanged{ID: id}, withParent(id, category.ParentId))
+	})
+}
+
+// lockParent checks that the parent of a category is not in the trash, and keeps it from being trashed
+// until the transaction commits. Purging a trashed parent would delete its subcategories with it.
+func lockParent(ctx context.Context, q *repository.Queries, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+
+	_, err := q.LockLiveCategory(ctx, utils.ToPgUUID(parentID))
+	if err != nil {
+		// Parent category doesn't exists in the database, or is in the trash
+		if _, err := errs.IsErrNoRows(err, false); err != nil {
+			return err
+		}
+		return errs.InvalidReference("parentId")
 	}
 
 	return nil
 }
 
 func (s *Store) GetAllCategories(ctx context.Context) ([]types.CategoryDTO, error) {
-	data, err := s.db.GetAllCategories(ctx)
+	data, err := s.read.GetAllCategories(ctx)
 	if err != nil {
 		return errs.IsErrNoRows[[]types.CategoryDTO](err, nil)
 	}
@@ -68,6 +114,7 @@ func (s *Store) GetAllCategories(ctx context.Context) ([]types.CategoryDTO, erro
 			Name:        category.Name,
 			Description: category.Description,
 			ParentID:    utils.PgUUIDToStringPtr(category.ParentID),
+			Version:     category.Version,
 			CreatedAt:   &category.CreatedAt.Time,
 			UpdatedAt:   &category.UpdatedAt.Time,
 		}
@@ -84,6 +131,23 @@ func (s *Store) GetCategoryByID(ctx context.Context, id string) (*types.Category
 		return errs.IsErrNoRows[*types.CategoryDTO](err, nil)
 	}
 
+	category := &types.CategoryDTO{
+		ID:          data.ID.String(),
+		Name:        data.Name,
+		Description: data.Description,
+		ParentID:    utils.PgUUIDToStringPtr(data.ParentID),
+		Version:     data.Version,
+	}
+
+	return category, nil
+}
+
+func (s *Store) GetCategoryByName(ctx context.Context, name string) (*types.CategoryDTO, error) {
+	data, err := s.db.GetCategoryByName(ctx, name)
+	if err != nil {
+		return errs.IsErrNoRows[*types.CategoryDTO](err, nil)
+	}
+
 	category := &types.CategoryDTO{
 		ID:          data.ID.String(),
 		Name:        data.Name,
@@ -94,36 +158,68 @@ func (s *Store) GetCategoryByID(ctx context.Context, id string) (*types.Category
 	return category, nil
 }
 
-func (s *Store) UpdateCategoryByID(ctx context.Context, id string, category validator.UpdateCategoryPayload) error {
+func (s *Store) UpdateCategoryByID(ctx context.Context, id string, category validator.UpdateCategoryPayload, version *int32) error {
 	args := repository.UpdateCategoryParams{
 		ID:          utils.ToPgUUID(id),
 		Name:        category.Name,
 		Description: category.Description,
 		ParentID:    utils.ToPgUUID(category.ParentId),
+		Version:     version,
 	}
 
-	rows, err := s.db.UpdateCategory(ctx, args)
-	if rows == 0 {
-		// [Category or Parent Category] does not exists in the database
-		return errs.ErrCategoryNotFound
-	}
+	return s.txns.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+		if err := lockParent(ctx, q, category.ParentId); err != nil {
+			return err
+		}
 
-	return err
-}
+		oldParentID, err := q.UpdateCategory(ctx, args)
+		if err != nil {
+			return categoryMutationError(err, version)
+		}
 
-func (s *Store) DeleteCategoryByID(ctx context.Context, id string) error {
-	rows, err := s.db.DeleteCategory(ctx, utils.ToPgUUID(id))
+		// The event is relevant to the parent a category is moved away from as well
+		categoryIDs := withParent(id, category.ParentId)
+		if oldParentID.Valid && !slices.Contains(categoryIDs, oldParentID.String()) {
+			categoryIDs = append(categoryIDs, oldParentID.String())
+		}
 
-	if rows == 0 {
-		// Category does not exists in the database
-		return errs.ErrCategoryNotFound
+		return events.Publish(ctx, q, events.CategoryUpdated, events.CategoryChanged{ID: id}, categoryIDs)
+	})
+}
+
+func (s *Store) DeleteCategoryByID(ctx context.Context, id string, version *int32) error {
+	args := repository.DeleteCategoryParams{
+		ID:      utils.ToPgUUID(id),
+		Version: version,
 	}
 
-	return err
+	return s.txns.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+		trashed, err := q.DeleteCategory(ctx, args)
+		if err != nil {
+			return errs.TranslatePgError(err)
+		}
+		if len(trashed) == 0 {
+			return categoryMutationError(pgx.ErrNoRows, version)
+		}
+
+		// Subcategories are trashed along with the category, and each of them is published as deleted
+		for _, category := range trashed {
+			categoryID := category.ID.String()
+			categoryIDs := []string{categoryID}
+			if category.ParentID.Valid {
+				categoryIDs = append(categoryIDs, category.ParentID.String())
+			}
+
+			if err := events.Publish(ctx, q, events.CategoryDeleted, events.CategoryChanged{ID: categoryID}, categoryIDs); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (s *Store) GetLinksForCategory(ctx context.Context, id string) ([]types.LinkDTO, error) {
-	data, err := s.db.GetLinksForCategory(ctx, utils.ToPgUUID(id))
+	data, err := s.read.GetLinksForCategory(ctx, utils.ToPgUUID(id))
 	if err != nil {
 		return errs.IsErrNoRows[[]types.LinkDTO](err, nil)
 	}
@@ -143,3 +239,100 @@ func (s *Store) GetLinksForCategory(ctx context.Context, id string) ([]types.Lin
 
 	return links, nil
 }
+
+// GetCategoriesByIDs returns the categories with the given IDs, in no particular order.
+// IDs of categories that don't exist are left out.
+func (s *Store) GetCategoriesByIDs(ctx context.Context, ids []string) ([]types.CategoryDTO, error) {
+	data, err := s.read.GetCategoriesByIDs(ctx, utils.ToPgUUIDs(ids))
+	if err != nil {
+		return errs.IsErrNoRows[[]types.CategoryDTO](err, nil)
+	}
+
+	categories := make([]types.CategoryDTO, len(data))
+	for i, category := range data {
+		categories[i] = types.CategoryDTO{
+			ID:          category.ID.String(),
+			Name:        category.Name,
+			Description: category.Description,
+			ParentID:    utils.PgUUIDToStringPtr(category.ParentID),
+			Version:     category.Version,
+			CreatedAt:   &category.CreatedAt.Time,
+			UpdatedAt:   &category.UpdatedAt.Time,
+		}
+	}
+
+	return categories, nil
+}
+
+// GetSubcategoriesOfCategories returns the subcategories of each of the given categories, keyed by parent ID.
+func (s *Store) GetSubcategoriesOfCategories(ctx context.Context, ids []string) (map[string][]types.CategoryDTO, error) {
+	data, err := s.read.GetSubcategoriesOfCategories(ctx, utils.ToPgUUIDs(ids))
+	if err != nil {
+		return errs.IsErrNoRows[map[string][]types.CategoryDTO](err, nil)
+	}
+
+	subcategories := make(map[string][]types.CategoryDTO, len(ids))
+	for _, category := range data {
+		parentID := category.ParentID.String()
+		subcategories[parentID] = append(subcategories[parentID], types.CategoryDTO{
+			ID:          category.ID.String(),
+			Name:        category.Name,
+			Description: category.Description,
+			ParentID:    utils.PgUUIDToStringPtr(category.ParentID),
+			Version:     category.Version,
+			CreatedAt:   &category.CreatedAt.Time,
+			UpdatedAt:   &category.UpdatedAt.Time,
+		})
+	}
+
+	return subcategories, nil
+}
+
+// GetLinksForCategories returns the links in each of the given categories, keyed by category ID.
+func (s *Store) GetLinksForCategories(ctx context.Context, ids []string) (map[string][]types.LinkDTO, error) {
+	data, err := s.read.GetLinksForCategories(ctx, utils.ToPgUUIDs(ids))
+	if err != nil {
+		return errs.IsErrNoRows[map[string][]types.LinkDTO](err, nil)
+	}
+
+	links := make(map[string][]types.LinkDTO, len(ids))
+	for _, link := range data {
+		categoryID := link.CategoryID.String()
+		links[categoryID] = append(links[categoryID], types.LinkDTO{
+			ID:          link.ID.String(),
+			Url:         link.Url,
+			Title:       link.Title,
+			Description: link.Description,
+			ShortUrl:    link.ShortUrl,
+			Version:     link.Version,
+			CreatedAt:   &link.CreatedAt.Time,
+			UpdatedAt:   &link.UpdatedAt.Time,
+		})
+	}
+
+	return links, nil
+}
+
+// categoryMutationError converts the error of a conditional write, which finds no row
+// if the category doesn't exist or isn't at the given version.
+func categoryMutationError(err error, version *int32) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Category was modified since the given version
+		if version != nil {
+			return errs.ErrPreconditionFailed
+		}
+		// Category does not exists in the database
+		return errs.ErrCategoryNotFound
+	}
+
+	return errs.TranslatePgError(err)
+}
+
+// withParent lists the categories an event about a category is relevant to,
+// so that subscribers of the parent category learn about its subcategories.
+func withParent(id, parentID string) []string {
+	if parentID == "" {
+		return []string{id}
+	}
+	return []string{id, parentID}
+}
@@ -0,0 +1,252 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/OmprakashD20/refero-api/apitest"
+	"github.com/OmprakashD20/refero-api/database/dbtest"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/repository"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func insertCategory(t *testing.T, store *Store, name, parentID string) string {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := store.CreateCategory(ctx, validator.CreateCategoryPayload{Name: name, ParentId: parentID}); err != nil {
+		t.Fatal(err)
+	}
+	category, err := store.GetCategoryByName(ctx, name)
+	if err != nil || category == nil {
+		t.Fatalf("GetCategoryByName(%q): got %v, %v", name, category, err)
+	}
+	return category.ID
+}
+
+func TestStoreCreateCategory(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	languages := insertCategory(t, store, "languages", "")
+	golang := insertCategory(t, store, "golang", languages)
+
+	category, err := store.GetCategoryByID(ctx, golang)
+	if err != nil || category == nil {
+		t.Fatalf("GetCategoryByID: got %v, %v", category, err)
+	}
+	if category.Name != "golang" || category.ParentID == nil || *category.ParentID != languages || category.Version != 1 {
+		t.Errorf("GetCategoryByID: got %+v", category)
+	}
+
+	for name, want := range map[string]bool{"golang": true, "rustlang": false} {
+		if exists, err := store.CheckIfCategoryExistsByName(ctx, name); err != nil || exists != want {
+			t.Errorf("CheckIfCategoryExistsByName(%q): got %v, %v, want %v", name, exists, err, want)
+		}
+	}
+	for id, want := range map[string]bool{golang: true, apitest.UnknownID: false} {
+		if exists, err := store.CheckIfCategoryExistsByID(ctx, id); err != nil || exists != want {
+			t.Errorf("CheckIfCategoryExistsByID(%q): got %v, %v, want %v", id, exists, err, want)
+		}
+	}
+
+	err = store.CreateCategory(ctx, validator.CreateCategoryPayload{Name: "golang"})
+	if code := apitest.ErrorCode(err); code != "duplicate_value" {
+		t.Errorf("CreateCategory with a duplicate name: got %v, want duplicate_value", err)
+	}
+	err = store.CreateCategory(ctx, validator.CreateCategoryPayload{Name: "rustlang", ParentId: apitest.UnknownID})
+	if code := apitest.ErrorCode(err); code != "invalid_reference" {
+		t.Errorf("CreateCategory with a missing parent: got %v, want invalid_reference", err)
+	}
+
+	// Events about subcategories are relevant to their parent as well
+	rows, err := repository.New(pool).ListEventsAfter(ctx, repository.ListEventsAfterParams{MaxEvents: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1].Type != events.CategoryCreated || len(rows[1].CategoryIds) != 2 {
+		t.Errorf("published %+v, want two %s events", rows, events.CategoryCreated)
+	}
+}
+
+func TestStoreListCategories(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	languages := insertCategory(t, store, "languages", "")
+	golang := insertCategory(t, store, "golang", languages)
+	rust := insertCategory(t, store, "rustlang", languages)
+	trashed := insertCategory(t, store, "trashed", "")
+	if err := store.DeleteCategoryByID(ctx, trashed, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := store.GetAllCategories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("GetAllCategories: got %d categories, want 3", len(all))
+	}
+
+	byIDs, err := store.GetCategoriesByIDs(ctx, []string{golang, trashed, apitest.UnknownID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byIDs) != 1 || byIDs[0].ID != golang {
+		t.Errorf("GetCategoriesByIDs: got %+v", byIDs)
+	}
+
+	subcategories, err := store.GetSubcategoriesOfCategories(ctx, []string{languages, golang})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, category := range subcategories[languages] {
+		got = append(got, category.ID)
+	}
+	slices.Sort(got)
+	want := []string{golang, rust}
+	slices.Sort(want)
+	if len(subcategories) != 1 || !slices.Equal(got, want) {
+		t.Errorf("GetSubcategoriesOfCategories: got %+v", subcategories)
+	}
+
+	link := dbtest.InsertLink(t, pool, "https://go.dev", golang)
+	dbtest.InsertLink(t, pool, "https://www.rust-lang.org", rust)
+
+	links, err := store.GetLinksForCategory(ctx, golang)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links[0].ID != link {
+		t.Errorf("GetLinksForCategory: got %+v", links)
+	}
+
+	byCategory, err := store.GetLinksForCategories(ctx, []string{golang, rust, languages})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byCategory) != 2 || len(byCategory[golang]) != 1 || len(byCategory[rust]) != 1 {
+		t.Errorf("GetLinksForCategories: got %+v", byCategory)
+	}
+}
+
+func TestStoreUpdateCategory(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	languages := insertCategory(t, store, "languages", "")
+	golang := insertCategory(t, store, "golang", "")
+	insertCategory(t, store, "rustlang", "")
+	stale, current := int32(2), int32(1)
+
+	update := validator.UpdateCategoryPayload{Name: "go-lang", ParentId: languages}
+	if err := store.UpdateCategoryByID(ctx, golang, update, &stale); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Errorf("UpdateCategoryByID at a stale version: got %v, want %v", err, errs.ErrPreconditionFailed)
+	}
+	if err := store.UpdateCategoryByID(ctx, golang, update, &current); err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := store.GetCategoryByID(ctx, golang)
+	if err != nil || category == nil || category.Name != "go-lang" || category.ParentID == nil || category.Version != 2 {
+		t.Errorf("GetCategoryByID after the update: got %+v, %v", category, err)
+	}
+
+	err = store.UpdateCategoryByID(ctx, golang, validator.UpdateCategoryPayload{Name: "rustlang"}, nil)
+	if code := apitest.ErrorCode(err); code != "duplicate_value" {
+		t.Errorf("UpdateCategoryByID with a duplicate name: got %v, want duplicate_value", err)
+	}
+	err = store.UpdateCategoryByID(ctx, golang, validator.UpdateCategoryPayload{Name: "golang", ParentId: apitest.UnknownID}, nil)
+	if code := apitest.ErrorCode(err); code != "invalid_reference" {
+		t.Errorf("UpdateCategoryByID with a missing parent: got %v, want invalid_reference", err)
+	}
+	err = store.UpdateCategoryByID(ctx, apitest.UnknownID, update, nil)
+	if !errors.Is(err, errs.ErrCategoryNotFound) {
+		t.Errorf("UpdateCategoryByID of a missing category: got %v, want %v", err, errs.ErrCategoryNotFound)
+	}
+
+	// Moving a category is relevant to the parent it is moved away from as well
+	if err := store.UpdateCategoryByID(ctx, golang, validator.UpdateCategoryPayload{Name: "go-lang"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := repository.New(pool).ListEventsAfter(ctx, repository.ListEventsAfterParams{MaxEvents: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var categories []string
+	for _, id := range rows[len(rows)-1].CategoryIds {
+		categories = append(categories, id.String())
+	}
+	slices.Sort(categories)
+	wantCategories := []string{golang, languages}
+	slices.Sort(wantCategories)
+	if !slices.Equal(categories, wantCategories) {
+		t.Errorf("the move was published to categories %v, want %v", categories, wantCategories)
+	}
+
+	want := []string{events.CategoryCreated, events.CategoryCreated, events.CategoryCreated, events.CategoryUpdated, events.CategoryUpdated}
+	if got := dbtest.PublishedEvents(t, pool); !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+func TestStoreDeleteCategory(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	languages := insertCategory(t, store, "languages", "")
+	golang := insertCategory(t, store, "golang", languages)
+	generics := insertCategory(t, store, "generics", golang)
+	stale := int32(2)
+
+	if err := store.DeleteCategoryByID(ctx, languages, &stale); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Errorf("DeleteCategoryByID at a stale version: got %v, want %v", err, errs.ErrPreconditionFailed)
+	}
+	if err := store.DeleteCategoryByID(ctx, languages, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Subcategories are trashed along with their parent
+	for _, id := range []string{languages, golang, generics} {
+		if category, err := store.GetCategoryByID(ctx, id); err != nil || category != nil {
+			t.Errorf("GetCategoryByID of a trashed category: got %+v, %v", category, err)
+		}
+	}
+	if err := store.DeleteCategoryByID(ctx, languages, nil); !errors.Is(err, errs.ErrCategoryNotFound) {
+		t.Errorf("DeleteCategoryByID of a trashed category: got %v, want %v", err, errs.ErrCategoryNotFound)
+	}
+
+	// Categories can't be moved under a trashed parent, as purging it would delete them
+	tools := insertCategory(t, store, "tools", "")
+	err := store.CreateCategory(ctx, validator.CreateCategoryPayload{Name: "rustlang", ParentId: languages})
+	if code := apitest.ErrorCode(err); code != "invalid_reference" {
+		t.Errorf("CreateCategory under a trashed parent: got %v, want invalid_reference", err)
+	}
+	err = store.UpdateCategoryByID(ctx, tools, validator.UpdateCategoryPayload{Name: "tools", ParentId: golang}, nil)
+	if code := apitest.ErrorCode(err); code != "invalid_reference" {
+		t.Errorf("UpdateCategoryByID under a trashed parent: got %v, want invalid_reference", err)
+	}
+
+	// The name of a trashed category can be used again
+	insertCategory(t, store, "languages", "")
+
+	// Every trashed category is published as deleted
+	want := []string{
+		events.CategoryCreated, events.CategoryCreated, events.CategoryCreated,
+		events.CategoryDeleted, events.CategoryDeleted, events.CategoryDeleted,
+		events.CategoryCreated, events.CategoryCreated,
+	}
+	if got := dbtest.PublishedEvents(t, pool); !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
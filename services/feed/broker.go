@@ -0,0 +1,209 @@
+package feed
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/metrics"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+const (
+	// replayPageSize bounds the events read from the outbox at once
+	replayPageSize = 500
+	// purgeInterval is how often events past their retention are deleted
+	purgeInterval = time.Hour
+)
+
+// Broker fans the events received by the listener out to the subscribers of this instance.
+// Every subscriber has a bounded queue, and is dropped once it is full rather than holding
+// up the others. Clients of a dropped subscription resume from the outbox.
+type Broker struct {
+	store      types.EventStore
+	bufferSize int
+	retention  time.Duration
+
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	lastID int64 // Latest event delivered to the subscribers
+	synced bool  // Whether lastID is known, before that a resync would replay the whole outbox
+	closed bool
+}
+
+func NewBroker(store types.EventStore, bufferSize int, retention time.Duration) *Broker {
+	return &Broker{
+		store:      store,
+		bufferSize: bufferSize,
+		retention:  retention,
+		subs:       make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives the events of the categories it was created for, or all events.
+type Subscription struct {
+	events     chan events.Envelope
+	categories map[string]struct{}
+	done       chan struct{}
+	err        error
+}
+
+// Events delivers the events in the order they were published.
+func (s *Subscription) Events() <-chan events.Envelope {
+	return s.events
+}
+
+// Done is closed once the broker dropped the subscription, see Err for the reason.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns why the subscription was dropped, once Done is closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Matches reports whether the event is relevant to the subscription.
+func (s *Subscription) Matches(event events.Envelope) bool {
+	if len(s.categories) == 0 {
+		return true
+	}
+
+	for _, id := range event.Categories {
+		if _, ok := s.categories[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe registers a subscription for the events of the given categories, or all events if there are none.
+func (b *Broker) Subscribe(categoryIDs []string) (*Subscription, error) {
+	sub := &Subscription{
+		events:     make(chan events.Envelope, b.bufferSize),
+		categories: make(map[string]struct{}, len(categoryIDs)),
+		done:       make(chan struct{}),
+	}
+	for _, id := range categoryIDs {
+		sub.categories[id] = struct{}{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, errs.ErrFeedUnavailable
+	}
+	b.subs[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// Unsubscribe removes the subscription, if it was not dropped already.
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subs, sub)
+}
+
+// Handle delivers an event received by the listener. Events that were delivered already,
+// e.g. by a resync racing with the notification, are skipped.
+func (b *Broker) Handle(ctx context.Context, event events.Envelope) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if event.ID <= b.lastID {
+		return
+	}
+	b.lastID, b.synced = event.ID, true
+
+	for sub := range b.subs {
+		if !sub.Matches(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			metrics.FeedDroppedSubscribers.Inc()
+			b.drop(sub, errs.ErrSubscriberTooSlow)
+		}
+	}
+}
+
+// Resync delivers the events published while the listener was disconnected.
+func (b *Broker) Resync(ctx context.Context) {
+	for {
+		b.mu.Lock()
+		after, synced := b.lastID, b.synced
+		b.mu.Unlock()
+
+		if !synced {
+			return
+		}
+
+		missed, err := b.store.ListEventsAfter(ctx, after, replayPageSize)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to resync the change feed", "error", err)
+			return
+		}
+
+		for _, event := range missed {
+			b.Handle(ctx, event)
+		}
+		if len(missed) < replayPageSize {
+			return
+		}
+	}
+}
+
+// Close drops every subscription and refuses new ones, so that streams end when the server shuts down.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.drop(sub, errs.ErrFeedUnavailable)
+	}
+}
+
+// drop removes the subscription and signals its reason, with the lock held.
+func (b *Broker) drop(sub *Subscription, err error) {
+	delete(b.subs, sub)
+	sub.err = err
+	close(sub.done)
+}
+
+// Run starts delivering events after the latest one in the outbox, and purges
+// the events past their retention until the context is cancelled.
+func (b *Broker) Run(ctx context.Context) {
+	if latest, err := b.store.GetLatestEventID(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to get the latest event", "error", err)
+	} else {
+		b.mu.Lock()
+		b.lastID, b.synced = max(b.lastID, latest), true
+		b.mu.Unlock()
+	}
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if purged, err := b.store.PurgeEvents(ctx, b.retention); err != nil {
+			slog.ErrorContext(ctx, "failed to purge events", "error", err)
+		} else if purged > 0 {
+			slog.InfoContext(ctx, "purged events", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+)
+
+// fakeEventStore is an outbox held in memory.
+type fakeEventStore struct {
+	mu     sync.Mutex
+	events []events.Envelope
+}
+
+// publish appends an event relevant to the given categories and returns it.
+func (s *fakeEventStore) publish(categories ...string) events.Envelope {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := events.Envelope{ID: int64(len(s.events) + 1), Type: events.LinkUpdated, Categories: categories, Data: []byte(`{}`)}
+	s.events = append(s.events, event)
+	return event
+}
+
+func (s *fakeEventStore) ListEventsAfter(ctx context.Context, afterID int64, limit int32) ([]events.Envelope, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []events.Envelope
+	for _, event := range s.events {
+		if event.ID > afterID && len(list) < int(limit) {
+			list = append(list, event)
+		}
+	}
+	return list, nil
+}
+
+func (s *fakeEventStore) GetLatestEventID(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.events)), nil
+}
+
+func (s *fakeEventStore) PurgeEvents(ctx context.Context, retention time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func subscribe(t *testing.T, broker *Broker, categoryIDs ...string) *Subscription {
+	t.Helper()
+
+	sub, err := broker.Subscribe(categoryIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sub
+}
+
+// received drains the events queued for the subscription and returns their IDs.
+func received(sub *Subscription) []int64 {
+	var ids []int64
+	for {
+		select {
+		case event := <-sub.Events():
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func expectDropped(t *testing.T, sub *Subscription, want error) {
+	t.Helper()
+
+	select {
+	case <-sub.Done():
+		if !errors.Is(sub.Err(), want) {
+			t.Errorf("subscription dropped with %v, want %v", sub.Err(), want)
+		}
+	default:
+		t.Errorf("subscription was not dropped, want %v", want)
+	}
+}
+
+func TestBrokerBackpressure(t *testing.T) {
+	store := &fakeEventStore{}
+	broker := NewBroker(store, 2, time.Hour)
+	ctx := context.Background()
+	slow := subscribe(t, broker)
+	fast := subscribe(t, broker)
+
+	var delivered []int64
+	for range 3 {
+		broker.Handle(ctx, store.publish())
+		delivered = append(delivered, received(fast)...)
+	}
+
+	// The full queue drops the slow subscriber, which keeps the events queued before
+	expectDropped(t, slow, errs.ErrSubscriberTooSlow)
+	if got := received(slow); !slices.Equal(got, []int64{1, 2}) {
+		t.Errorf("slow subscriber: got events %v, want [1 2]", got)
+	}
+
+	// The others keep receiving events
+	broker.Handle(ctx, store.publish())
+	delivered = append(delivered, received(fast)...)
+	if !slices.Equal(delivered, []int64{1, 2, 3, 4}) {
+		t.Errorf("fast subscriber: got events %v, want [1 2 3 4]", delivered)
+	}
+	select {
+	case <-fast.Done():
+		t.Errorf("fast subscriber was dropped: %v", fast.Err())
+	default:
+	}
+}
+
+func TestBrokerCategories(t *testing.T) {
+	store := &fakeEventStore{}
+	broker := NewBroker(store, 10, time.Hour)
+	ctx := context.Background()
+	all := subscribe(t, broker)
+	golang := subscribe(t, broker, "golang")
+	both := subscribe(t, broker, "golang", "rustlang")
+
+	broker.Handle(ctx, store.publish("rustlang"))
+	broker.Handle(ctx, store.publish("tools", "golang"))
+	broker.Handle(ctx, store.publish())
+
+	tests := []struct {
+		name string
+		sub  *Subscription
+		want []int64
+	}{
+		{"all", all, []int64{1, 2, 3}},
+		{"one category", golang, []int64{2}},
+		{"several categories", both, []int64{1, 2}},
+	}
+	for _, tt := range tests {
+		if got := received(tt.sub); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got events %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBrokerResync(t *testing.T) {
+	store := &fakeEventStore{}
+	broker := NewBroker(store, 2*replayPageSize, time.Hour)
+	ctx := context.Background()
+	sub := subscribe(t, broker)
+
+	// Until the latest delivered event is known, a resync would replay the whole outbox
+	store.publish()
+	broker.Resync(ctx)
+	if got := received(sub); len(got) != 0 {
+		t.Errorf("resync before any event: got events %v", got)
+	}
+
+	broker.Handle(ctx, store.events[0])
+
+	// Events missed while the listener was disconnected are delivered a page at a time
+	for range replayPageSize + 2 {
+		store.publish()
+	}
+	broker.Resync(ctx)
+
+	got := received(sub)
+	if len(got) != replayPageSize+3 || got[0] != 1 || got[len(got)-1] != replayPageSize+3 {
+		t.Fatalf("resync: got %d events from %v to %v, want 1 to %d", len(got), got[0], got[len(got)-1], replayPageSize+3)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i] != got[i-1]+1 {
+			t.Fatalf("resync: event %d followed event %d", got[i], got[i-1])
+		}
+	}
+
+	// Notifications of the events delivered by the resync arrive late, and are skipped
+	broker.Handle(ctx, store.events[3])
+	broker.Handle(ctx, store.events[len(store.events)-1])
+	if got := received(sub); len(got) != 0 {
+		t.Errorf("duplicate notifications: got events %v", got)
+	}
+
+	next := store.publish()
+	broker.Handle(ctx, next)
+	if got := received(sub); !slices.Equal(got, []int64{next.ID}) {
+		t.Errorf("after the resync: got events %v, want [%d]", got, next.ID)
+	}
+}
+
+func TestBrokerRun(t *testing.T) {
+	store := &fakeEventStore{}
+	broker := NewBroker(store, 10, time.Hour)
+	sub := subscribe(t, broker)
+	store.publish()
+	store.publish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	broker.Run(ctx)
+
+	// Events in the outbox before the broker started are not delivered
+	store.publish()
+	broker.Resync(context.Background())
+	if got := received(sub); !slices.Equal(got, []int64{3}) {
+		t.Errorf("resync after Run: got events %v, want [3]", got)
+	}
+}
+
+func TestBrokerClose(t *testing.T) {
+	broker := NewBroker(&fakeEventStore{}, 10, time.Hour)
+	sub := subscribe(t, broker)
+	unsubscribed := subscribe(t, broker)
+	broker.Unsubscribe(unsubscribed)
+
+	broker.Close()
+	expectDropped(t, sub, errs.ErrFeedUnavailable)
+
+	select {
+	case <-unsubscribed.Done():
+		t.Error("Close dropped a subscription that was removed before")
+	default:
+	}
+
+	if _, err := broker.Subscribe(nil); !errors.Is(err, errs.ErrFeedUnavailable) {
+		t.Errorf("Subscribe after Close: got %v, want %v", err, errs.ErrFeedUnavailable)
+	}
+}
@@ -0,0 +1,39 @@
+package feed
+
+import (
+	"net/http"
+
+	"github.com/OmprakashD20/refero-api/openapi"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// Docs describes the change feed routes for the OpenAPI document.
+var Docs = []openapi.Route{
+	{
+		Method:      http.MethodGet,
+		Path:        "",
+		OperationID: "streamEvents",
+		Summary:     "Stream changes as Server-Sent Events",
+		Description: "Each event is named after its type and carries the event as JSON data. " +
+			"Clients resuming with Last-Event-ID first receive the events they missed, for as long as the outbox retains them.",
+		Query:   validator.EventsQuery{},
+		Headers: []openapi.Header{{Name: "Last-Event-ID", Description: "ID of the last event received, takes precedence over lastEventId"}},
+		Responses: []openapi.Response{{
+			Status: http.StatusOK,
+			Body:   openapi.Content{"text/event-stream": types.EventDTO{}},
+		}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/ws",
+		OperationID: "streamEventsWebSocket",
+		Summary:     "Stream changes over a WebSocket",
+		Description: "Each message is an event as JSON. Clients resume with the lastEventId query parameter.",
+		Query:       validator.EventsQuery{},
+		Responses: []openapi.Response{{
+			Status:      http.StatusSwitchingProtocols,
+			Description: "The connection is upgraded to a WebSocket",
+		}},
+	},
+}
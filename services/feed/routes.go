@@ -0,0 +1,186 @@
+package feed
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/metrics"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+type FeedService struct {
+	broker    *Broker
+	store     types.EventStore
+	heartbeat time.Duration
+	// originPatterns lists the hosts allowed to open a WebSocket from a browser, besides the API's own
+	originPatterns []string
+}
+
+func NewService(broker *Broker, store types.EventStore, heartbeat time.Duration, originPatterns []string) *FeedService {
+	return &FeedService{broker, store, heartbeat, originPatterns}
+}
+
+func (s *FeedService) SetupFeedRoutes(api *gin.RouterGroup, websocket bool) {
+	api.GET("", validator.ValidateQuery[validator.EventsQuery](), s.StreamEventsHandler)
+
+	if websocket {
+		api.GET("/ws", validator.ValidateQuery[validator.EventsQuery](), s.WebSocketHandler)
+	}
+}
+
+// StreamEventsHandler streams the change feed as Server-Sent Events. Clients resuming with
+// Last-Event-ID first receive the events they missed from the outbox.
+func (s *FeedService) StreamEventsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, ok := validator.GetValidatedData[validator.EventsQuery](c, validator.ValidatedQueryKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	lastEventID := query.LastEventID
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			c.Error(errs.BadRequest(errs.ErrInvalidLastEventID))
+			return
+		}
+		lastEventID = &id
+	}
+
+	sub, err := s.broker.Subscribe(query.CategoryIDs)
+	if err != nil {
+		c.Error(errs.ServiceUnavailable(err))
+		return
+	}
+	defer s.broker.Unsubscribe(sub)
+
+	gauge := metrics.FeedSubscribers.WithLabelValues("sse")
+	gauge.Inc()
+	defer gauge.Dec()
+
+	// The stream outlives the write timeout of the server
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Keep nginx from buffering the stream
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	send := func(event events.Envelope) error {
+		c.Render(-1, sse.Event{
+			Id:    strconv.FormatInt(event.ID, 10),
+			Event: event.Type,
+			Data:  toDTO(event),
+		})
+		c.Writer.Flush()
+		return ctx.Err()
+	}
+
+	heartbeat := func() error {
+		if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	// Once the subscription is dropped the stream ends, and the client reconnects with the last event ID
+	if err := s.stream(ctx, sub, lastEventID, send, heartbeat); err != nil && ctx.Err() == nil {
+		slog.InfoContext(ctx, "change feed stream ended", "error", err)
+	}
+}
+
+// stream sends the events after lastEventID from the outbox, if set, followed by the events
+// of the subscription, until the client goes away or the subscription is dropped.
+func (s *FeedService) stream(ctx context.Context, sub *Subscription, lastEventID *int64, send func(events.Envelope) error, heartbeat func() error) error {
+	// Events of the subscription up to here have been sent from the outbox
+	var sent int64
+	if lastEventID != nil {
+		var err error
+		if sent, err = s.replay(ctx, sub, *lastEventID, send); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.Done():
+			// Send what was queued before the subscription was dropped, so the client resumes from there
+			for {
+				select {
+				case event := <-sub.Events():
+					if event.ID <= sent {
+						continue
+					}
+					if err := send(event); err != nil {
+						return err
+					}
+				default:
+					return sub.Err()
+				}
+			}
+		case <-ticker.C:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		case event := <-sub.Events():
+			if event.ID <= sent {
+				continue
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replay sends the events of the subscription published after the given ID,
+// and returns the ID it caught up to.
+func (s *FeedService) replay(ctx context.Context, sub *Subscription, after int64, send func(events.Envelope) error) (int64, error) {
+	for {
+		missed, err := s.store.ListEventsAfter(ctx, after, replayPageSize)
+		if err != nil {
+			return after, err
+		}
+
+		for _, event := range missed {
+			after = event.ID
+			if !sub.Matches(event) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return after, err
+			}
+		}
+
+		if len(missed) < replayPageSize {
+			return after, nil
+		}
+	}
+}
+
+func toDTO(event events.Envelope) types.EventDTO {
+	return types.EventDTO{
+		ID:         event.ID,
+		Type:       event.Type,
+		Categories: event.Categories,
+		Data:       event.Data,
+	}
+}
@@ -0,0 +1,141 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+)
+
+func TestStreamReplay(t *testing.T) {
+	store := &fakeEventStore{}
+	broker := NewBroker(store, 10, time.Hour)
+	service := NewService(broker, store, time.Hour, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, category := range []string{"golang", "rustlang", "golang", "golang"} {
+		store.publish(category)
+	}
+	sub := subscribe(t, broker, "golang")
+
+	// The notifications of the last two events reach the subscription before the replay reads them
+	broker.Handle(ctx, store.events[2])
+	broker.Handle(ctx, store.events[3])
+
+	var sent []int64
+	send := func(event events.Envelope) error {
+		sent = append(sent, event.ID)
+
+		switch event.ID {
+		case 4:
+			// Published live once the replay caught up
+			broker.Handle(ctx, store.publish("rustlang"))
+			broker.Handle(ctx, store.publish("golang"))
+		case 6:
+			cancel()
+		}
+		return ctx.Err()
+	}
+	heartbeat := func() error {
+		t.Error("heartbeat sent")
+		return nil
+	}
+
+	lastEventID := int64(1)
+	if err := service.stream(ctx, sub, &lastEventID, send, heartbeat); !errors.Is(err, context.Canceled) {
+		t.Errorf("stream: got %v, want %v", err, context.Canceled)
+	}
+
+	// The replay sends the events of the category after the last one, and the queued duplicates are skipped
+	if want := []int64{3, 4, 6}; !slices.Equal(sent, want) {
+		t.Errorf("got events %v, want %v", sent, want)
+	}
+}
+
+func TestStreamLive(t *testing.T) {
+	store := &fakeEventStore{}
+	broker := NewBroker(store, 10, time.Hour)
+	service := NewService(broker, store, time.Hour, nil)
+	ctx := context.Background()
+
+	// Without a last event ID the outbox is not read
+	store.publish()
+	sub := subscribe(t, broker)
+	broker.Handle(ctx, store.publish())
+	broker.Handle(ctx, store.publish())
+	broker.Close()
+
+	var sent []int64
+	send := func(event events.Envelope) error {
+		sent = append(sent, event.ID)
+		return nil
+	}
+
+	// Events queued before the subscription was dropped are sent before the stream ends
+	if err := service.stream(ctx, sub, nil, send, func() error { return nil }); !errors.Is(err, errs.ErrFeedUnavailable) {
+		t.Errorf("stream: got %v, want %v", err, errs.ErrFeedUnavailable)
+	}
+	if want := []int64{2, 3}; !slices.Equal(sent, want) {
+		t.Errorf("got events %v, want %v", sent, want)
+	}
+}
+
+func TestStreamSendError(t *testing.T) {
+	store := &fakeEventStore{}
+	broker := NewBroker(store, 10, time.Hour)
+	service := NewService(broker, store, time.Hour, nil)
+	ctx := context.Background()
+	store.publish()
+	store.publish()
+	sub := subscribe(t, broker)
+
+	// A client that went away ends the replay
+	failed := errors.New("connection reset")
+	var sent int
+	send := func(event events.Envelope) error {
+		sent++
+		return failed
+	}
+
+	lastEventID := int64(0)
+	if err := service.stream(ctx, sub, &lastEventID, send, func() error { return nil }); !errors.Is(err, failed) {
+		t.Errorf("stream: got %v, want %v", err, failed)
+	}
+	if sent != 1 {
+		t.Errorf("sent %d events after the send failed", sent)
+	}
+}
+
+func TestStreamHeartbeat(t *testing.T) {
+	store := &fakeEventStore{}
+	broker := NewBroker(store, 10, time.Hour)
+	service := NewService(broker, store, time.Millisecond, nil)
+	sub := subscribe(t, broker)
+
+	failed := errors.New("connection reset")
+	var heartbeats int
+	heartbeat := func() error {
+		heartbeats++
+		if heartbeats == 3 {
+			return failed
+		}
+		return nil
+	}
+	send := func(event events.Envelope) error {
+		t.Errorf("sent event %d", event.ID)
+		return nil
+	}
+
+	// Heartbeats are sent while there are no events, until one fails
+	if err := service.stream(context.Background(), sub, nil, send, heartbeat); !errors.Is(err, failed) {
+		t.Errorf("stream: got %v, want %v", err, failed)
+	}
+	if heartbeats != 3 {
+		t.Errorf("got %d heartbeats, want 3", heartbeats)
+	}
+}
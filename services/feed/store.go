@@ -0,0 +1,56 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/repository"
+)
+
+type Store struct {
+	conn *pgxpool.Pool
+	db   *repository.Queries
+}
+
+// NewStore creates a store for the events outbox. It always reads from the primary,
+// as clients resume right after the events they were notified of.
+func NewStore(conn *pgxpool.Pool) *Store {
+	return &Store{conn: conn, db: repository.New(conn)}
+}
+
+func (s *Store) ListEventsAfter(ctx context.Context, afterID int64, limit int32) ([]events.Envelope, error) {
+	rows, err := s.db.ListEventsAfter(ctx, repository.ListEventsAfterParams{AfterID: afterID, MaxEvents: limit})
+	if err != nil {
+		return errs.IsErrNoRows[[]events.Envelope](err, nil)
+	}
+
+	list := make([]events.Envelope, len(rows))
+	for i, row := range rows {
+		categories := make([]string, len(row.CategoryIds))
+		for j, id := range row.CategoryIds {
+			categories[j] = id.String()
+		}
+
+		list[i] = events.Envelope{
+			ID:         row.ID,
+			Type:       row.Type,
+			Source:     row.Source,
+			Categories: categories,
+			Data:       row.Data,
+		}
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetLatestEventID(ctx context.Context) (int64, error) {
+	return s.db.GetLatestEventID(ctx)
+}
+
+func (s *Store) PurgeEvents(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.db.PurgeEvents(ctx, int64(retention.Seconds()))
+}
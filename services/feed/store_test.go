@@ -0,0 +1,78 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/OmprakashD20/refero-api/database"
+	"github.com/OmprakashD20/refero-api/database/dbtest"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/repository"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func TestStoreEvents(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	latest, err := store.GetLatestEventID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != 0 {
+		t.Errorf("GetLatestEventID of an empty outbox: got %d", latest)
+	}
+
+	category := "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f"
+	txns := database.NewTransactionStore(pool)
+	for _, eventType := range []string{events.LinkCreated, events.LinkUpdated, events.LinkDeleted} {
+		err := txns.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+			return events.Publish(ctx, q, eventType, events.LinkChanged{ID: "1", ShortURL: "go"}, []string{category})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, err := store.ListEventsAfter(ctx, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Type != events.LinkCreated || list[1].Type != events.LinkUpdated {
+		t.Fatalf("ListEventsAfter: got %+v", list)
+	}
+	var data events.LinkChanged
+	if err := json.Unmarshal(list[0].Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(list[0].Categories, []string{category}) || data.ShortURL != "go" {
+		t.Errorf("ListEventsAfter: got %+v", list[0])
+	}
+
+	rest, err := store.ListEventsAfter(ctx, list[1].ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 || rest[0].Type != events.LinkDeleted {
+		t.Errorf("ListEventsAfter the second event: got %+v", rest)
+	}
+
+	if latest, err := store.GetLatestEventID(ctx); err != nil || latest != rest[0].ID {
+		t.Errorf("GetLatestEventID: got %d, %v, want %d", latest, err, rest[0].ID)
+	}
+
+	dbtest.Exec(t, pool, `UPDATE events SET created_at = now() - interval '2 days' WHERE id = $1`, list[0].ID)
+	purged, err := store.PurgeEvents(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeEvents purged %d events, want 1", purged)
+	}
+}
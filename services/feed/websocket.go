@@ -0,0 +1,76 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gin-gonic/gin"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/metrics"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// writeTimeout bounds how long a single WebSocket message may take to send
+const writeTimeout = 10 * time.Second
+
+// WebSocketHandler streams the change feed as JSON messages over a WebSocket.
+// Clients resume with the lastEventId query parameter, as the browser API can't set headers.
+func (s *FeedService) WebSocketHandler(c *gin.Context) {
+	query, ok := validator.GetValidatedData[validator.EventsQuery](c, validator.ValidatedQueryKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	sub, err := s.broker.Subscribe(query.CategoryIDs)
+	if err != nil {
+		c.Error(errs.ServiceUnavailable(err))
+		return
+	}
+	defer s.broker.Unsubscribe(sub)
+
+	conn, err := websocket.Accept(c.Writer, c.Request, &websocket.AcceptOptions{OriginPatterns: s.originPatterns})
+	if err != nil {
+		// Accept has written the response already
+		slog.WarnContext(c.Request.Context(), "websocket handshake failed", "error", err)
+		return
+	}
+	defer conn.CloseNow()
+
+	gauge := metrics.FeedSubscribers.WithLabelValues("websocket")
+	gauge.Inc()
+	defer gauge.Dec()
+
+	// The feed is one way, reading only handles control frames and notices the client closing
+	ctx := conn.CloseRead(c.Request.Context())
+
+	send := func(event events.Envelope) error {
+		ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+		defer cancel()
+
+		return wsjson.Write(ctx, conn, toDTO(event))
+	}
+
+	heartbeat := func() error {
+		ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+		defer cancel()
+
+		return conn.Ping(ctx)
+	}
+
+	err = s.stream(ctx, sub, query.LastEventID, send, heartbeat)
+	switch {
+	case errors.Is(err, errs.ErrSubscriberTooSlow):
+		conn.Close(websocket.StatusTryAgainLater, err.Error())
+	case errors.Is(err, errs.ErrFeedUnavailable):
+		conn.Close(websocket.StatusGoingAway, err.Error())
+	case err != nil && ctx.Err() == nil:
+		conn.Close(websocket.StatusInternalError, "")
+	}
+}
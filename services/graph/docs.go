@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"net/http"
+
+	"github.com/OmprakashD20/refero-api/openapi"
+)
+
+// graphQLRequest is a GraphQL request, sent as JSON with POST or as query parameters with GET.
+type graphQLRequest struct {
+	Query         string         `json:"query" form:"query" binding:"required"`
+	OperationName string         `json:"operationName,omitempty" form:"operationName"`
+	Variables     map[string]any `json:"variables,omitempty" form:"variables"`
+}
+
+// graphQLResponse is a GraphQL response, which can carry both data and errors.
+type graphQLResponse struct {
+	Data   any   `json:"data"`
+	Errors []any `json:"errors,omitempty"`
+}
+
+// Docs describes the GraphQL routes for the OpenAPI document. The schema itself is available through introspection.
+var Docs = []openapi.Route{
+	{
+		Method:      http.MethodGet,
+		Path:        "",
+		OperationID: "queryGraphQL",
+		Summary:     "Run a GraphQL query",
+		Description: "Mutations are only accepted over POST. Variables are sent as JSON.",
+		Query:       graphQLRequest{},
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: graphQLResponse{}}},
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "",
+		OperationID: "postGraphQL",
+		Summary:     "Run a GraphQL query or mutation",
+		Body:        graphQLRequest{},
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: graphQLResponse{}}},
+	},
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+)
+
+// presentError reports errors the way the REST API does, with the code, status and invalid fields
+// of the problem details in the extensions. The cause of server errors is logged but never returned.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var httpErr *errs.HTTPError
+	if !errors.As(err, &httpErr) {
+		var parseErr *gqlerror.Error
+		if errors.As(err, &parseErr) && parseErr.Err == nil {
+			// Syntax and validation errors of the query itself
+			return gqlErr
+		}
+
+		slog.ErrorContext(ctx, "graphql resolver failed", "path", gqlErr.Path.String(), "error", err)
+		httpErr = errs.InternalServerError()
+	}
+
+	httpErr = httpErr.Resolve()
+	if httpErr.Cause != nil && httpErr.StatusCode >= 500 {
+		slog.ErrorContext(ctx, "graphql resolver failed", "path", gqlErr.Path.String(), "error", httpErr.ErrorMsg, "cause", httpErr.Cause)
+	}
+
+	gqlErr.Message = httpErr.ErrorMsg
+	gqlErr.Extensions = map[string]any{
+		"code":   httpErr.ErrorCode(),
+		"status": httpErr.StatusCode,
+	}
+	if len(httpErr.Fields) > 0 {
+		gqlErr.Extensions["fields"] = httpErr.Fields
+	}
+
+	return gqlErr
+}
+
+// recoverPanic turns a panic in a resolver into an internal server error.
+func recoverPanic(ctx context.Context, p any) error {
+	slog.ErrorContext(ctx, "graphql resolver panicked", "panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+	return errs.InternalServerError()
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// listWeight is how many items a list field is assumed to hold when estimating the complexity of a query.
+const listWeight = 10
+
+// complexity weighs the fields returning lists, so that nesting them costs what it does to resolve.
+func complexity() ComplexityRoot {
+	var c ComplexityRoot
+
+	list := func(childComplexity int) int { return listWeight * (1 + childComplexity) }
+	c.Query.Links = list
+	c.Query.Categories = list
+	c.Link.Categories = list
+	c.Category.Children = list
+	c.Category.Links = list
+
+	return c
+}
+
+// depthLimit rejects operations selecting fields nested deeper than max.
+// Introspection fields are not counted, since tools ask for deeply nested types.
+type depthLimit struct {
+	max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = depthLimit{}
+
+func (depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d depthLimit) Validate(graphql.ExecutableSchema) error {
+	if d.max <= 0 {
+		return errors.New("depth limit must be positive")
+	}
+	return nil
+}
+
+func (d depthLimit) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	depth := selectionDepth(oc.Operation.SelectionSet, make(map[string]int))
+	if depth > d.max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.max)
+		errcode.Set(err, "DEPTH_LIMIT_EXCEEDED")
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns how deeply the fields of the selection set are nested.
+// The depth of each fragment is remembered, so that spreading a fragment many times is cheap.
+func selectionDepth(set ast.SelectionSet, fragments map[string]int) int {
+	depth := 0
+	for _, selection := range set {
+		var d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(s.SelectionSet, fragments)
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet, fragments)
+		case *ast.FragmentSpread:
+			known, ok := fragments[s.Name]
+			if !ok && s.Definition != nil {
+				// Validation rejects cyclic fragments, so this terminates
+				known = selectionDepth(s.Definition.SelectionSet, fragments)
+				fragments[s.Name] = known
+			}
+			d = known
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmprakashD20/refero-api/dataloader"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+const (
+	loaderWait     = 2 * time.Millisecond // Long enough for sibling fields to queue their keys
+	loaderMaxBatch = 500
+)
+
+type loadersKey struct{}
+
+// Loaders batch the lookups made while resolving a single request,
+// so that resolving a list of N objects costs one query per field instead of N.
+type Loaders struct {
+	Link              *dataloader.Loader[string, *types.LinkDTO]
+	Category          *dataloader.Loader[string, *types.CategoryDTO]
+	CategoriesForLink *dataloader.Loader[string, []types.CategoryDTO]
+	Subcategories     *dataloader.Loader[string, []types.CategoryDTO]
+	LinksForCategory  *dataloader.Loader[string, []types.LinkDTO]
+}
+
+func newLoaders(linkStore types.LinkStore, categoryStore types.CategoryStore) *Loaders {
+	return &Loaders{
+		Link: dataloader.New(func(ctx context.Context, ids []string) (map[string]*types.LinkDTO, error) {
+			links, err := linkStore.GetLinksByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return byID(links, func(link *types.LinkDTO) string { return link.ID }), nil
+		}, loaderWait, loaderMaxBatch),
+		Category: dataloader.New(func(ctx context.Context, ids []string) (map[string]*types.CategoryDTO, error) {
+			categories, err := categoryStore.GetCategoriesByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			return byID(categories, func(category *types.CategoryDTO) string { return category.ID }), nil
+		}, loaderWait, loaderMaxBatch),
+		CategoriesForLink: dataloader.New(linkStore.GetCategoriesForLinks, loaderWait, loaderMaxBatch),
+		Subcategories:     dataloader.New(categoryStore.GetSubcategoriesOfCategories, loaderWait, loaderMaxBatch),
+		LinksForCategory:  dataloader.New(categoryStore.GetLinksForCategories, loaderWait, loaderMaxBatch),
+	}
+}
+
+func withLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+func loadersFrom(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
+
+// byID indexes the items by their ID.
+func byID[T any](items []T, id func(*T) string) map[string]*T {
+	index := make(map[string]*T, len(items))
+	for i := range items {
+		index[id(&items[i])] = &items[i]
+	}
+	return index
+}
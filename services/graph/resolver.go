@@ -0,0 +1,47 @@
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate --config gqlgen.yml
+
+import (
+	"context"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/services/links"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+// Resolver resolves GraphQL operations with the same services and stores as the REST handlers,
+// so both APIs apply the same rules and publish the same events.
+type Resolver struct {
+	links         *links.LinkService
+	categories    *category.CategoryService
+	linkStore     types.LinkStore
+	categoryStore types.CategoryStore
+}
+
+// getLink reads the link back from the primary after a mutation.
+func (r *Resolver) getLink(ctx context.Context, id string) (*types.LinkDTO, error) {
+	link, err := r.linkStore.GetLinkByID(ctx, id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if link == nil {
+		// Deleted in the meantime
+		return nil, errs.NotFound(errs.ErrLinkNotFound)
+	}
+	return link, nil
+}
+
+// getCategory reads the category back from the primary after a mutation.
+func (r *Resolver) getCategory(ctx context.Context, id string) (*types.CategoryDTO, error) {
+	category, err := r.categoryStore.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if category == nil {
+		// Deleted in the meantime
+		return nil, errs.NotFound(errs.ErrCategoryNotFound)
+	}
+	return category, nil
+}
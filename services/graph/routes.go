@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/services/links"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+// Options limit the queries the endpoint accepts.
+type Options struct {
+	MaxDepth      int
+	MaxComplexity int
+	Introspection bool
+}
+
+type GraphQLService struct {
+	server        *handler.Server
+	linkStore     types.LinkStore
+	categoryStore types.CategoryStore
+}
+
+func NewService(linkService *links.LinkService, categoryService *category.CategoryService, linkStore types.LinkStore, categoryStore types.CategoryStore, opts Options) *GraphQLService {
+	schema := NewExecutableSchema(Config{
+		Resolvers: &Resolver{
+			links:         linkService,
+			categories:    categoryService,
+			linkStore:     linkStore,
+			categoryStore: categoryStore,
+		},
+		Complexity: complexity(),
+	})
+
+	server := handler.New(schema)
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	server.SetErrorPresenter(presentError)
+	server.SetRecoverFunc(recoverPanic)
+
+	if opts.Introspection {
+		server.Use(extension.Introspection{})
+	}
+	server.Use(depthLimit{max: opts.MaxDepth})
+	server.Use(extension.FixedComplexityLimit(opts.MaxComplexity))
+
+	return &GraphQLService{server, linkStore, categoryStore}
+}
+
+// SetupGraphQLRoutes serves queries over GET and POST, and mutations over POST only.
+// Clients sending queries with GET are rate limited with the read policy rather than the write policy.
+func (s *GraphQLService) SetupGraphQLRoutes(api *gin.RouterGroup) {
+	api.GET("", s.GraphQLHandler)
+	api.POST("", s.GraphQLHandler)
+}
+
+func (s *GraphQLService) GraphQLHandler(c *gin.Context) {
+	// Loaders cache what they load, so they must not outlive the request
+	ctx := withLoaders(c.Request.Context(), newLoaders(s.linkStore, s.categoryStore))
+
+	s.server.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/OmprakashD20/refero-api/apitest"
+	"github.com/OmprakashD20/refero-api/memstore"
+	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/services/links"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+type response struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// newTestServer serves the GraphQL endpoint over an in-memory store, with a depth limit of 3.
+func newTestServer(t *testing.T) http.Handler {
+	t.Helper()
+
+	store := memstore.New()
+	if err := store.CreateCategory(context.Background(), validator.CreateCategoryPayload{Name: "Go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	return apitest.Handler(t, "/graphql", func(api *gin.RouterGroup) {
+		NewService(links.NewService(store, store), category.NewService(store), store, store, Options{
+			MaxDepth:      3,
+			MaxComplexity: 5000,
+			Introspection: true,
+		}).SetupGraphQLRoutes(api)
+	})
+}
+
+func query(t *testing.T, handler http.Handler, q string) (int, response) {
+	t.Helper()
+
+	res := apitest.Serve(t, handler, http.MethodPost, "/graphql", map[string]string{"query": q})
+	return res.Code, apitest.Decode[response](t, res)
+}
+
+func TestDepthLimit(t *testing.T) {
+	handler := newTestServer(t)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"nested fields", `{ categories { children { children { name } } } }`},
+		{"fragments", `{ categories { ...nested } } fragment nested on Category { parent { links { title } } }`},
+		{"inline fragments", `{ links { ... on Link { categories { children { id } } } } }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, res := query(t, handler, tt.query)
+			if len(res.Errors) != 1 || res.Data != nil {
+				t.Fatalf("got %+v, want a single error", res)
+			}
+			if code := res.Errors[0].Extensions["code"]; code != "DEPTH_LIMIT_EXCEEDED" {
+				t.Errorf("got error code %v, want DEPTH_LIMIT_EXCEEDED", code)
+			}
+			if want := "operation has depth 4, which exceeds the limit of 3"; res.Errors[0].Message != want {
+				t.Errorf("got message %q, want %q", res.Errors[0].Message, want)
+			}
+		})
+	}
+
+	// Queries at the limit are run, and introspection fields are not counted
+	for _, q := range []string{
+		`{ categories { children { name } } }`,
+		`{ categories { __typename children { __typename name } } }`,
+		`{ __schema { types { fields { type { ofType { name } } } } } }`,
+	} {
+		status, res := query(t, handler, q)
+		if status != http.StatusOK || len(res.Errors) != 0 || res.Data == nil {
+			t.Errorf("%s: got status %d and %+v", q, status, res)
+		}
+	}
+}
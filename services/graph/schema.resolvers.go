@@ -0,0 +1,202 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// Parent is the resolver for the parent field.
+func (r *categoryResolver) Parent(ctx context.Context, obj *types.CategoryDTO) (*types.CategoryDTO, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+
+	parent, err := loadersFrom(ctx).Category.Load(ctx, *obj.ParentID)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	return parent, nil
+}
+
+// Children is the resolver for the children field.
+func (r *categoryResolver) Children(ctx context.Context, obj *types.CategoryDTO) ([]types.CategoryDTO, error) {
+	children, err := loadersFrom(ctx).Subcategories.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	return children, nil
+}
+
+// Links is the resolver for the links field.
+func (r *categoryResolver) Links(ctx context.Context, obj *types.CategoryDTO) ([]types.LinkDTO, error) {
+	links, err := loadersFrom(ctx).LinksForCategory.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	return links, nil
+}
+
+// Categories is the resolver for the categories field.
+func (r *linkResolver) Categories(ctx context.Context, obj *types.LinkDTO) ([]types.CategoryDTO, error) {
+	categories, err := loadersFrom(ctx).CategoriesForLink.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	return categories, nil
+}
+
+// CreateLink is the resolver for the createLink field.
+func (r *mutationResolver) CreateLink(ctx context.Context, input validator.LinkPayload) (*types.LinkDTO, error) {
+	if err := validator.Validate(input); err != nil {
+		return nil, err
+	}
+
+	id, err := r.links.CreateLink(ctx, input, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getLink(ctx, *id)
+}
+
+// UpdateLink is the resolver for the updateLink field.
+func (r *mutationResolver) UpdateLink(ctx context.Context, id string, input validator.LinkPayload, version *int32) (*types.LinkDTO, error) {
+	if err := validator.Validate(validator.UpdateLinkByIDParam{ID: id}, input); err != nil {
+		return nil, err
+	}
+
+	if err := r.links.UpdateLink(ctx, id, input, version); err != nil {
+		return nil, err
+	}
+
+	return r.getLink(ctx, id)
+}
+
+// DeleteLink is the resolver for the deleteLink field.
+func (r *mutationResolver) DeleteLink(ctx context.Context, id string, version *int32) (bool, error) {
+	if err := validator.Validate(validator.DeleteLinkByIDParam{ID: id}); err != nil {
+		return false, err
+	}
+
+	if err := r.links.DeleteLink(ctx, id, version); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateCategory is the resolver for the createCategory field.
+func (r *mutationResolver) CreateCategory(ctx context.Context, input validator.CategoryPayload) (*types.CategoryDTO, error) {
+	if err := validator.Validate(input); err != nil {
+		return nil, err
+	}
+
+	if err := r.categories.CreateCategory(ctx, input); err != nil {
+		return nil, err
+	}
+
+	// Names are unique, and the store doesn't return the ID of the new category
+	created, err := r.categoryStore.GetCategoryByName(ctx, input.Name)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if created == nil {
+		return nil, errs.NotFound(errs.ErrCategoryNotFound)
+	}
+
+	return r.getCategory(ctx, created.ID)
+}
+
+// UpdateCategory is the resolver for the updateCategory field.
+func (r *mutationResolver) UpdateCategory(ctx context.Context, id string, input validator.CategoryPayload, version *int32) (*types.CategoryDTO, error) {
+	if err := validator.Validate(validator.UpdateCategoryByIDParam{ID: id}, input); err != nil {
+		return nil, err
+	}
+
+	if err := r.categories.UpdateCategory(ctx, id, input, version); err != nil {
+		return nil, err
+	}
+
+	return r.getCategory(ctx, id)
+}
+
+// DeleteCategory is the resolver for the deleteCategory field.
+func (r *mutationResolver) DeleteCategory(ctx context.Context, id string, version *int32) (bool, error) {
+	if err := validator.Validate(validator.DeleteCategoryByIDParam{ID: id}); err != nil {
+		return false, err
+	}
+
+	if err := r.categories.DeleteCategory(ctx, id, version); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Links is the resolver for the links field.
+func (r *queryResolver) Links(ctx context.Context) ([]types.LinkDTO, error) {
+	links, err := r.linkStore.GetAllLinks(ctx)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	return links, nil
+}
+
+// Link is the resolver for the link field.
+func (r *queryResolver) Link(ctx context.Context, id string) (*types.LinkDTO, error) {
+	if err := validator.Validate(validator.GetLinkByIDParam{ID: id}); err != nil {
+		return nil, err
+	}
+
+	link, err := loadersFrom(ctx).Link.Load(ctx, id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	return link, nil
+}
+
+// Categories is the resolver for the categories field.
+func (r *queryResolver) Categories(ctx context.Context) ([]types.CategoryDTO, error) {
+	categories, err := r.categoryStore.GetAllCategories(ctx)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	return categories, nil
+}
+
+// Category is the resolver for the category field.
+func (r *queryResolver) Category(ctx context.Context, id string) (*types.CategoryDTO, error) {
+	if err := validator.Validate(validator.GetCategoryByIDParam{ID: id}); err != nil {
+		return nil, err
+	}
+
+	category, err := loadersFrom(ctx).Category.Load(ctx, id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	return category, nil
+}
+
+// Category returns CategoryResolver implementation.
+func (r *Resolver) Category() CategoryResolver { return &categoryResolver{r} }
+
+// Link returns LinkResolver implementation.
+func (r *Resolver) Link() LinkResolver { return &linkResolver{r} }
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type categoryResolver struct{ *Resolver }
+type linkResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
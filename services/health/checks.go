@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/database"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Checker reports whether a dependency of the API is ready to serve traffic.
+// The details, if any, are included in the readiness report.
+type Checker interface {
+	Check(ctx context.Context) (details any, err error)
+}
+
+// CheckerFunc adapts a plain function to the Checker interface.
+type CheckerFunc func(ctx context.Context) (any, error)
+
+func (f CheckerFunc) Check(ctx context.Context) (any, error) {
+	return f(ctx)
+}
+
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Details  any    `json:"details,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Registry holds the readiness checks of every subsystem.
+type Registry struct {
+	mu       sync.RWMutex
+	timeout  time.Duration
+	checkers map[string]Checker
+}
+
+// NewRegistry creates an empty registry whose checks are cancelled after the given timeout.
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout, checkers: make(map[string]Checker)}
+}
+
+// Register adds a named check, replacing any check with the same name.
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkers[name] = checker
+}
+
+// Run executes all checks concurrently and reports whether every one of them passed.
+func (r *Registry) Run(ctx context.Context) (bool, map[string]Result) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		ready   = true
+		results = make(map[string]Result, len(r.checkers))
+	)
+
+	for name, checker := range r.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			details, err := checker.Check(ctx)
+
+			result := Result{Status: StatusOK, Details: details, Duration: time.Since(start).String()}
+			if err != nil {
+				result.Status = StatusUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result
+			if err != nil {
+				ready = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	return ready, results
+}
+
+// DatabaseChecker pings the pool and reports its connection counts.
+func DatabaseChecker(conn *pgxpool.Pool) Checker {
+	return CheckerFunc(func(ctx context.Context) (any, error) {
+		stat := conn.Stat()
+		details := map[string]int32{
+			"total":    stat.TotalConns(),
+			"idle":     stat.IdleConns(),
+			"acquired": stat.AcquiredConns(),
+		}
+
+		return details, conn.Ping(ctx)
+	})
+}
+
+// MigrationsChecker fails while any embedded migration has not been applied yet.
+func MigrationsChecker(migrator *database.Migrator) Checker {
+	return CheckerFunc(func(ctx context.Context) (any, error) {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		details := map[string]any{"latest": migrator.Latest(), "pending": pending}
+		if pending > 0 {
+			return details, fmt.Errorf("%d migrations have not been applied", pending)
+		}
+
+		return details, nil
+	})
+}
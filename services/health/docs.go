@@ -0,0 +1,42 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/OmprakashD20/refero-api/openapi"
+)
+
+// Docs describes the health routes for the OpenAPI document.
+var Docs = []openapi.Route{
+	{
+		Method:      http.MethodGet,
+		Path:        "/healthz",
+		OperationID: "getLiveness",
+		Summary:     "Report that the server is up",
+		Responses: []openapi.Response{{Status: http.StatusOK, Body: struct {
+			Status string `json:"status"`
+		}{}}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/readyz",
+		OperationID: "getReadiness",
+		Summary:     "Report whether the dependencies of the server are healthy",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: readiness{}},
+			{Status: http.StatusServiceUnavailable, Description: "A check failed", Body: readiness{}},
+		},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/version",
+		OperationID: "getVersion",
+		Summary:     "Get the build of the server",
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: BuildInfo{}}},
+	},
+}
+
+type readiness = struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
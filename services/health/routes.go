@@ -0,0 +1,48 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthService struct {
+	registry *Registry
+	build    BuildInfo
+}
+
+func NewService(registry *Registry) *HealthService {
+	return &HealthService{registry, readBuildInfo()}
+}
+
+func (s *HealthService) SetupHealthRoutes(router gin.IRoutes) {
+	router.GET("/healthz", s.LivenessHandler)
+	router.GET("/readyz", s.ReadinessHandler)
+	router.GET("/version", s.VersionHandler)
+}
+
+// LivenessHandler only reports that the process is serving requests,
+// so a failing dependency never gets the API restarted.
+func (s *HealthService) LivenessHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": StatusOK,
+	})
+}
+
+func (s *HealthService) ReadinessHandler(c *gin.Context) {
+	ready, results := s.registry.Run(c.Request.Context())
+
+	status, code := StatusOK, http.StatusOK
+	if !ready {
+		status, code = StatusUnavailable, http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, gin.H{
+		"status": status,
+		"checks": results,
+	})
+}
+
+func (s *HealthService) VersionHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, s.build)
+}
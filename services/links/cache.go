@@ -0,0 +1,124 @@
+package links
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/OmprakashD20/refero-api/cache"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/metrics"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// cacheName labels the metrics of the short URL cache.
+const cacheName = "short_url"
+
+// CachedStore is a read-through cache in front of a LinkStore for short URL lookups.
+// Unknown short URLs are cached as nil for negativeTTL, so probing for codes doesn't reach the database.
+// Lookups within a transaction always go to the store. Misses are filled from the primary, as a link
+// read from a lagging replica could be cached after the eviction for its change. Entries are evicted as soon as a mutation
+// returns, and again by HandleLinkEvent once its event arrives after the commit. This drops an old
+// link cached by a lookup that raced with the transaction, as well as the entries of other instances.
+type CachedStore struct {
+	types.LinkStore
+	primary     *repository.Queries
+	cache       cache.Cache[*types.LinkDTO]
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+func NewCachedStore(store types.LinkStore, primary *repository.Queries, cache cache.Cache[*types.LinkDTO], ttl, negativeTTL time.Duration) *CachedStore {
+	return &CachedStore{store, primary, cache, ttl, negativeTTL}
+}
+
+func (s *CachedStore) GetLinkByShortURL(ctx context.Context, shortUrl string, txn *repository.Queries) (*types.LinkDTO, error) {
+	if txn != nil {
+		return s.LinkStore.GetLinkByShortURL(ctx, shortUrl, txn)
+	}
+
+	if link, ok := s.cache.Get(ctx, shortUrl); ok {
+		if link == nil {
+			metrics.CacheRequests.WithLabelValues(cacheName, "negative_hit").Inc()
+		} else {
+			metrics.CacheRequests.WithLabelValues(cacheName, "hit").Inc()
+		}
+		return link, nil
+	}
+	metrics.CacheRequests.WithLabelValues(cacheName, "miss").Inc()
+
+	link, err := s.LinkStore.GetLinkByShortURL(ctx, shortUrl, s.primary)
+	if err != nil {
+		return nil, err
+	}
+
+	if link == nil {
+		if s.negativeTTL > 0 {
+			s.cache.Set(ctx, shortUrl, nil, s.negativeTTL)
+		}
+		return nil, nil
+	}
+
+	s.cache.Set(ctx, shortUrl, link, s.ttl)
+	return link, nil
+}
+
+// CreateLink drops a cached miss for the short URL, in case it was looked up before it existed.
+func (s *CachedStore) CreateLink(ctx context.Context, link validator.CreateLinkPayload, shortUrl string, txn *repository.Queries) (*string, error) {
+	id, err := s.LinkStore.CreateLink(ctx, link, shortUrl, txn)
+	if err == nil {
+		s.cache.Delete(ctx, shortUrl)
+	}
+
+	return id, err
+}
+
+func (s *CachedStore) UpdateLinkByID(ctx context.Context, id string, link validator.UpdateLinkPayload, version *int32, txn *repository.Queries) error {
+	return s.evictAfter(ctx, id, func() error {
+		return s.LinkStore.UpdateLinkByID(ctx, id, link, version, txn)
+	})
+}
+
+func (s *CachedStore) DeleteLinkByID(ctx context.Context, id string, version *int32, txn *repository.Queries) error {
+	return s.evictAfter(ctx, id, func() error {
+		return s.LinkStore.DeleteLinkByID(ctx, id, version, txn)
+	})
+}
+
+// evictAfter runs the mutation and then drops the link from the cache.
+// The short URL is looked up first, as a deleted link can no longer be found by its ID.
+func (s *CachedStore) evictAfter(ctx context.Context, id string, mutate func() error) error {
+	link, err := s.LinkStore.GetLinkByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := mutate(); err != nil {
+		return err
+	}
+
+	if link != nil {
+		s.cache.Delete(ctx, link.ShortUrl)
+	}
+	return nil
+}
+
+// HandleLinkEvent evicts the short URL of a link changed on any instance.
+func (s *CachedStore) HandleLinkEvent(ctx context.Context, event events.Envelope) {
+	link, err := events.Decode[events.LinkChanged](event)
+	if err != nil {
+		// The link can't be told apart, so nothing cached can be trusted
+		slog.WarnContext(ctx, "clearing short URL cache", "error", err)
+		s.cache.Clear(ctx)
+		return
+	}
+
+	s.cache.Delete(ctx, link.ShortURL)
+}
+
+// Reset drops all entries, after events may have been missed.
+func (s *CachedStore) Reset(ctx context.Context) {
+	s.cache.Clear(ctx)
+}
@@ -0,0 +1,194 @@
+package links
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OmprakashD20/refero-api/cache"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/memstore"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+// roundTrip approximates a query against a Postgres instance on the same network.
+const roundTrip = 200 * time.Microsecond
+
+// slowStore resolves short URLs after a simulated database round trip.
+type slowStore struct {
+	types.LinkStore
+	links map[string]*types.LinkDTO
+}
+
+func (s *slowStore) GetLinkByShortURL(ctx context.Context, shortUrl string, txn *repository.Queries) (*types.LinkDTO, error) {
+	time.Sleep(roundTrip)
+	return s.links[shortUrl], nil
+}
+
+func BenchmarkGetLinkByShortURL(b *testing.B) {
+	store := &slowStore{links: make(map[string]*types.LinkDTO)}
+	codes := make([]string, 1000)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("code%d", i)
+		store.links[codes[i]] = &types.LinkDTO{ShortUrl: codes[i], Url: "https://example.com/" + codes[i]}
+	}
+
+	stores := []struct {
+		name  string
+		store types.LinkStore
+	}{
+		{"uncached", store},
+		{"cached", NewCachedStore(store, nil, cache.NewLRU[*types.LinkDTO](len(codes)), time.Minute, time.Minute)},
+	}
+
+	ctx := context.Background()
+	for _, bm := range stores {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := range b.N {
+				if _, err := bm.store.GetLinkByShortURL(ctx, codes[i%len(codes)], nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// countingStore counts the short URL lookups that reach the store.
+type countingStore struct {
+	*memstore.Store
+	lookups int
+}
+
+func (s *countingStore) GetLinkByShortURL(ctx context.Context, shortUrl string, txn *repository.Queries) (*types.LinkDTO, error) {
+	s.lookups++
+	return s.Store.GetLinkByShortURL(ctx, shortUrl, txn)
+}
+
+func newCachedStore(t *testing.T) (*CachedStore, *countingStore) {
+	t.Helper()
+
+	store := &countingStore{Store: memstore.New()}
+	return NewCachedStore(store, nil, cache.NewLRU[*types.LinkDTO](10), time.Minute, time.Minute), store
+}
+
+// lookup resolves the short URL through the cache and checks the title of the link, or that
+// there is none if the title is empty, and how many lookups reached the store so far.
+func lookup(t *testing.T, cached *CachedStore, store *countingStore, shortUrl, title string, lookups int) {
+	t.Helper()
+
+	link, err := cached.GetLinkByShortURL(context.Background(), shortUrl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title == "" && link != nil {
+		t.Errorf("GetLinkByShortURL(%q): got %+v, want nil", shortUrl, link)
+	}
+	if title != "" && (link == nil || link.Title != title) {
+		t.Errorf("GetLinkByShortURL(%q): got %+v, want the title %q", shortUrl, link, title)
+	}
+	if store.lookups != lookups {
+		t.Errorf("GetLinkByShortURL(%q): %d lookups reached the store, want %d", shortUrl, store.lookups, lookups)
+	}
+}
+
+func createCachedLink(t *testing.T, cached *CachedStore, url, shortUrl string) string {
+	t.Helper()
+
+	id, err := cached.CreateLink(context.Background(), linkPayload(url), shortUrl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *id
+}
+
+func linkEvent(t *testing.T, id, shortUrl string) events.Envelope {
+	t.Helper()
+
+	data, err := json.Marshal(events.LinkChanged{ID: id, ShortURL: shortUrl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events.Envelope{Type: events.LinkUpdated, Data: data}
+}
+
+func TestCachedStoreLookups(t *testing.T) {
+	cached, store := newCachedStore(t)
+	ctx := context.Background()
+	createCachedLink(t, cached, "https://go.dev", "golang")
+
+	lookup(t, cached, store, "golang", "Link title", 1)
+	lookup(t, cached, store, "golang", "Link title", 1)
+
+	// Unknown short URLs are cached as misses until a link is created for them
+	lookup(t, cached, store, "rustlang", "", 2)
+	lookup(t, cached, store, "rustlang", "", 2)
+	createCachedLink(t, cached, "https://rust-lang.org", "rustlang")
+	lookup(t, cached, store, "rustlang", "Link title", 3)
+
+	// Lookups within a transaction skip the cache
+	if _, err := cached.GetLinkByShortURL(ctx, "golang", &repository.Queries{}); err != nil {
+		t.Fatal(err)
+	}
+	if store.lookups != 4 {
+		t.Errorf("a lookup within a transaction was served from the cache")
+	}
+}
+
+func TestCachedStoreInvalidation(t *testing.T) {
+	cached, store := newCachedStore(t)
+	ctx := context.Background()
+	id := createCachedLink(t, cached, "https://go.dev", "golang")
+	lookup(t, cached, store, "golang", "Link title", 1)
+
+	update := linkPayload("https://go.dev")
+	update.Title = "The Go Programming Language"
+	if err := cached.UpdateLinkByID(ctx, id, update, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	lookup(t, cached, store, "golang", "The Go Programming Language", 2)
+
+	// A failed mutation keeps the entry
+	stale := int32(1)
+	if err := cached.UpdateLinkByID(ctx, id, linkPayload("https://go.dev"), &stale, nil); err == nil {
+		t.Fatal("UpdateLinkByID at a stale version succeeded")
+	}
+	lookup(t, cached, store, "golang", "The Go Programming Language", 2)
+
+	if err := cached.DeleteLinkByID(ctx, id, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	lookup(t, cached, store, "golang", "", 3)
+}
+
+func TestCachedStoreEvents(t *testing.T) {
+	cached, store := newCachedStore(t)
+	ctx := context.Background()
+	golang := createCachedLink(t, cached, "https://go.dev", "golang")
+	createCachedLink(t, cached, "https://rust-lang.org", "rustlang")
+	lookup(t, cached, store, "golang", "Link title", 1)
+	lookup(t, cached, store, "rustlang", "Link title", 2)
+
+	// Changes made by other instances bypass this cache until their events arrive
+	update := linkPayload("https://go.dev")
+	update.Title = "The Go Programming Language"
+	if err := store.UpdateLinkByID(ctx, golang, update, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	lookup(t, cached, store, "golang", "Link title", 2)
+
+	cached.HandleLinkEvent(ctx, linkEvent(t, golang, "golang"))
+	lookup(t, cached, store, "golang", "The Go Programming Language", 3)
+	lookup(t, cached, store, "rustlang", "Link title", 3)
+
+	// An event that can't be decoded drops every entry
+	cached.HandleLinkEvent(ctx, events.Envelope{Type: events.LinkUpdated, Data: json.RawMessage(`[]`)})
+	lookup(t, cached, store, "golang", "The Go Programming Language", 4)
+	lookup(t, cached, store, "rustlang", "Link title", 5)
+
+	cached.Reset(ctx)
+	lookup(t, cached, store, "golang", "The Go Programming Language", 6)
+	lookup(t, cached, store, "rustlang", "Link title", 7)
+}
@@ -0,0 +1,97 @@
+package links
+
+import (
+	"net/http"
+
+	"github.com/OmprakashD20/refero-api/openapi"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// Docs describes the link routes for the OpenAPI document.
+var Docs = []openapi.Route{
+	{
+		Method:      http.MethodPost,
+		Path:        "/",
+		OperationID: "createLink",
+		Summary:     "Create a link",
+		Description: "Creating a link to a URL which is already saved adds the categories to the existing link.",
+		Body:        validator.CreateLinkPayload{},
+		Responses:   []openapi.Response{{Status: http.StatusCreated}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/",
+		OperationID: "listLinks",
+		Summary:     "List the links",
+		Description: "Without a query, every link is listed. Otherwise the links containing the search term in their title, URL or description " +
+			"are listed newest first, in pages requested with limit and offset.",
+		Query:     validator.ListLinksQuery{},
+		Responses: []openapi.Response{{Status: http.StatusOK, Body: []types.LinkDTO{}}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/:id",
+		OperationID: "getLink",
+		Summary:     "Get a link",
+		Params:      validator.GetLinkByIDParam{},
+		Headers:     []openapi.Header{ifNoneMatch},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Headers: []openapi.Header{etag}, Body: types.LinkDTO{}},
+			{Status: http.StatusNotModified, Description: "The link still has the version in If-None-Match", Headers: []openapi.Header{etag}},
+		},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/r/:shortUrl",
+		OperationID: "redirectLink",
+		Summary:     "Follow a short URL",
+		Params:      validator.RedirectLinkParams{},
+		Responses: []openapi.Response{{
+			Status:      http.StatusMovedPermanently,
+			Description: "Redirects to the URL of the link",
+			Headers:     []openapi.Header{{Name: "Location", Description: "URL of the link"}},
+		}},
+	},
+	{
+		Method:      http.MethodPut,
+		Path:        "/:id",
+		OperationID: "updateLink",
+		Summary:     "Replace a link",
+		Params:      validator.UpdateLinkByIDParam{},
+		Headers:     []openapi.Header{ifMatch},
+		Body:        validator.UpdateLinkPayload{},
+		Responses:   []openapi.Response{{Status: http.StatusOK}},
+	},
+	{
+		Method:      http.MethodPatch,
+		Path:        "/:id",
+		OperationID: "patchLink",
+		Summary:     "Patch a link",
+		Description: "Applies a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) to the link, then validates the result as a full update.",
+		Params:      validator.PatchLinkByIDParam{},
+		Headers:     []openapi.Header{ifMatch},
+		Body: openapi.Content{
+			validator.MergePatchMediaType: mergePatch,
+			"application/json":            mergePatch,
+			validator.JSONPatchMediaType:  openapi.JSONPatch,
+		},
+		Responses: []openapi.Response{{Status: http.StatusOK}},
+	},
+	{
+		Method:      http.MethodDelete,
+		Path:        "/:id",
+		OperationID: "deleteLink",
+		Summary:     "Move a link to the trash",
+		Params:      validator.DeleteLinkByIDParam{},
+		Headers:     []openapi.Header{ifMatch},
+		Responses:   []openapi.Response{{Status: http.StatusOK}},
+	},
+}
+
+var (
+	mergePatch  = &openapi.Schema{Type: "object", Description: "Fields of the link to replace, null removes optional ones"}
+	etag        = openapi.Header{Name: "ETag", Description: "Version of the link"}
+	ifNoneMatch = openapi.Header{Name: "If-None-Match", Description: "ETag of the cached link"}
+	ifMatch     = openapi.Header{Name: "If-Match", Description: "ETag the link must still have, for the write to succeed"}
+)
@@ -1,11 +1,14 @@
 package links
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
 	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/metrics"
 	"github.com/OmprakashD20/refero-api/repository"
 	"github.com/OmprakashD20/refero-api/types"
 	"github.com/OmprakashD20/refero-api/utils"
@@ -26,11 +29,12 @@ func NewService(store types.LinkStore, txn types.TransactionStore) *LinkService
 func (s *LinkService) SetupLinkRoutes(api *gin.RouterGroup) {
 	api.POST("/", validator.ValidateBody[validator.CreateLinkPayload](), s.CreateLinkHandler)
 
-	api.GET("/", s.GetLinksHandler)
+	api.GET("/", validator.ValidateQuery[validator.ListLinksQuery](), s.GetLinksHandler)
 	api.GET("/:id", validator.ValidateParams[validator.GetLinkByIDParam](), s.GetLinkByIDHandler)
 	api.GET("/r/:shortUrl", validator.ValidateParams[validator.RedirectLinkParams](), s.RedirectURLHandler)
 
 	api.PUT("/:id", validator.ValidateParams[validator.UpdateLinkByIDParam](), validator.ValidateBody[validator.UpdateLinkPayload](), s.UpdateLinkByIDHandler)
+	api.PATCH("/:id", validator.ValidateParams[validator.PatchLinkByIDParam](), validator.ValidatePatchBody(), s.PatchLinkByIDHandler)
 
 	api.DELETE("/:id", validator.ValidateParams[validator.DeleteLinkByIDParam](), s.DeleteLinkByIDHandler)
 }
@@ -44,38 +48,41 @@ func (s *LinkService) CreateLinkHandler(c *gin.Context) {
 		return
 	}
 
+	if _, err := s.CreateLink(ctx, link, ""); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, nil)
+}
+
+// CreateLink creates the link with the given short URL, generating one if it is empty.
+// If a link with the same URL already exists, it is associated with the new categories instead.
+// It returns the ID of the created or existing link.
+func (s *LinkService) CreateLink(ctx context.Context, link validator.CreateLinkPayload, shortUrl string) (*string, error) {
 	// Check if link exists
 	linkID, err := s.store.CheckIfLinkExistsByURL(ctx, link.URL, nil)
 	if err != nil {
-		c.Error(errs.InternalServerError(errs.WithCause(err)))
-		return
+		return nil, errs.InternalServerError(errs.WithCause(err))
 	}
 
 	// If exists, associate the existing link with new categories
 	if linkID != nil {
-		err := s.txn.Exec(ctx, func(q *repository.Queries) error {
+		err := s.txn.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
 			existingCategories, err := s.store.GetCategoriesForLink(ctx, *linkID, q)
 			if err != nil {
 				return errs.InternalServerError(errs.WithCause(err))
 			}
 
-			existingCategorySet := make(map[string]struct{}, len(existingCategories))
-			for _, categoryID := range existingCategories {
-				existingCategorySet[categoryID] = struct{}{}
-			}
-
-			var mappings []types.LinkCategoryDTO
+			var added []string
 			for _, categoryID := range link.CategoryIDs {
-				if _, exists := existingCategorySet[categoryID]; !exists {
-					mappings = append(mappings, types.LinkCategoryDTO{
-						LinkID:     *linkID,
-						CategoryID: categoryID,
-					})
+				if !slices.Contains(existingCategories, categoryID) && !slices.Contains(added, categoryID) {
+					added = append(added, categoryID)
 				}
 			}
 
-			if len(mappings) > 0 {
-				if err := s.store.AddLinkToCategory(ctx, mappings, q); err != nil {
+			if len(added) > 0 {
+				if err := s.store.AddCategoriesToLink(ctx, *linkID, added, q); err != nil {
 					return errs.InternalServerError(errs.WithCause(err))
 				}
 			}
@@ -83,13 +90,7 @@ func (s *LinkService) CreateLinkHandler(c *gin.Context) {
 			return nil
 		})
 
-		if err != nil {
-			c.Error(err)
-			return
-		}
-
-		c.JSON(http.StatusCreated, nil)
-		return
+		return linkID, err
 	}
 
 	// Clean the URL
@@ -98,10 +99,12 @@ func (s *LinkService) CreateLinkHandler(c *gin.Context) {
 	}
 
 	// Generate the ShortURL
-	shortUrl := utils.GenerateShortURL(link.URL)
+	if shortUrl == "" {
+		shortUrl = utils.GenerateShortURL(link.URL)
+	}
 
 	// Insert the link
-	err = s.txn.Exec(ctx, func(q *repository.Queries) error {
+	err = s.txn.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
 		var err error
 		linkID, err = s.store.CreateLink(ctx, link, shortUrl, q)
 		if err != nil {
@@ -126,12 +129,7 @@ func (s *LinkService) CreateLinkHandler(c *gin.Context) {
 		return nil
 	})
 
-	if err != nil {
-		c.Error(err)
-		return
-	}
-
-	c.JSON(http.StatusCreated, nil)
+	return linkID, err
 }
 
 func (s *LinkService) RedirectURLHandler(c *gin.Context) {
@@ -146,18 +144,39 @@ func (s *LinkService) RedirectURLHandler(c *gin.Context) {
 	// Get the original link using the short url
 	data, err := s.store.GetLinkByShortURL(ctx, params.ShortURL, nil)
 	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+
+	// Link doesn't exist
+	if data == nil {
+		metrics.RedirectMiss()
 		c.Error(errs.NotFound(errs.ErrLinkNotFound))
 		return
 	}
 
+	metrics.RedirectHit()
 	c.Redirect(http.StatusMovedPermanently, data.Url)
 }
 
+// GetLinksHandler lists the links, or a page of the links matching the search term if one is queried.
 func (s *LinkService) GetLinksHandler(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	// Get all links from the database
-	links, err := s.store.GetAllLinks(ctx)
+	query, ok := validator.GetValidatedData[validator.ListLinksQuery](c, validator.ValidatedQueryKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	var links []types.LinkDTO
+	var err error
+	if query == (validator.ListLinksQuery{}) {
+		// Get all links from the database
+		links, err = s.store.GetAllLinks(ctx)
+	} else {
+		links, err = s.store.SearchLinks(ctx, query.Search, query.Limit, query.Offset)
+	}
 	if err != nil {
 		c.Error(errs.InternalServerError(errs.WithCause(err)))
 		return
@@ -194,6 +213,14 @@ func (s *LinkService) GetLinkByIDHandler(c *gin.Context) {
 		return
 	}
 
+	// Skip the body if the client already has the current version
+	etag := utils.FormatETag(link.Version)
+	c.Header("ETag", etag)
+	if utils.MatchETag(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, link)
 }
 
@@ -212,18 +239,105 @@ func (s *LinkService) UpdateLinkByIDHandler(c *gin.Context) {
 		return
 	}
 
-	err := s.txn.Exec(ctx, func(q *repository.Queries) error {
+	version, err := s.checkPrecondition(c, params.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := s.UpdateLink(ctx, params.ID, link, version); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func (s *LinkService) PatchLinkByIDHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, ok := validator.GetValidatedData[validator.PatchLinkByIDParam](c, validator.ValidatedParamKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	patch, ok := validator.GetValidatedData[validator.PatchPayload](c, validator.ValidatedBodyKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	// Get the current state of the link
+	existing, err := s.store.GetLinkByID(ctx, params.ID)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+	if existing == nil {
+		c.Error(errs.NotFound(errs.ErrLinkNotFound))
+		return
+	}
+
+	// Only apply the patch on top of the version the client has seen
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && !utils.MatchStrongETag(ifMatch, utils.FormatETag(existing.Version)) {
+		c.Error(errs.PreconditionFailed(errs.ErrPreconditionFailed))
+		return
+	}
+
+	categoryIDs, err := s.store.GetCategoriesForLink(ctx, params.ID, nil)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+
+	current := validator.UpdateLinkPayload{
+		Title:       existing.Title,
+		URL:         existing.Url,
+		Description: &existing.Description,
+		CategoryIDs: categoryIDs,
+	}
+
+	// Apply the patch and validate the result
+	link, err := validator.ApplyPatch(current, patch)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Update the link, unless it was modified since it was read
+	if err := s.UpdateLink(ctx, params.ID, link, &existing.Version); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// UpdateLink replaces the link details and syncs its category mappings within a single transaction.
+// If version is set, the update only succeeds while the link is still at that version.
+func (s *LinkService) UpdateLink(ctx context.Context, id string, link validator.UpdateLinkPayload, version *int32) error {
+	// Clean the URL
+	if !strings.HasPrefix(link.URL, "http://") && !strings.HasPrefix(link.URL, "https://") {
+		link.URL = "https://" + link.URL
+	}
+
+	return s.txn.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
 		// Update the link
-		if err := s.store.UpdateLinkByID(ctx, params.ID, link, q); err != nil {
+		if err := s.store.UpdateLinkByID(ctx, id, link, version, q); err != nil {
 			// If link doesn't exists
 			if errors.Is(err, errs.ErrLinkNotFound) {
 				return errs.NotFound(errs.ErrLinkNotFound)
 			}
+			// If link was modified concurrently
+			if errors.Is(err, errs.ErrPreconditionFailed) {
+				return errs.PreconditionFailed(errs.ErrPreconditionFailed)
+			}
 			return errs.InternalServerError(errs.WithError(errs.ErrFailedToUpdateLink), errs.WithCause(err))
 		}
 
 		// Get the existing categories associated with the link
-		existingCategories, err := s.store.GetCategoriesForLink(ctx, params.ID, q)
+		existingCategories, err := s.store.GetCategoriesForLink(ctx, id, q)
 		if err != nil {
 			return errs.InternalServerError(errs.WithError(errs.ErrFailedToUpdateLink), errs.WithCause(err))
 		}
@@ -242,7 +356,7 @@ func (s *LinkService) UpdateLinkByIDHandler(c *gin.Context) {
 		for _, categoryID := range existingCategories {
 			if _, exists := newCategorySet[categoryID]; !exists {
 				categoriesToRemove = append(categoriesToRemove, types.LinkCategoryDTO{
-					LinkID:     params.ID,
+					LinkID:     id,
 					CategoryID: categoryID,
 				})
 			}
@@ -252,7 +366,7 @@ func (s *LinkService) UpdateLinkByIDHandler(c *gin.Context) {
 		for _, categoryID := range link.CategoryIDs {
 			if _, exists := existingCategorySet[categoryID]; !exists {
 				categoriesToAdd = append(categoriesToAdd, types.LinkCategoryDTO{
-					LinkID:     params.ID,
+					LinkID:     id,
 					CategoryID: categoryID,
 				})
 			}
@@ -271,13 +385,6 @@ func (s *LinkService) UpdateLinkByIDHandler(c *gin.Context) {
 
 		return nil
 	})
-
-	if err != nil {
-		c.Error(err)
-		return
-	}
-
-	c.JSON(http.StatusOK, nil)
 }
 
 func (s *LinkService) DeleteLinkByIDHandler(c *gin.Context) {
@@ -289,17 +396,58 @@ func (s *LinkService) DeleteLinkByIDHandler(c *gin.Context) {
 		return
 	}
 
-	// Delete the link
-	if err := s.store.DeleteLinkByID(ctx, params.ID, nil); err != nil {
+	version, err := s.checkPrecondition(c, params.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := s.DeleteLink(ctx, params.ID, version); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// DeleteLink moves the link to the trash.
+// If version is set, it only succeeds while the link is still at that version.
+func (s *LinkService) DeleteLink(ctx context.Context, id string, version *int32) error {
+	if err := s.store.DeleteLinkByID(ctx, id, version, nil); err != nil {
 		// If link doesn't exists
 		if errors.Is(err, errs.ErrLinkNotFound) {
-			c.Error(errs.NotFound(errs.ErrLinkNotFound))
-			return
+			return errs.NotFound(errs.ErrLinkNotFound)
+		}
+		// If link was modified concurrently
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return errs.PreconditionFailed(errs.ErrPreconditionFailed)
 		}
 
-		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToDeleteLink), errs.WithCause(err)))
-		return
+		return errs.InternalServerError(errs.WithError(errs.ErrFailedToDeleteLink), errs.WithCause(err))
 	}
 
-	c.JSON(http.StatusOK, nil)
+	return nil
+}
+
+// checkPrecondition evaluates the If-Match header against the current version of the link.
+// It returns the version the write has to match, or nil if the request is unconditional.
+func (s *LinkService) checkPrecondition(c *gin.Context, id string) (*int32, error) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		return nil, nil
+	}
+
+	link, err := s.store.GetLinkByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if link == nil {
+		return nil, errs.NotFound(errs.ErrLinkNotFound)
+	}
+
+	if !utils.MatchStrongETag(ifMatch, utils.FormatETag(link.Version)) {
+		return nil, errs.PreconditionFailed(errs.ErrPreconditionFailed)
+	}
+
+	return &link.Version, nil
 }
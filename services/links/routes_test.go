@@ -0,0 +1,314 @@
+package links
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/OmprakashD20/refero-api/apitest"
+	"github.com/OmprakashD20/refero-api/memstore"
+	"github.com/OmprakashD20/refero-api/types"
+	"github.com/OmprakashD20/refero-api/utils"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// newTestServer serves the link routes over an in-memory store.
+func newTestServer(t *testing.T) (*memstore.Store, http.Handler) {
+	t.Helper()
+
+	store := memstore.New()
+	handler := apitest.Handler(t, "/api/v1/link", func(api *gin.RouterGroup) {
+		NewService(store, store).SetupLinkRoutes(api)
+	})
+
+	return store, handler
+}
+
+func createCategory(t *testing.T, store *memstore.Store, name string) string {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := store.CreateCategory(ctx, validator.CreateCategoryPayload{Name: name}); err != nil {
+		t.Fatal(err)
+	}
+	category, err := store.GetCategoryByName(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return category.ID
+}
+
+func createLink(t *testing.T, handler http.Handler, payload validator.CreateLinkPayload) types.LinkDTO {
+	t.Helper()
+
+	if res := apitest.Serve(t, handler, http.MethodPost, "/api/v1/link/", payload); res.Code != http.StatusCreated {
+		t.Fatalf("creating %s: got status %d: %s", payload.URL, res.Code, res.Body)
+	}
+
+	for _, link := range apitest.Decode[[]types.LinkDTO](t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/", nil)) {
+		if link.Url == payload.URL {
+			return link
+		}
+	}
+	t.Fatalf("created link %s is not listed", payload.URL)
+	return types.LinkDTO{}
+}
+
+func linkPayload(url string, categoryIDs ...string) validator.CreateLinkPayload {
+	description := "A link to " + url
+	return validator.CreateLinkPayload{Title: "Link title", URL: url, Description: &description, CategoryIDs: categoryIDs}
+}
+
+func TestCreateLink(t *testing.T) {
+	store, handler := newTestServer(t)
+	ctx := context.Background()
+	golang := createCategory(t, store, "golang")
+	tools := createCategory(t, store, "tools")
+
+	link := createLink(t, handler, linkPayload("https://go.dev", golang))
+	if link.ShortUrl == "" || link.Version != 1 {
+		t.Errorf("got %+v, want a short URL at version 1", link)
+	}
+
+	// Saving the URL again adds the new categories to the existing link
+	again := createLink(t, handler, linkPayload("https://go.dev", golang, tools))
+	if again.ID != link.ID {
+		t.Errorf("saving the URL again created link %s, want %s", again.ID, link.ID)
+	}
+	if again.Version != 2 {
+		t.Errorf("got version %d after adding categories, want 2", again.Version)
+	}
+	categories, err := store.GetCategoriesForLink(ctx, link.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(categories, []string{golang, tools}) {
+		t.Errorf("got categories %v, want %v", categories, []string{golang, tools})
+	}
+
+	t.Run("invalid payload", func(t *testing.T) {
+		res := apitest.Serve(t, handler, http.MethodPost, "/api/v1/link/", validator.CreateLinkPayload{Title: "Go", URL: "not a url"})
+		problem := apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "validation_failed")
+		if len(problem.Errors) != 3 {
+			t.Errorf("got field errors %+v, want title, url and description", problem.Errors)
+		}
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		res := apitest.Serve(t, handler, http.MethodPost, "/api/v1/link/", linkPayload("https://pkg.go.dev", apitest.UnknownID))
+		apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "invalid_reference")
+
+		// The link is not created without its categories
+		if id, _ := store.CheckIfLinkExistsByURL(ctx, "https://pkg.go.dev", nil); id != nil {
+			t.Error("the link was created even though its category doesn't exist")
+		}
+	})
+
+	t.Run("trashed category", func(t *testing.T) {
+		trashed := createCategory(t, store, "trashed")
+		if err := store.DeleteCategoryByID(ctx, trashed, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		// Links mapped to a trashed category would not be listed anywhere
+		res := apitest.Serve(t, handler, http.MethodPost, "/api/v1/link/", linkPayload("https://go.dev/play", trashed))
+		apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "invalid_reference")
+		res = apitest.Serve(t, handler, http.MethodPost, "/api/v1/link/", linkPayload("https://go.dev", trashed))
+		apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "invalid_reference")
+		res = apitest.Serve(t, handler, http.MethodPut, "/api/v1/link/"+link.ID, linkPayload("https://go.dev", trashed))
+		apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "invalid_reference")
+	})
+
+	t.Run("failure rolls back", func(t *testing.T) {
+		store.Fail("AddLinkToCategory", errors.New("connection reset"))
+		defer store.Fail("AddLinkToCategory", nil)
+
+		res := apitest.Serve(t, handler, http.MethodPost, "/api/v1/link/", linkPayload("https://go.dev/blog", golang))
+		apitest.ExpectProblem(t, res, http.StatusInternalServerError, "internal_server_error")
+
+		if id, _ := store.CheckIfLinkExistsByURL(ctx, "https://go.dev/blog", nil); id != nil {
+			t.Error("the link was created even though its categories failed to be added")
+		}
+	})
+}
+
+func TestGetLinks(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	for _, url := range []string{"https://go.dev", "https://pkg.go.dev", "https://www.rust-lang.org", "https://go.dev/doc"} {
+		createLink(t, handler, linkPayload(url))
+	}
+
+	urls := func(links []types.LinkDTO) []string {
+		var urls []string
+		for _, link := range links {
+			urls = append(urls, link.Url)
+		}
+		return urls
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"https://go.dev", "https://pkg.go.dev", "https://www.rust-lang.org", "https://go.dev/doc"}},
+		{"?search=GO.DEV", []string{"https://go.dev/doc", "https://pkg.go.dev", "https://go.dev"}},
+		{"?search=go.dev&limit=2", []string{"https://go.dev/doc", "https://pkg.go.dev"}},
+		{"?search=go.dev&limit=2&offset=2", []string{"https://go.dev"}},
+		{"?search=100%25", nil},
+	}
+	for _, tt := range tests {
+		res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/"+tt.query, nil)
+		if res.Code != http.StatusOK {
+			t.Fatalf("GET %s: got status %d: %s", tt.query, res.Code, res.Body)
+		}
+		if got := urls(apitest.Decode[[]types.LinkDTO](t, res)); !slices.Equal(got, tt.want) {
+			t.Errorf("GET %s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/?limit=1000", nil)
+	apitest.ExpectProblem(t, res, http.StatusUnprocessableEntity, "validation_failed")
+}
+
+func TestGetLinkByID(t *testing.T) {
+	_, handler := newTestServer(t)
+	link := createLink(t, handler, linkPayload("https://go.dev"))
+
+	res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/"+link.ID, nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+	if got := apitest.Decode[types.LinkDTO](t, res); got.ID != link.ID || got.Url != link.Url {
+		t.Errorf("got %+v, want %+v", got, link)
+	}
+
+	etag := res.Header().Get("ETag")
+	if etag != utils.FormatETag(1) {
+		t.Errorf("got ETag %q, want %q", etag, utils.FormatETag(1))
+	}
+	if res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/"+link.ID, nil, "If-None-Match", etag); res.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: got status %d, want %d", res.Code, http.StatusNotModified)
+	}
+
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/"+apitest.UnknownID, nil), http.StatusNotFound, "link_not_found")
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/not-a-uuid", nil), http.StatusUnprocessableEntity, "validation_failed")
+}
+
+func TestRedirect(t *testing.T) {
+	_, handler := newTestServer(t)
+	link := createLink(t, handler, linkPayload("https://go.dev"))
+
+	res := apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/r/"+link.ShortUrl, nil)
+	if res.Code != http.StatusMovedPermanently {
+		t.Fatalf("got status %d, want %d", res.Code, http.StatusMovedPermanently)
+	}
+	if location := res.Header().Get("Location"); location != link.Url {
+		t.Errorf("redirected to %q, want %q", location, link.Url)
+	}
+
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/r/missing", nil), http.StatusNotFound, "link_not_found")
+}
+
+func TestUpdateLink(t *testing.T) {
+	store, handler := newTestServer(t)
+	golang := createCategory(t, store, "golang")
+	tools := createCategory(t, store, "tools")
+	link := createLink(t, handler, linkPayload("https://go.dev", golang))
+
+	update := linkPayload("https://go.dev/doc", tools)
+	update.Title = "Documentation"
+
+	// Writes conditional on a stale version are rejected
+	res := apitest.Serve(t, handler, http.MethodPut, "/api/v1/link/"+link.ID, update, "If-Match", utils.FormatETag(2))
+	apitest.ExpectProblem(t, res, http.StatusPreconditionFailed, "precondition_failed")
+
+	// If-Match uses the strong comparison, so a weak tag of the current version doesn't match either
+	res = apitest.Serve(t, handler, http.MethodPut, "/api/v1/link/"+link.ID, update, "If-Match", "W/"+utils.FormatETag(1))
+	apitest.ExpectProblem(t, res, http.StatusPreconditionFailed, "precondition_failed")
+
+	res = apitest.Serve(t, handler, http.MethodPut, "/api/v1/link/"+link.ID, update, "If-Match", utils.FormatETag(1))
+	if res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+
+	got := apitest.Decode[types.LinkDTO](t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/"+link.ID, nil))
+	if got.Title != "Documentation" || got.Url != "https://go.dev/doc" || got.Version != 2 {
+		t.Errorf("got %+v after the update", got)
+	}
+	categories, err := store.GetCategoriesForLink(context.Background(), link.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(categories, []string{tools}) {
+		t.Errorf("got categories %v, want %v", categories, []string{tools})
+	}
+
+	res = apitest.Serve(t, handler, http.MethodPut, "/api/v1/link/"+apitest.UnknownID, update)
+	apitest.ExpectProblem(t, res, http.StatusNotFound, "link_not_found")
+}
+
+func TestPatchLink(t *testing.T) {
+	_, handler := newTestServer(t)
+	link := createLink(t, handler, linkPayload("https://go.dev"))
+
+	patch := func(body string, header ...string) *httptest.ResponseRecorder {
+		return apitest.Serve(t, handler, http.MethodPatch, "/api/v1/link/"+link.ID, body, append([]string{"Content-Type", validator.MergePatchMediaType}, header...)...)
+	}
+
+	if res := patch(`{"title": "The Go Programming Language"}`); res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+	got := apitest.Decode[types.LinkDTO](t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/"+link.ID, nil))
+	if got.Title != "The Go Programming Language" || got.Url != link.Url || got.Version != 2 {
+		t.Errorf("got %+v after the patch", got)
+	}
+
+	// The patched link is validated
+	apitest.ExpectProblem(t, patch(`{"title": "Go"}`), http.StatusUnprocessableEntity, "validation_failed")
+	apitest.ExpectProblem(t, patch(`{"title": "Golang"}`, "If-Match", utils.FormatETag(1)), http.StatusPreconditionFailed, "precondition_failed")
+
+	t.Run("json patch", func(t *testing.T) {
+		jsonPatch := func(body string) *httptest.ResponseRecorder {
+			return apitest.Serve(t, handler, http.MethodPatch, "/api/v1/link/"+link.ID, body, "Content-Type", validator.JSONPatchMediaType)
+		}
+
+		res := jsonPatch(`[{"op": "test", "path": "/title", "value": "The Go Programming Language"}, {"op": "replace", "path": "/title", "value": "Golang"}]`)
+		if res.Code != http.StatusOK {
+			t.Fatalf("got status %d: %s", res.Code, res.Body)
+		}
+		got := apitest.Decode[types.LinkDTO](t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/"+link.ID, nil))
+		if got.Title != "Golang" || got.Version != 3 {
+			t.Errorf("got %+v after the patch", got)
+		}
+
+		// The merged link is validated, not the operations
+		apitest.ExpectProblem(t, jsonPatch(`[{"op": "replace", "path": "/title", "value": "Go"}]`), http.StatusUnprocessableEntity, "validation_failed")
+		apitest.ExpectProblem(t, jsonPatch(`[{"op": "remove", "path": "/url"}]`), http.StatusUnprocessableEntity, "validation_failed")
+		apitest.ExpectProblem(t, jsonPatch(`[{"op": "test", "path": "/title", "value": "Rust"}]`), http.StatusUnprocessableEntity, "invalid_patch")
+		apitest.ExpectProblem(t, jsonPatch(`[{"op": "replace", "path": "/missing", "value": "x"}]`), http.StatusUnprocessableEntity, "invalid_patch")
+	})
+}
+
+func TestDeleteLink(t *testing.T) {
+	_, handler := newTestServer(t)
+	link := createLink(t, handler, linkPayload("https://go.dev"))
+
+	if res := apitest.Serve(t, handler, http.MethodDelete, "/api/v1/link/"+link.ID, nil); res.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", res.Code, res.Body)
+	}
+
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/"+link.ID, nil), http.StatusNotFound, "link_not_found")
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodGet, "/api/v1/link/r/"+link.ShortUrl, nil), http.StatusNotFound, "link_not_found")
+	apitest.ExpectProblem(t, apitest.Serve(t, handler, http.MethodDelete, "/api/v1/link/"+link.ID, nil), http.StatusNotFound, "link_not_found")
+
+	// The URL can be saved again once the link is in the trash
+	if again := createLink(t, handler, linkPayload("https://go.dev")); again.ID == link.ID {
+		t.Error("saving the URL again restored the deleted link")
+	}
+}
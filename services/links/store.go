@@ -2,10 +2,17 @@ package links
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/OmprakashD20/refero-api/database"
 	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
 	"github.com/OmprakashD20/refero-api/repository"
 	"github.com/OmprakashD20/refero-api/types"
 	"github.com/OmprakashD20/refero-api/utils"
@@ -15,10 +22,23 @@ import (
 type Store struct {
 	conn *pgxpool.Pool
 	db   *repository.Queries
+	read *repository.Queries // Read-only queries that tolerate replication lag
+	txns *database.Store
 }
 
 func NewStore(conn *pgxpool.Pool) *Store {
-	return &Store{conn: conn, db: repository.New(conn)}
+	db := repository.New(conn)
+	return &Store{conn: conn, db: db, read: db, txns: database.NewTransactionStore(conn)}
+}
+
+// WithReplica sends listing, search, batch loading and short URL redirects to the replica pool.
+// GetLinkByID stays on the primary, as it backs the version checks of updates and deletes,
+// and so do lookups within a transaction and the misses of the short URL cache.
+func (s *Store) WithReplica(replica *pgxpool.Pool) *Store {
+	if replica != nil {
+		s.read = repository.New(replica)
+	}
+	return s
 }
 
 func (s *Store) CheckIfLinkExistsByURL(ctx context.Context, url string, txn *repository.Queries) (*string, error) {
@@ -34,10 +54,16 @@ func (s *Store) CheckIfLinkExistsByURL(ctx context.Context, url string, txn *rep
 	return utils.PgUUIDToStringPtr(link.ID), nil
 }
 
-func (s *Store) CreateLink(ctx context.Context, link validator.CreateLinkPayload, shortUrl string, txn *repository.Queries) (*string, error) {
-	if txn == nil {
-		txn = s.db
+// inTxn runs fn in the given transaction, or in a new one if there is none,
+// so that the events published by a mutation are only delivered once it commits.
+func (s *Store) inTxn(ctx context.Context, txn *repository.Queries, fn func(ctx context.Context, q *repository.Queries) error) error {
+	if txn != nil {
+		return fn(ctx, txn)
 	}
+	return s.txns.Exec(ctx, fn)
+}
+
+func (s *Store) CreateLink(ctx context.Context, link validator.CreateLinkPayload, shortUrl string, txn *repository.Queries) (*string, error) {
 	args := repository.CreateLinkParams{
 		Title:       link.Title,
 		Description: *link.Description,
@@ -45,16 +71,25 @@ func (s *Store) CreateLink(ctx context.Context, link validator.CreateLinkPayload
 		ShortUrl:    shortUrl,
 	}
 
-	linkID, err := txn.CreateLink(ctx, args)
-	if !linkID.Valid {
+	var id *string
+	err := s.inTxn(ctx, txn, func(ctx context.Context, q *repository.Queries) error {
+		linkID, err := q.CreateLink(ctx, args)
+		if !linkID.Valid {
+			return errs.TranslatePgError(err)
+		}
+		id = utils.PgUUIDToStringPtr(linkID)
+
+		return events.Publish(ctx, q, events.LinkCreated, events.LinkChanged{ID: *id, ShortURL: shortUrl}, link.CategoryIDs)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return utils.PgUUIDToStringPtr(linkID), nil
+	return id, nil
 }
 
 func (s *Store) GetAllLinks(ctx context.Context) ([]types.LinkDTO, error) {
-	data, err := s.db.GetAllLinks(ctx)
+	data, err := s.read.GetAllLinks(ctx)
 	if err != nil {
 		return errs.IsErrNoRows[[]types.LinkDTO](err, nil)
 	}
@@ -67,6 +102,7 @@ func (s *Store) GetAllLinks(ctx context.Context) ([]types.LinkDTO, error) {
 			Description: link.Description,
 			Url:         link.Url,
 			ShortUrl:    link.ShortUrl,
+			Version:     link.Version,
 			CreatedAt:   &link.CreatedAt.Time,
 			UpdatedAt:   &link.UpdatedAt.Time,
 		}
@@ -75,6 +111,43 @@ func (s *Store) GetAllLinks(ctx context.Context) ([]types.LinkDTO, error) {
 	return links, nil
 }
 
+// SearchLinks returns the links containing the search term in their title, URL or description,
+// newest first. An empty term matches every link, and a limit of 0 returns all of them.
+func (s *Store) SearchLinks(ctx context.Context, search string, limit, offset int32) ([]types.LinkDTO, error) {
+	params := repository.SearchLinksParams{Skip: offset}
+	if search != "" {
+		pattern := "%" + likeEscaper.Replace(search) + "%"
+		params.Pattern = &pattern
+	}
+	if limit > 0 {
+		params.MaxLinks = &limit
+	}
+
+	data, err := s.read.SearchLinks(ctx, params)
+	if err != nil {
+		return errs.IsErrNoRows[[]types.LinkDTO](err, nil)
+	}
+
+	links := make([]types.LinkDTO, len(data))
+	for i, link := range data {
+		links[i] = types.LinkDTO{
+			ID:          link.ID.String(),
+			Url:         link.Url,
+			Title:       link.Title,
+			Description: link.Description,
+			ShortUrl:    link.ShortUrl,
+			Version:     link.Version,
+			CreatedAt:   &link.CreatedAt.Time,
+			UpdatedAt:   &link.UpdatedAt.Time,
+		}
+	}
+
+	return links, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, so search terms match literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (s *Store) GetLinkByID(ctx context.Context, id string) (*types.LinkDTO, error) {
 	linkID := utils.ToPgUUID(id)
 	
@@ -89,6 +162,7 @@ func (s *Store) GetLinkByID(ctx context.Context, id string) (*types.LinkDTO, err
 		Description: data.Description,
 		Url:         data.Url,
 		ShortUrl:    data.ShortUrl,
+		Version:     data.Version,
 	}
 
 	return link, nil
@@ -111,26 +185,91 @@ func (s *Store) GetCategoriesForLink(ctx context.Context, id string, txn *reposi
 	return categoryIDs, nil
 }
 
-func (s *Store) AddLinkToCategory(ctx context.Context, mappings []types.LinkCategoryDTO, txn *repository.Queries) error {
-	if txn == nil {
-		txn = s.db
+// GetLinksByIDs returns the links with the given IDs, in no particular order.
+// IDs of links that don't exist are left out.
+func (s *Store) GetLinksByIDs(ctx context.Context, ids []string) ([]types.LinkDTO, error) {
+	data, err := s.read.GetLinksByIDs(ctx, utils.ToPgUUIDs(ids))
+	if err != nil {
+		return errs.IsErrNoRows[[]types.LinkDTO](err, nil)
+	}
+
+	links := make([]types.LinkDTO, len(data))
+	for i, link := range data {
+		links[i] = types.LinkDTO{
+			ID:          link.ID.String(),
+			Url:         link.Url,
+			Title:       link.Title,
+			Description: link.Description,
+			ShortUrl:    link.ShortUrl,
+			Version:     link.Version,
+			CreatedAt:   &link.CreatedAt.Time,
+			UpdatedAt:   &link.UpdatedAt.Time,
+		}
+	}
+
+	return links, nil
+}
+
+// GetCategoriesForLinks returns the categories of each of the given links, keyed by link ID.
+func (s *Store) GetCategoriesForLinks(ctx context.Context, ids []string) (map[string][]types.CategoryDTO, error) {
+	data, err := s.read.GetCategoriesForLinks(ctx, utils.ToPgUUIDs(ids))
+	if err != nil {
+		return errs.IsErrNoRows[map[string][]types.CategoryDTO](err, nil)
 	}
+
+	categories := make(map[string][]types.CategoryDTO, len(ids))
+	for _, category := range data {
+		linkID := category.LinkID.String()
+		categories[linkID] = append(categories[linkID], types.CategoryDTO{
+			ID:          category.ID.String(),
+			Name:        category.Name,
+			Description: category.Description,
+			ParentID:    utils.PgUUIDToStringPtr(category.ParentID),
+			Version:     category.Version,
+			CreatedAt:   &category.CreatedAt.Time,
+			UpdatedAt:   &category.UpdatedAt.Time,
+		})
+	}
+
+	return categories, nil
+}
+
+// AddLinkToCategory adds the mappings. The categories have to exist outside of the trash,
+// as links mapped to a trashed category would not be listed in it, and are locked until
+// the transaction commits, so they aren't trashed in the meantime.
+func (s *Store) AddLinkToCategory(ctx context.Context, mappings []types.LinkCategoryDTO, txn *repository.Queries) error {
 	var args []repository.AddLinkToCategoryParams
+	var categoryIDs []pgtype.UUID
 
 	for _, obj := range mappings {
+		categoryID := utils.ToPgUUID(obj.CategoryID)
 		args = append(args, repository.AddLinkToCategoryParams{
 			LinkID:     utils.ToPgUUID(obj.LinkID),
-			CategoryID: utils.ToPgUUID(obj.CategoryID),
+			CategoryID: categoryID,
 		})
+		if !slices.Contains(categoryIDs, categoryID) {
+			categoryIDs = append(categoryIDs, categoryID)
+		}
 	}
 
-	_, err := txn.AddLinkToCategory(ctx, args)
-	return err
+	return s.inTxn(ctx, txn, func(ctx context.Context, q *repository.Queries) error {
+		live, err := q.LockLiveCategories(ctx, categoryIDs)
+		if err != nil {
+			return errs.TranslatePgError(err)
+		}
+		if len(live) != len(categoryIDs) {
+			// Category doesn't exists in the database, or is in the trash
+			return errs.InvalidReference("categoryIds")
+		}
+
+		_, err = q.AddLinkToCategory(ctx, args)
+		return errs.TranslatePgError(err)
+	})
 }
 
 func (s *Store) GetLinkByShortURL(ctx context.Context, shortUrl string, txn *repository.Queries) (*types.LinkDTO, error) {
 	if txn == nil {
-		txn = s.db
+		txn = s.read
 	}
 	link, err := txn.GetLinkByShortURL(ctx, shortUrl)
 	if err != nil {
@@ -149,23 +288,62 @@ func (s *Store) GetLinkByShortURL(ctx context.Context, shortUrl string, txn *rep
 	return data, nil
 }
 
-func (s *Store) UpdateLinkByID(ctx context.Context, id string, link validator.UpdateLinkPayload, txn *repository.Queries) error {
-	if txn == nil {
-		txn = s.db
-	}
+func (s *Store) UpdateLinkByID(ctx context.Context, id string, link validator.UpdateLinkPayload, version *int32, txn *repository.Queries) error {
 	args := repository.UpdateLinkParams{
 		ID:          utils.ToPgUUID(id),
 		Title:       link.Title,
+		Url:         link.URL,
 		Description: *link.Description,
+		Version:     version,
 	}
 
-	rows, err := txn.UpdateLink(ctx, args)
-	if rows == 0 {
-		// Link does not exists in the database
-		return errs.ErrLinkNotFound
+	return s.inTxn(ctx, txn, func(ctx context.Context, q *repository.Queries) error {
+		// The event is relevant to the categories the link is removed from as well
+		categoryIDs, err := s.GetCategoriesForLink(ctx, id, q)
+		if err != nil {
+			return err
+		}
+
+		shortUrl, err := q.UpdateLink(ctx, args)
+		if err != nil {
+			return linkMutationError(err, version)
+		}
+
+		for _, categoryID := range link.CategoryIDs {
+			if !slices.Contains(categoryIDs, categoryID) {
+				categoryIDs = append(categoryIDs, categoryID)
+			}
+		}
+
+		return events.Publish(ctx, q, events.LinkUpdated, events.LinkChanged{ID: id, ShortURL: shortUrl}, categoryIDs)
+	})
+}
+
+// AddCategoriesToLink adds an existing link to the categories. The version of the link is bumped
+// and the update published, as the link is listed in the categories from then on.
+func (s *Store) AddCategoriesToLink(ctx context.Context, id string, categoryIDs []string, txn *repository.Queries) error {
+	mappings := make([]types.LinkCategoryDTO, len(categoryIDs))
+	for i, categoryID := range categoryIDs {
+		mappings[i] = types.LinkCategoryDTO{LinkID: id, CategoryID: categoryID}
 	}
 
-	return err
+	return s.inTxn(ctx, txn, func(ctx context.Context, q *repository.Queries) error {
+		shortUrl, err := q.TouchLink(ctx, utils.ToPgUUID(id))
+		if err != nil {
+			return linkMutationError(err, nil)
+		}
+
+		if err := s.AddLinkToCategory(ctx, mappings, q); err != nil {
+			return err
+		}
+
+		linkCategories, err := s.GetCategoriesForLink(ctx, id, q)
+		if err != nil {
+			return err
+		}
+
+		return events.Publish(ctx, q, events.LinkUpdated, events.LinkChanged{ID: id, ShortURL: shortUrl}, linkCategories)
+	})
 }
 
 func (s *Store) RemoveLinkFromCategory(ctx context.Context, mappings []types.LinkCategoryDTO, txn *repository.Queries) error {
@@ -194,22 +372,42 @@ func (s *Store) RemoveLinkFromCategory(ctx context.Context, mappings []types.Lin
 
 	// Close the batch
 	if err := deleteBatch.Close(); err != nil {
-		return err
+		return errs.TranslatePgError(err)
 	}
 
-	return batchErr
+	return errs.TranslatePgError(batchErr)
 }
 
-func (s *Store) DeleteLinkByID(ctx context.Context, id string, txn *repository.Queries) error {
-	if txn == nil {
-		txn = s.db
+func (s *Store) DeleteLinkByID(ctx context.Context, id string, version *int32, txn *repository.Queries) error {
+	args := repository.DeleteLinkParams{
+		ID:      utils.ToPgUUID(id),
+		Version: version,
 	}
-	rows, err := txn.DeleteLink(ctx, utils.ToPgUUID(id))
 
-	if rows == 0 {
+	return s.inTxn(ctx, txn, func(ctx context.Context, q *repository.Queries) error {
+		categoryIDs, err := s.GetCategoriesForLink(ctx, id, q)
+		if err != nil {
+			return err
+		}
+
+		shortUrl, err := q.DeleteLink(ctx, args)
+		if err != nil {
+			return linkMutationError(err, version)
+		}
+
+		return events.Publish(ctx, q, events.LinkDeleted, events.LinkChanged{ID: id, ShortURL: shortUrl}, categoryIDs)
+	})
+}
+
+func linkMutationError(err error, version *int32) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Link was modified since the given version
+		if version != nil {
+			return errs.ErrPreconditionFailed
+		}
 		// Link does not exists in the database
 		return errs.ErrLinkNotFound
 	}
 
-	return err
+	return errs.TranslatePgError(err)
 }
@@ -0,0 +1,289 @@
+package links
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/OmprakashD20/refero-api/apitest"
+	"github.com/OmprakashD20/refero-api/database"
+	"github.com/OmprakashD20/refero-api/database/dbtest"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func insertLink(t *testing.T, store *Store, url, shortURL string, categoryIDs ...string) string {
+	t.Helper()
+
+	id, err := store.CreateLink(context.Background(), linkPayload(url, categoryIDs...), shortURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *id
+}
+
+func TestStoreCreateLink(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	category := dbtest.InsertCategory(t, pool, "golang", "")
+	id := insertLink(t, store, "https://go.dev", "go", category)
+
+	link, err := store.GetLinkByID(ctx, id)
+	if err != nil || link == nil {
+		t.Fatalf("GetLinkByID: got %v, %v", link, err)
+	}
+	if link.Url != "https://go.dev" || link.ShortUrl != "go" || link.Version != 1 {
+		t.Errorf("GetLinkByID: got %+v", link)
+	}
+
+	existing, err := store.CheckIfLinkExistsByURL(ctx, "https://go.dev", nil)
+	if err != nil || existing == nil || *existing != id {
+		t.Errorf("CheckIfLinkExistsByURL: got %v, %v, want %s", existing, err, id)
+	}
+	if missing, err := store.CheckIfLinkExistsByURL(ctx, "https://example.com", nil); err != nil || missing != nil {
+		t.Errorf("CheckIfLinkExistsByURL of a missing link: got %v, %v", missing, err)
+	}
+
+	// Short URLs are unique
+	_, err = store.CreateLink(ctx, linkPayload("https://pkg.go.dev"), "go", nil)
+	if code := apitest.ErrorCode(err); code != "duplicate_value" {
+		t.Errorf("CreateLink with a duplicate short URL: got %v, want duplicate_value", err)
+	}
+
+	if got := dbtest.PublishedEvents(t, pool); !slices.Equal(got, []string{events.LinkCreated}) {
+		t.Errorf("published %v, want a single %s", got, events.LinkCreated)
+	}
+}
+
+func TestStoreCreateLinkInTransaction(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+	rollback := errors.New("rollback")
+
+	// A mutation given a transaction runs in it, along with its event
+	err := database.NewTransactionStore(pool).Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+		if _, err := store.CreateLink(ctx, linkPayload("https://go.dev"), "go", q); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("got %v, want the rollback", err)
+	}
+
+	if link, err := store.GetLinkByShortURL(ctx, "go", nil); err != nil || link != nil {
+		t.Errorf("GetLinkByShortURL after the rollback: got %+v, %v", link, err)
+	}
+	if got := dbtest.PublishedEvents(t, pool); len(got) != 0 {
+		t.Errorf("published %v after the rollback", got)
+	}
+}
+
+func TestStoreListLinks(t *testing.T) {
+	store, _ := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	first := insertLink(t, store, "https://go.dev", "go")
+	second := insertLink(t, store, "https://pkg.go.dev", "pkg")
+	third := insertLink(t, store, "https://www.rust-lang.org/100%", "rust")
+	trashed := insertLink(t, store, "https://golang.org", "golang")
+	if err := store.DeleteLinkByID(ctx, trashed, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := store.GetAllLinks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("GetAllLinks: got %d links, want 3", len(all))
+	}
+
+	byIDs, err := store.GetLinksByIDs(ctx, []string{second, trashed, apitest.UnknownID, "not-a-uuid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byIDs) != 1 || byIDs[0].ID != second {
+		t.Errorf("GetLinksByIDs: got %+v", byIDs)
+	}
+
+	// Each link is created in a transaction of its own, so the newest comes first
+	tests := []struct {
+		search        string
+		limit, offset int32
+		want          []string
+	}{
+		{"", 0, 0, []string{third, second, first}},
+		{"GO.DEV", 0, 0, []string{second, first}},
+		{"", 1, 1, []string{second}},
+		{"", 0, 3, nil},
+		{"100%", 0, 0, []string{third}},
+		{"_", 0, 0, nil}, // Wildcards match literally
+		{"golang.org", 0, 0, nil},
+	}
+	for _, tt := range tests {
+		links, err := store.SearchLinks(ctx, tt.search, tt.limit, tt.offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, len(links))
+		for i, link := range links {
+			got[i] = link.ID
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SearchLinks(%q, %d, %d): got %v, want %v", tt.search, tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestStoreLinkCategories(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	golang := dbtest.InsertCategory(t, pool, "golang", "")
+	tools := dbtest.InsertCategory(t, pool, "tools", "")
+	link := insertLink(t, store, "https://go.dev/gopls", "gopls")
+
+	mappings := []types.LinkCategoryDTO{{LinkID: link, CategoryID: golang}, {LinkID: link, CategoryID: tools}}
+	if err := store.AddLinkToCategory(ctx, mappings, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	categories, err := store.GetCategoriesForLink(ctx, link, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(categories)
+	want := []string{golang, tools}
+	slices.Sort(want)
+	if !slices.Equal(categories, want) {
+		t.Errorf("GetCategoriesForLink: got %v, want %v", categories, want)
+	}
+
+	byLink, err := store.GetCategoriesForLinks(ctx, []string{link, apitest.UnknownID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byLink) != 1 || len(byLink[link]) != 2 {
+		t.Errorf("GetCategoriesForLinks: got %+v", byLink)
+	}
+
+	err = store.AddLinkToCategory(ctx, mappings[:1], nil)
+	if code := apitest.ErrorCode(err); code != "duplicate_value" {
+		t.Errorf("AddLinkToCategory of an existing mapping: got %v, want duplicate_value", err)
+	}
+	err = store.AddLinkToCategory(ctx, []types.LinkCategoryDTO{{LinkID: link, CategoryID: apitest.UnknownID}}, nil)
+	if code := apitest.ErrorCode(err); code != "invalid_reference" {
+		t.Errorf("AddLinkToCategory of a missing category: got %v, want invalid_reference", err)
+	}
+	trashed := dbtest.InsertCategory(t, pool, "trashed", "")
+	dbtest.Exec(t, pool, `UPDATE category SET deleted_at = now() WHERE id = $1`, trashed)
+	err = store.AddLinkToCategory(ctx, []types.LinkCategoryDTO{{LinkID: link, CategoryID: trashed}}, nil)
+	if code := apitest.ErrorCode(err); code != "invalid_reference" {
+		t.Errorf("AddLinkToCategory of a trashed category: got %v, want invalid_reference", err)
+	}
+
+	if err := store.RemoveLinkFromCategory(ctx, mappings, nil); err != nil {
+		t.Fatal(err)
+	}
+	if categories, err := store.GetCategoriesForLink(ctx, link, nil); err != nil || len(categories) != 0 {
+		t.Errorf("GetCategoriesForLink after the removal: got %v, %v", categories, err)
+	}
+
+	// Adding the link to categories through the link bumps its version and publishes the update
+	if err := store.AddCategoriesToLink(ctx, link, []string{golang}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.GetLinkByID(ctx, link); err != nil || got == nil || got.Version != 2 {
+		t.Errorf("GetLinkByID after adding categories: got %+v, %v, want version 2", got, err)
+	}
+	if err := store.AddCategoriesToLink(ctx, apitest.UnknownID, []string{golang}, nil); !errors.Is(err, errs.ErrLinkNotFound) {
+		t.Errorf("AddCategoriesToLink of a missing link: got %v, want %v", err, errs.ErrLinkNotFound)
+	}
+
+	want = []string{events.LinkCreated, events.LinkUpdated}
+	if got := dbtest.PublishedEvents(t, pool); !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+func TestStoreUpdateLink(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	category := dbtest.InsertCategory(t, pool, "golang", "")
+	id := insertLink(t, store, "https://go.dev", "go")
+	insertLink(t, store, "https://pkg.go.dev", "pkg")
+	stale, current := int32(2), int32(1)
+
+	update := linkPayload("https://go.dev/doc", category)
+	if err := store.UpdateLinkByID(ctx, id, update, &stale, nil); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Errorf("UpdateLinkByID at a stale version: got %v, want %v", err, errs.ErrPreconditionFailed)
+	}
+	if err := store.UpdateLinkByID(ctx, id, update, &current, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := store.GetLinkByShortURL(ctx, "go", nil)
+	if err != nil || link == nil || link.Url != "https://go.dev/doc" {
+		t.Errorf("GetLinkByShortURL after the update: got %+v, %v", link, err)
+	}
+
+	// URLs are unique
+	err = store.UpdateLinkByID(ctx, id, linkPayload("https://pkg.go.dev"), nil, nil)
+	if code := apitest.ErrorCode(err); code != "duplicate_value" {
+		t.Errorf("UpdateLinkByID with a duplicate URL: got %v, want duplicate_value", err)
+	}
+
+	err = store.UpdateLinkByID(ctx, apitest.UnknownID, update, nil, nil)
+	if !errors.Is(err, errs.ErrLinkNotFound) {
+		t.Errorf("UpdateLinkByID of a missing link: got %v, want %v", err, errs.ErrLinkNotFound)
+	}
+
+	want := []string{events.LinkCreated, events.LinkCreated, events.LinkUpdated}
+	if got := dbtest.PublishedEvents(t, pool); !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+func TestStoreDeleteLink(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	id := insertLink(t, store, "https://go.dev", "go")
+	stale := int32(2)
+
+	if err := store.DeleteLinkByID(ctx, id, &stale, nil); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Errorf("DeleteLinkByID at a stale version: got %v, want %v", err, errs.ErrPreconditionFailed)
+	}
+	if err := store.DeleteLinkByID(ctx, id, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteLinkByID(ctx, id, nil, nil); !errors.Is(err, errs.ErrLinkNotFound) {
+		t.Errorf("DeleteLinkByID of a trashed link: got %v, want %v", err, errs.ErrLinkNotFound)
+	}
+
+	if link, err := store.GetLinkByID(ctx, id); err != nil || link != nil {
+		t.Errorf("GetLinkByID of a trashed link: got %+v, %v", link, err)
+	}
+
+	// The URL of a trashed link can be saved again, but not its short URL
+	insertLink(t, store, "https://go.dev", "go-again")
+	_, err := store.CreateLink(ctx, linkPayload("https://go.dev/doc"), "go", nil)
+	if code := apitest.ErrorCode(err); code != "duplicate_value" {
+		t.Errorf("CreateLink with the short URL of a trashed link: got %v, want duplicate_value", err)
+	}
+
+	want := []string{events.LinkCreated, events.LinkDeleted, events.LinkCreated}
+	if got := dbtest.PublishedEvents(t, pool); !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
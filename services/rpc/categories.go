@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	referov1 "github.com/OmprakashD20/refero-api/gen/refero/v1"
+	"github.com/OmprakashD20/refero-api/gen/refero/v1/referov1connect"
+	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// CategoryServer serves the CategoryService RPCs with the same service and store as the REST handlers.
+type CategoryServer struct {
+	service *category.CategoryService
+	store   types.CategoryStore
+}
+
+var _ referov1connect.CategoryServiceHandler = (*CategoryServer)(nil)
+
+func NewCategoryServer(service *category.CategoryService, store types.CategoryStore) *CategoryServer {
+	return &CategoryServer{service, store}
+}
+
+func (s *CategoryServer) CreateCategory(ctx context.Context, req *connect.Request[referov1.CreateCategoryRequest]) (*connect.Response[referov1.CreateCategoryResponse], error) {
+	payload := validator.CreateCategoryPayload{
+		Name:        req.Msg.Name,
+		Description: req.Msg.Description,
+		ParentId:    req.Msg.GetParentId(),
+	}
+	if err := validator.Validate(payload); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.CreateCategory(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	// Names are unique, and the store doesn't return the ID of the new category
+	created, err := s.store.GetCategoryByName(ctx, payload.Name)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if created == nil {
+		return nil, errs.NotFound(errs.ErrCategoryNotFound)
+	}
+
+	category, err := s.getCategory(ctx, created.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&referov1.CreateCategoryResponse{Category: toCategoryProto(category)}), nil
+}
+
+func (s *CategoryServer) GetCategory(ctx context.Context, req *connect.Request[referov1.GetCategoryRequest]) (*connect.Response[referov1.GetCategoryResponse], error) {
+	if err := validator.Validate(validator.GetCategoryByIDParam{ID: req.Msg.Id}); err != nil {
+		return nil, err
+	}
+
+	category, err := s.getCategory(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&referov1.GetCategoryResponse{Category: toCategoryProto(category)}), nil
+}
+
+func (s *CategoryServer) ListCategories(ctx context.Context, req *connect.Request[referov1.ListCategoriesRequest]) (*connect.Response[referov1.ListCategoriesResponse], error) {
+	categories, err := s.store.GetAllCategories(ctx)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+
+	return connect.NewResponse(&referov1.ListCategoriesResponse{Categories: toCategoryProtos(categories)}), nil
+}
+
+func (s *CategoryServer) ListCategoryLinks(ctx context.Context, req *connect.Request[referov1.ListCategoryLinksRequest]) (*connect.Response[referov1.ListCategoryLinksResponse], error) {
+	if err := validator.Validate(validator.GetLinksForCategoryParams{ID: req.Msg.Id}); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.store.CheckIfCategoryExistsByID(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if !exists {
+		return nil, errs.NotFound(errs.ErrCategoryNotFound)
+	}
+
+	links, err := s.store.GetLinksForCategory(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+
+	return connect.NewResponse(&referov1.ListCategoryLinksResponse{Links: toLinkProtos(links)}), nil
+}
+
+func (s *CategoryServer) UpdateCategory(ctx context.Context, req *connect.Request[referov1.UpdateCategoryRequest]) (*connect.Response[referov1.UpdateCategoryResponse], error) {
+	payload := validator.UpdateCategoryPayload{
+		Name:        req.Msg.Name,
+		Description: req.Msg.Description,
+		ParentId:    req.Msg.GetParentId(),
+	}
+	if err := validator.Validate(validator.UpdateCategoryByIDParam{ID: req.Msg.Id}, payload); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.UpdateCategory(ctx, req.Msg.Id, payload, req.Msg.Version); err != nil {
+		return nil, err
+	}
+
+	category, err := s.getCategory(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&referov1.UpdateCategoryResponse{Category: toCategoryProto(category)}), nil
+}
+
+func (s *CategoryServer) DeleteCategory(ctx context.Context, req *connect.Request[referov1.DeleteCategoryRequest]) (*connect.Response[referov1.DeleteCategoryResponse], error) {
+	if err := validator.Validate(validator.DeleteCategoryByIDParam{ID: req.Msg.Id}); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteCategory(ctx, req.Msg.Id, req.Msg.Version); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&referov1.DeleteCategoryResponse{}), nil
+}
+
+func (s *CategoryServer) getCategory(ctx context.Context, id string) (*types.CategoryDTO, error) {
+	category, err := s.store.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if category == nil {
+		return nil, errs.NotFound(errs.ErrCategoryNotFound)
+	}
+	return category, nil
+}
@@ -0,0 +1,58 @@
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	referov1 "github.com/OmprakashD20/refero-api/gen/refero/v1"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+func toLinkProto(link *types.LinkDTO) *referov1.Link {
+	return &referov1.Link{
+		Id:          link.ID,
+		Url:         link.Url,
+		Title:       link.Title,
+		Description: link.Description,
+		ShortUrl:    link.ShortUrl,
+		Version:     link.Version,
+		CreateTime:  toTimestamp(link.CreatedAt),
+		UpdateTime:  toTimestamp(link.UpdatedAt),
+	}
+}
+
+func toLinkProtos(links []types.LinkDTO) []*referov1.Link {
+	messages := make([]*referov1.Link, len(links))
+	for i := range links {
+		messages[i] = toLinkProto(&links[i])
+	}
+	return messages
+}
+
+func toCategoryProto(category *types.CategoryDTO) *referov1.Category {
+	return &referov1.Category{
+		Id:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+		ParentId:    category.ParentID,
+		Version:     category.Version,
+		CreateTime:  toTimestamp(category.CreatedAt),
+		UpdateTime:  toTimestamp(category.UpdatedAt),
+	}
+}
+
+func toCategoryProtos(categories []types.CategoryDTO) []*referov1.Category {
+	messages := make([]*referov1.Category, len(categories))
+	for i := range categories {
+		messages[i] = toCategoryProto(&categories[i])
+	}
+	return messages
+}
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
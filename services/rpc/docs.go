@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	referov1 "github.com/OmprakashD20/refero-api/gen/refero/v1"
+	"github.com/OmprakashD20/refero-api/openapi"
+)
+
+// Docs describes the RPC routes for the OpenAPI document. The messages are defined by the
+// services in proto/, which are best called with a gRPC or Connect client generated from them.
+var Docs = slices.Concat(
+	serviceDocs(referov1.File_refero_v1_links_proto.Services().ByName("LinkService")),
+	serviceDocs(referov1.File_refero_v1_categories_proto.Services().ByName("CategoryService")),
+)
+
+// connectError is the body of the errors returned to Connect clients.
+type connectError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"` // Base64 encoded protobuf message
+	} `json:"details,omitempty"`
+}
+
+func serviceDocs(service protoreflect.ServiceDescriptor) []openapi.Route {
+	// Only the procedures without side effects can be called with GET
+	var procedures, queries []string
+	methods := service.Methods()
+	for i := range methods.Len() {
+		method := methods.Get(i)
+		procedures = append(procedures, string(method.Name()))
+		if method.Options().(*descriptorpb.MethodOptions).GetIdempotencyLevel() == descriptorpb.MethodOptions_NO_SIDE_EFFECTS {
+			queries = append(queries, string(method.Name()))
+		}
+	}
+
+	path := "/" + string(service.FullName()) + "/*procedure"
+	description := "Requests are authenticated with a bearer token, and served over gRPC, gRPC-Web and the Connect protocol."
+	message := openapi.Content{
+		"application/json":  &openapi.Schema{Type: "object", Description: "Message as protobuf JSON"},
+		"application/proto": &openapi.Schema{Type: "string", Format: "binary"},
+	}
+	responses := []openapi.Response{
+		{Status: http.StatusOK, Body: message},
+		{Description: "Connect error", Body: connectError{}},
+	}
+
+	return []openapi.Route{
+		{
+			Method:      http.MethodPost,
+			Path:        path,
+			OperationID: "call" + string(service.Name()),
+			Summary:     "Call a procedure of " + string(service.FullName()),
+			Description: "Procedures: " + strings.Join(procedures, ", ") + ". " + description,
+			Headers:     []openapi.Header{authorization},
+			Body:        message,
+			Responses:   responses,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        path,
+			OperationID: "get" + string(service.Name()),
+			Summary:     "Call a procedure of " + string(service.FullName()) + " without side effects",
+			Description: "Procedures: " + strings.Join(queries, ", ") + ". " + description +
+				" The message is sent in the query, as defined by the Connect protocol.",
+			Headers:   []openapi.Header{authorization},
+			Responses: responses,
+		},
+	}
+}
+
+var authorization = openapi.Header{Name: "Authorization", Description: "Bearer token of the calling service"}
@@ -0,0 +1,175 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/logging"
+)
+
+// errorDomain qualifies the reasons of the ErrorInfo details attached to errors.
+const errorDomain = "refero"
+
+// Token authenticates an internal service calling the RPC API.
+type Token struct {
+	Name   string // Identifies the caller in the logs
+	Secret string
+}
+
+// authInterceptor lets through callers sending one of the tokens as a bearer token.
+func authInterceptor(tokens []Token) connect.UnaryInterceptorFunc {
+	// Compare digests, so the comparison takes as long whatever the length of the secrets
+	sums := make([][sha256.Size]byte, len(tokens))
+	for i, token := range tokens {
+		sums[i] = sha256.Sum256([]byte(token.Secret))
+	}
+
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			secret, ok := strings.CutPrefix(req.Header().Get("Authorization"), "Bearer ")
+			if !ok || secret == "" {
+				return nil, errs.Unauthorized(errs.ErrUnauthenticated)
+			}
+
+			sum := sha256.Sum256([]byte(secret))
+			caller := ""
+			for i, token := range tokens {
+				if subtle.ConstantTimeCompare(sum[:], sums[i][:]) == 1 {
+					caller = token.Name
+				}
+			}
+			if caller == "" {
+				return nil, errs.Unauthorized(errs.ErrUnauthenticated)
+			}
+
+			return next(logging.WithUserID(ctx, "service:"+caller), req)
+		}
+	}
+}
+
+// errorInterceptor converts the HTTPErrors returned by the services to RPC errors with the matching code.
+// The machine-readable code is attached as an ErrorInfo, and invalid fields as a BadRequest.
+// The cause of server errors is logged but never returned.
+func errorInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			res, err := next(ctx, req)
+			if err != nil {
+				return nil, toConnectError(ctx, req.Spec().Procedure, err)
+			}
+			return res, nil
+		}
+	}
+}
+
+func toConnectError(ctx context.Context, procedure string, err error) error {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr
+	}
+
+	var httpErr *errs.HTTPError
+	if !errors.As(err, &httpErr) {
+		slog.ErrorContext(ctx, "rpc failed", "procedure", procedure, "error", err)
+		httpErr = errs.InternalServerError()
+	}
+
+	httpErr = httpErr.Resolve()
+	if httpErr.Cause != nil && httpErr.StatusCode >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "rpc failed", "procedure", procedure, "error", httpErr.ErrorMsg, "cause", httpErr.Cause)
+	}
+
+	connectErr = connect.NewError(statusCode(httpErr.StatusCode), errors.New(httpErr.ErrorMsg))
+
+	info := &errdetails.ErrorInfo{
+		Reason: strings.ToUpper(httpErr.ErrorCode()),
+		Domain: errorDomain,
+	}
+	if detail, err := connect.NewErrorDetail(info); err == nil {
+		connectErr.AddDetail(detail)
+	}
+
+	if len(httpErr.Fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(httpErr.Fields))
+		for i, field := range httpErr.Fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Message}
+		}
+		if detail, err := connect.NewErrorDetail(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+			connectErr.AddDetail(detail)
+		}
+	}
+
+	return connectErr
+}
+
+// statusCode maps the status codes of HTTPErrors to RPC codes.
+func statusCode(status int) connect.Code {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusUnsupportedMediaType:
+		return connect.CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return connect.CodeUnauthenticated
+	case http.StatusForbidden:
+		return connect.CodePermissionDenied
+	case http.StatusNotFound:
+		return connect.CodeNotFound
+	case http.StatusConflict:
+		return connect.CodeAlreadyExists
+	case http.StatusPreconditionFailed:
+		// The resource changed since the caller read it, so it should read it again and retry
+		return connect.CodeAborted
+	case http.StatusTooManyRequests:
+		return connect.CodeResourceExhausted
+	case http.StatusServiceUnavailable:
+		return connect.CodeUnavailable
+	case http.StatusNotImplemented:
+		return connect.CodeUnimplemented
+	default:
+		return connect.CodeInternal
+	}
+}
+
+// loggingInterceptor logs one line per call with its outcome.
+// Server errors are logged at error level and client errors at warn level.
+func loggingInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			start := time.Now()
+
+			res, err := next(ctx, req)
+
+			attrs := []slog.Attr{
+				slog.String("procedure", req.Spec().Procedure),
+				slog.String("protocol", req.Peer().Protocol),
+				slog.Duration("latency", time.Since(start)),
+			}
+
+			level := slog.LevelInfo
+			if err != nil {
+				code := connect.CodeOf(err)
+				attrs = append(attrs, slog.String("code", code.String()), slog.String("error", err.Error()))
+
+				level = slog.LevelWarn
+				switch code {
+				case connect.CodeInternal, connect.CodeUnknown, connect.CodeUnavailable, connect.CodeDataLoss:
+					level = slog.LevelError
+				}
+			} else {
+				attrs = append(attrs, slog.String("code", "ok"))
+			}
+
+			slog.LogAttrs(ctx, level, "rpc", attrs...)
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/OmprakashD20/refero-api/apitest"
+	referov1 "github.com/OmprakashD20/refero-api/gen/refero/v1"
+	"github.com/OmprakashD20/refero-api/gen/refero/v1/referov1connect"
+	"github.com/OmprakashD20/refero-api/memstore"
+	"github.com/OmprakashD20/refero-api/services/category"
+	"github.com/OmprakashD20/refero-api/services/links"
+)
+
+// newTestClient serves the RPC routes over an in-memory store and returns a Connect client of the link service.
+func newTestClient(t *testing.T) referov1connect.LinkServiceClient {
+	t.Helper()
+
+	store := memstore.New()
+	service := NewService(
+		NewLinkServer(links.NewService(store, store), store),
+		NewCategoryServer(category.NewService(store), store),
+		ParseTokens([]string{"indexer:indexer-secret", "crawler:crawler-secret"}),
+	)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	service.SetupRPCRoutes(engine)
+
+	srv := httptest.NewServer(engine)
+	t.Cleanup(srv.Close)
+
+	return referov1connect.NewLinkServiceClient(srv.Client(), srv.URL)
+}
+
+func request[T any](msg *T, authorization string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	if authorization != "" {
+		req.Header().Set("Authorization", authorization)
+	}
+	return req
+}
+
+// expectError checks the code of the RPC error and the reason of its ErrorInfo, and returns its other details.
+func expectError(t *testing.T, err error, code connect.Code, reason string) []*connect.ErrorDetail {
+	t.Helper()
+
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("got error %v, want %v", err, code)
+	}
+	if connectErr.Code() != code {
+		t.Errorf("got code %v, want %v: %v", connectErr.Code(), code, err)
+	}
+
+	var others []*connect.ErrorDetail
+	found := false
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, ok := value.(*errdetails.ErrorInfo)
+		if !ok {
+			others = append(others, detail)
+			continue
+		}
+		found = true
+		if info.Reason != reason || info.Domain != errorDomain {
+			t.Errorf("got ErrorInfo %s/%s, want %s/%s", info.Domain, info.Reason, errorDomain, reason)
+		}
+	}
+	if !found {
+		t.Errorf("got no ErrorInfo, want reason %s", reason)
+	}
+	return others
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name          string
+		authorization string
+	}{
+		{"missing", ""},
+		{"empty", "Bearer "},
+		{"not a bearer token", "Basic aW5kZXhlcjppbmRleGVyLXNlY3JldA=="},
+		{"wrong", "Bearer wrong-secret"},
+		{"name instead of the secret", "Bearer indexer"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ListLinks(ctx, request(&referov1.ListLinksRequest{}, tt.authorization))
+			if details := expectError(t, err, connect.CodeUnauthenticated, "UNAUTHENTICATED"); len(details) != 0 {
+				t.Errorf("got %d other details, want none", len(details))
+			}
+		})
+	}
+
+	// Each of the tokens is accepted
+	for _, secret := range []string{"indexer-secret", "crawler-secret"} {
+		if _, err := client.ListLinks(ctx, request(&referov1.ListLinksRequest{}, "Bearer "+secret)); err != nil {
+			t.Errorf("ListLinks with %s: %v", secret, err)
+		}
+	}
+}
+
+func TestErrorInterceptor(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	const authorization = "Bearer indexer-secret"
+
+	t.Run("invalid argument", func(t *testing.T) {
+		_, err := client.CreateLink(ctx, request(&referov1.CreateLinkRequest{Title: "Go docs", Url: "not a url", Description: "The Go documentation"}, authorization))
+		details := expectError(t, err, connect.CodeInvalidArgument, "VALIDATION_FAILED")
+
+		if len(details) != 1 {
+			t.Fatalf("got %d other details, want a BadRequest", len(details))
+		}
+		value, err := details[0].Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		badRequest, ok := value.(*errdetails.BadRequest)
+		if !ok {
+			t.Fatalf("got detail %T, want a BadRequest", value)
+		}
+		violations := badRequest.GetFieldViolations()
+		if len(violations) != 1 || violations[0].GetField() != "url" || violations[0].GetDescription() == "" {
+			t.Errorf("got violations %v, want the url", violations)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := client.GetLink(ctx, request(&referov1.GetLinkRequest{Id: apitest.UnknownID}, authorization))
+		if details := expectError(t, err, connect.CodeNotFound, "LINK_NOT_FOUND"); len(details) != 0 {
+			t.Errorf("got %d other details, want none", len(details))
+		}
+	})
+
+	t.Run("created", func(t *testing.T) {
+		res, err := client.CreateLink(ctx, request(&referov1.CreateLinkRequest{Title: "Go docs", Url: "https://go.dev", Description: "The Go documentation"}, authorization))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Msg.GetLink().GetUrl() != "https://go.dev" {
+			t.Errorf("got link %v", res.Msg.GetLink())
+		}
+	})
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		status int
+		want   connect.Code
+	}{
+		{http.StatusBadRequest, connect.CodeInvalidArgument},
+		{http.StatusUnprocessableEntity, connect.CodeInvalidArgument},
+		{http.StatusUnsupportedMediaType, connect.CodeInvalidArgument},
+		{http.StatusUnauthorized, connect.CodeUnauthenticated},
+		{http.StatusForbidden, connect.CodePermissionDenied},
+		{http.StatusNotFound, connect.CodeNotFound},
+		{http.StatusConflict, connect.CodeAlreadyExists},
+		{http.StatusPreconditionFailed, connect.CodeAborted},
+		{http.StatusTooManyRequests, connect.CodeResourceExhausted},
+		{http.StatusServiceUnavailable, connect.CodeUnavailable},
+		{http.StatusNotImplemented, connect.CodeUnimplemented},
+		{http.StatusInternalServerError, connect.CodeInternal},
+		{http.StatusBadGateway, connect.CodeInternal},
+		{http.StatusTeapot, connect.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		if got := statusCode(tt.status); got != tt.want {
+			t.Errorf("statusCode(%d): got %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
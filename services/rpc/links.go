@@ -0,0 +1,152 @@
+package rpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	referov1 "github.com/OmprakashD20/refero-api/gen/refero/v1"
+	"github.com/OmprakashD20/refero-api/gen/refero/v1/referov1connect"
+	"github.com/OmprakashD20/refero-api/services/links"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// LinkServer serves the LinkService RPCs with the same service and store as the REST handlers.
+type LinkServer struct {
+	service *links.LinkService
+	store   types.LinkStore
+}
+
+var _ referov1connect.LinkServiceHandler = (*LinkServer)(nil)
+
+func NewLinkServer(service *links.LinkService, store types.LinkStore) *LinkServer {
+	return &LinkServer{service, store}
+}
+
+func (s *LinkServer) CreateLink(ctx context.Context, req *connect.Request[referov1.CreateLinkRequest]) (*connect.Response[referov1.CreateLinkResponse], error) {
+	link := validator.CreateLinkPayload{
+		Title:       req.Msg.Title,
+		URL:         req.Msg.Url,
+		Description: &req.Msg.Description,
+		CategoryIDs: req.Msg.CategoryIds,
+	}
+	if err := validator.Validate(link); err != nil {
+		return nil, err
+	}
+
+	id, err := s.service.CreateLink(ctx, link, "")
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.getLink(ctx, *id)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&referov1.CreateLinkResponse{Link: toLinkProto(created)}), nil
+}
+
+func (s *LinkServer) GetLink(ctx context.Context, req *connect.Request[referov1.GetLinkRequest]) (*connect.Response[referov1.GetLinkResponse], error) {
+	if err := validator.Validate(validator.GetLinkByIDParam{ID: req.Msg.Id}); err != nil {
+		return nil, err
+	}
+
+	link, err := s.getLink(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&referov1.GetLinkResponse{Link: toLinkProto(link)}), nil
+}
+
+func (s *LinkServer) ResolveShortURL(ctx context.Context, req *connect.Request[referov1.ResolveShortURLRequest]) (*connect.Response[referov1.ResolveShortURLResponse], error) {
+	if err := validator.Validate(validator.RedirectLinkParams{ShortURL: req.Msg.ShortUrl}); err != nil {
+		return nil, err
+	}
+
+	link, err := s.store.GetLinkByShortURL(ctx, req.Msg.ShortUrl, nil)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if link == nil {
+		return nil, errs.NotFound(errs.ErrLinkNotFound)
+	}
+
+	return connect.NewResponse(&referov1.ResolveShortURLResponse{Link: toLinkProto(link)}), nil
+}
+
+func (s *LinkServer) ListLinks(ctx context.Context, req *connect.Request[referov1.ListLinksRequest]) (*connect.Response[referov1.ListLinksResponse], error) {
+	links, err := s.store.GetAllLinks(ctx)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+
+	return connect.NewResponse(&referov1.ListLinksResponse{Links: toLinkProtos(links)}), nil
+}
+
+func (s *LinkServer) ListLinkCategories(ctx context.Context, req *connect.Request[referov1.ListLinkCategoriesRequest]) (*connect.Response[referov1.ListLinkCategoriesResponse], error) {
+	if err := validator.Validate(validator.GetLinkByIDParam{ID: req.Msg.Id}); err != nil {
+		return nil, err
+	}
+
+	// Tell a link without categories apart from a link that doesn't exist
+	if _, err := s.getLink(ctx, req.Msg.Id); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.store.GetCategoriesForLinks(ctx, []string{req.Msg.Id})
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+
+	return connect.NewResponse(&referov1.ListLinkCategoriesResponse{Categories: toCategoryProtos(categories[req.Msg.Id])}), nil
+}
+
+func (s *LinkServer) UpdateLink(ctx context.Context, req *connect.Request[referov1.UpdateLinkRequest]) (*connect.Response[referov1.UpdateLinkResponse], error) {
+	link := validator.UpdateLinkPayload{
+		Title:       req.Msg.Title,
+		URL:         req.Msg.Url,
+		Description: &req.Msg.Description,
+		CategoryIDs: req.Msg.CategoryIds,
+	}
+	if err := validator.Validate(validator.UpdateLinkByIDParam{ID: req.Msg.Id}, link); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.UpdateLink(ctx, req.Msg.Id, link, req.Msg.Version); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.getLink(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&referov1.UpdateLinkResponse{Link: toLinkProto(updated)}), nil
+}
+
+func (s *LinkServer) DeleteLink(ctx context.Context, req *connect.Request[referov1.DeleteLinkRequest]) (*connect.Response[referov1.DeleteLinkResponse], error) {
+	if err := validator.Validate(validator.DeleteLinkByIDParam{ID: req.Msg.Id}); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteLink(ctx, req.Msg.Id, req.Msg.Version); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&referov1.DeleteLinkResponse{}), nil
+}
+
+func (s *LinkServer) getLink(ctx context.Context, id string) (*types.LinkDTO, error) {
+	link, err := s.store.GetLinkByID(ctx, id)
+	if err != nil {
+		return nil, errs.InternalServerError(errs.WithCause(err))
+	}
+	if link == nil {
+		return nil, errs.NotFound(errs.ErrLinkNotFound)
+	}
+	return link, nil
+}
@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/gin-gonic/gin"
+
+	"github.com/OmprakashD20/refero-api/gen/refero/v1/referov1connect"
+)
+
+// maxMessageBytes bounds the size of request messages.
+const maxMessageBytes = 1 << 20
+
+type RPCService struct {
+	links      *LinkServer
+	categories *CategoryServer
+	tokens     []Token
+}
+
+func NewService(links *LinkServer, categories *CategoryServer, tokens []Token) *RPCService {
+	return &RPCService{links, categories, tokens}
+}
+
+// SetupRPCRoutes serves the services over gRPC, gRPC-Web and Connect on the HTTP port.
+// Plaintext gRPC needs HTTP/2 without TLS (h2c) to be enabled on the server.
+func (s *RPCService) SetupRPCRoutes(app *gin.Engine) {
+	opts := []connect.HandlerOption{
+		// Outermost first, so the logs see the mapped errors and failed authentication
+		connect.WithInterceptors(loggingInterceptor(), errorInterceptor(), authInterceptor(s.tokens)),
+		connect.WithReadMaxBytes(maxMessageBytes),
+	}
+
+	mount := func(path string, handler http.Handler) {
+		// Connect also accepts GET for the RPCs without side effects
+		app.POST(path+"*procedure", gin.WrapH(handler))
+		app.GET(path+"*procedure", gin.WrapH(handler))
+	}
+	mount(referov1connect.NewLinkServiceHandler(s.links, opts...))
+	mount(referov1connect.NewCategoryServiceHandler(s.categories, opts...))
+}
+
+// ParseTokens parses tokens formatted as "name:secret", skipping malformed ones.
+func ParseTokens(entries []string) []Token {
+	tokens := make([]Token, 0, len(entries))
+	for _, entry := range entries {
+		name, secret, ok := strings.Cut(entry, ":")
+		if !ok || name == "" || secret == "" {
+			continue
+		}
+		tokens = append(tokens, Token{Name: name, Secret: secret})
+	}
+	return tokens
+}
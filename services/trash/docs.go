@@ -0,0 +1,28 @@
+package trash
+
+import (
+	"net/http"
+
+	"github.com/OmprakashD20/refero-api/openapi"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// Docs describes the trash routes for the OpenAPI document.
+var Docs = []openapi.Route{
+	{
+		Method:      http.MethodGet,
+		Path:        "/",
+		OperationID: "listTrash",
+		Summary:     "List the links and categories in the trash",
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: types.TrashDTO{}}},
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/:id/restore",
+		OperationID: "restoreTrashItem",
+		Summary:     "Restore a link or category from the trash",
+		Params:      validator.RestoreTrashItemParam{},
+		Responses:   []openapi.Response{{Status: http.StatusOK}},
+	},
+}
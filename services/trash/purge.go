@@ -0,0 +1,95 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+// PurgeJob periodically deletes links and categories that have been in the trash
+// for longer than the retention period.
+type PurgeJob struct {
+	store     types.TrashStore
+	txn       types.TransactionStore
+	retention time.Duration
+	interval  time.Duration
+
+	lastRun atomic.Int64 // Unix nanoseconds of the last completed run, or of the start of Run
+}
+
+func NewPurgeJob(store types.TrashStore, txn types.TransactionStore, retention, interval time.Duration) *PurgeJob {
+	return &PurgeJob{store: store, txn: txn, retention: retention, interval: interval}
+}
+
+// Run purges the trash on every interval until the context is cancelled.
+// A non-positive retention or interval disables purging.
+func (j *PurgeJob) Run(ctx context.Context) {
+	if j.retention <= 0 || j.interval <= 0 {
+		slog.Info("trash purge job is disabled")
+		return
+	}
+
+	j.lastRun.Store(time.Now().UnixNano())
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PurgeJob) purge(ctx context.Context) {
+	var purged int64
+
+	err := j.txn.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+		var err error
+		purged, err = j.store.PurgeTrash(ctx, j.retention, q)
+		return err
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to purge trash", "error", err)
+		return
+	}
+	j.lastRun.Store(time.Now().UnixNano())
+
+	if purged > 0 {
+		slog.InfoContext(ctx, "purged trash", "items", purged)
+	}
+}
+
+// Check reports how far the job has fallen behind its schedule.
+// It fails once a whole interval has been missed, e.g. because purges keep failing.
+func (j *PurgeJob) Check(ctx context.Context) (any, error) {
+	if j.retention <= 0 || j.interval <= 0 {
+		return map[string]any{"enabled": false}, nil
+	}
+
+	lastRun := j.lastRun.Load()
+	if lastRun == 0 {
+		return nil, fmt.Errorf("purge job is not running")
+	}
+
+	lag := max(time.Since(time.Unix(0, lastRun))-j.interval, 0)
+	details := map[string]any{
+		"enabled": true,
+		"lastRun": time.Unix(0, lastRun).UTC(),
+		"lag":     lag.Round(time.Millisecond).String(),
+	}
+	if lag > j.interval {
+		return details, fmt.Errorf("purge job is %s behind schedule", lag.Round(time.Second))
+	}
+
+	return details, nil
+}
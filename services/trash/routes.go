@@ -0,0 +1,83 @@
+package trash
+
+import (
+	"errors"
+	"net/http"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashService struct {
+	store types.TrashStore
+}
+
+func NewService(store types.TrashStore) *TrashService {
+	return &TrashService{store}
+}
+
+func (s *TrashService) SetupTrashRoutes(api *gin.RouterGroup) {
+	api.GET("/", s.GetTrashHandler)
+
+	api.POST("/:id/restore", validator.ValidateParams[validator.RestoreTrashItemParam](), s.RestoreTrashItemHandler)
+}
+
+func (s *TrashService) GetTrashHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get all trashed links and categories from the database
+	links, err := s.store.GetTrashedLinks(ctx)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+
+	categories, err := s.store.GetTrashedCategories(ctx)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+
+	// Nothing found in the trash
+	trash := types.TrashDTO{Links: links, Categories: categories}
+	if trash.Links == nil {
+		trash.Links = []types.LinkDTO{}
+	}
+	if trash.Categories == nil {
+		trash.Categories = []types.CategoryDTO{}
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+func (s *TrashService) RestoreTrashItemHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, ok := validator.GetValidatedData[validator.RestoreTrashItemParam](c, validator.ValidatedParamKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	// The ID can refer to either a link or a category
+	err := s.store.RestoreLink(ctx, params.ID)
+	if errors.Is(err, errs.ErrTrashItemNotFound) {
+		err = s.store.RestoreCategory(ctx, params.ID)
+	}
+
+	if err != nil {
+		// If neither a link nor a category is in the trash
+		if errors.Is(err, errs.ErrTrashItemNotFound) {
+			c.Error(errs.NotFound(errs.ErrTrashItemNotFound))
+			return
+		}
+
+		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToRestore), errs.WithCause(err)))
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,165 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/database"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+	"github.com/OmprakashD20/refero-api/utils"
+)
+
+type Store struct {
+	conn *pgxpool.Pool
+	db   *repository.Queries
+	read *repository.Queries // Read-only queries that tolerate replication lag
+	txns *database.Store
+}
+
+func NewStore(conn *pgxpool.Pool) *Store {
+	db := repository.New(conn)
+	return &Store{conn: conn, db: db, read: db, txns: database.NewTransactionStore(conn)}
+}
+
+// WithReplica sends the listings of trashed links and categories to the replica pool.
+// Restores and purges always run on the primary.
+func (s *Store) WithReplica(replica *pgxpool.Pool) *Store {
+	if replica != nil {
+		s.read = repository.New(replica)
+	}
+	return s
+}
+
+func (s *Store) GetTrashedLinks(ctx context.Context) ([]types.LinkDTO, error) {
+	data, err := s.read.GetTrashedLinks(ctx)
+	if err != nil {
+		return errs.IsErrNoRows[[]types.LinkDTO](err, nil)
+	}
+
+	links := make([]types.LinkDTO, len(data))
+	for i, link := range data {
+		links[i] = types.LinkDTO{
+			ID:          link.ID.String(),
+			Title:       link.Title,
+			Description: link.Description,
+			Url:         link.Url,
+			ShortUrl:    link.ShortUrl,
+			Version:     link.Version,
+			CreatedAt:   &link.CreatedAt.Time,
+			UpdatedAt:   &link.UpdatedAt.Time,
+			DeletedAt:   &link.DeletedAt.Time,
+		}
+	}
+
+	return links, nil
+}
+
+func (s *Store) GetTrashedCategories(ctx context.Context) ([]types.CategoryDTO, error) {
+	data, err := s.read.GetTrashedCategories(ctx)
+	if err != nil {
+		return errs.IsErrNoRows[[]types.CategoryDTO](err, nil)
+	}
+
+	categories := make([]types.CategoryDTO, len(data))
+	for i, category := range data {
+		categories[i] = types.CategoryDTO{
+			ID:          category.ID.String(),
+			Name:        category.Name,
+			Description: category.Description,
+			ParentID:    utils.PgUUIDToStringPtr(category.ParentID),
+			Version:     category.Version,
+			CreatedAt:   &category.CreatedAt.Time,
+			UpdatedAt:   &category.UpdatedAt.Time,
+			DeletedAt:   &category.DeletedAt.Time,
+		}
+	}
+
+	return categories, nil
+}
+
+// RestoreLink takes the link out of the trash. A restored link is published as created,
+// so caches drop the miss they may hold for its short URL and subscribers learn it is back.
+func (s *Store) RestoreLink(ctx context.Context, id string) error {
+	return s.txns.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+		shortURL, err := q.RestoreLink(ctx, utils.ToPgUUID(id))
+		if err != nil {
+			// Link is not in the trash
+			if _, err := errs.IsErrNoRows(err, false); err != nil {
+				return err
+			}
+			return errs.ErrTrashItemNotFound
+		}
+
+		categories, err := q.GetCategoriesForLink(ctx, utils.ToPgUUID(id))
+		if err != nil {
+			return err
+		}
+		categoryIDs := make([]string, len(categories))
+		for i, category := range categories {
+			categoryIDs[i] = category.ID.String()
+		}
+
+		return events.Publish(ctx, q, events.LinkCreated, events.LinkChanged{ID: id, ShortURL: shortURL}, categoryIDs)
+	})
+}
+
+// RestoreCategory takes the category out of the trash, along with the subcategories trashed with it.
+// Each restored category is published as created.
+func (s *Store) RestoreCategory(ctx context.Context, id string) error {
+	return s.txns.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+		restored, err := q.RestoreCategory(ctx, utils.ToPgUUID(id))
+		if err != nil {
+			return errs.TranslatePgError(err)
+		}
+		if len(restored) == 0 {
+			// Category is not in the trash, or its parent category still is
+			return errs.ErrTrashItemNotFound
+		}
+
+		for _, category := range restored {
+			categoryID := category.ID.String()
+			categoryIDs := []string{categoryID}
+			if category.ParentID.Valid {
+				categoryIDs = append(categoryIDs, category.ParentID.String())
+			}
+
+			if err := events.Publish(ctx, q, events.CategoryCreated, events.CategoryChanged{ID: categoryID}, categoryIDs); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PurgeTrash permanently deletes the items that have been in the trash for longer than retention,
+// in the given transaction, or in a new one if there is none. No events are published, as the items
+// were already published as deleted when they were trashed.
+func (s *Store) PurgeTrash(ctx context.Context, retention time.Duration, txn *repository.Queries) (int64, error) {
+	if txn == nil {
+		var purged int64
+		err := s.txns.Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+			var err error
+			purged, err = s.PurgeTrash(ctx, retention, q)
+			return err
+		})
+		return purged, err
+	}
+	seconds := int64(retention.Seconds())
+
+	links, err := txn.PurgeTrashedLinks(ctx, seconds)
+	if err != nil {
+		return 0, errs.TranslatePgError(err)
+	}
+
+	categories, err := txn.PurgeTrashedCategories(ctx, seconds)
+	if err != nil {
+		return 0, errs.TranslatePgError(err)
+	}
+
+	return links + categories, nil
+}
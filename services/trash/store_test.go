@@ -0,0 +1,100 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/OmprakashD20/refero-api/database/dbtest"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/events"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func TestStoreTrash(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	link := dbtest.InsertLink(t, pool, "https://go.dev")
+	dbtest.InsertLink(t, pool, "https://pkg.go.dev")
+	languages := dbtest.InsertCategory(t, pool, "languages", "")
+	golang := dbtest.InsertCategory(t, pool, "golang", languages)
+	dbtest.InsertCategory(t, pool, "tools", "")
+
+	dbtest.Exec(t, pool, `UPDATE links SET deleted_at = now() WHERE id = $1`, link)
+	dbtest.Exec(t, pool, `UPDATE category SET deleted_at = now() WHERE id IN ($1, $2)`, languages, golang)
+
+	links, err := store.GetTrashedLinks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links[0].ID != link || links[0].DeletedAt == nil {
+		t.Errorf("GetTrashedLinks: got %+v", links)
+	}
+
+	categories, err := store.GetTrashedCategories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != 2 {
+		t.Errorf("GetTrashedCategories: got %+v", categories)
+	}
+
+	if err := store.RestoreLink(ctx, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RestoreLink(ctx, link); !errors.Is(err, errs.ErrTrashItemNotFound) {
+		t.Errorf("RestoreLink of a link which is not in the trash: got %v, want %v", err, errs.ErrTrashItemNotFound)
+	}
+
+	// Subcategories are restored along with their parent, and not before it
+	if err := store.RestoreCategory(ctx, golang); !errors.Is(err, errs.ErrTrashItemNotFound) {
+		t.Errorf("RestoreCategory of a subcategory of a trashed category: got %v, want %v", err, errs.ErrTrashItemNotFound)
+	}
+	if err := store.RestoreCategory(ctx, languages); err != nil {
+		t.Fatal(err)
+	}
+	if categories, err := store.GetTrashedCategories(ctx); err != nil || len(categories) != 0 {
+		t.Errorf("GetTrashedCategories after the restore: got %+v, %v", categories, err)
+	}
+
+	// Restores are published as creations, once per restored category
+	want := []string{events.LinkCreated, events.CategoryCreated, events.CategoryCreated}
+	if got := dbtest.PublishedEvents(t, pool); !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+func TestStorePurgeTrash(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	old := dbtest.InsertLink(t, pool, "https://golang.org")
+	recent := dbtest.InsertLink(t, pool, "https://go.dev")
+	category := dbtest.InsertCategory(t, pool, "golang", "")
+
+	dbtest.Exec(t, pool, `UPDATE links SET deleted_at = now() - interval '2 days' WHERE id = $1`, old)
+	dbtest.Exec(t, pool, `UPDATE links SET deleted_at = now() WHERE id = $1`, recent)
+	dbtest.Exec(t, pool, `UPDATE category SET deleted_at = now() - interval '3 days' WHERE id = $1`, category)
+
+	purged, err := store.PurgeTrash(ctx, 24*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 2 {
+		t.Errorf("PurgeTrash purged %d items, want 2", purged)
+	}
+
+	links, err := store.GetTrashedLinks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links[0].ID != recent {
+		t.Errorf("GetTrashedLinks after the purge: got %+v", links)
+	}
+}
@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var errPrivateAddress = errors.New("webhook resolves to a private address")
+
+// reservedPrefixes are the non-public ranges not covered by netip.Addr.IsPrivate
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),  // Shared address space of carrier-grade NAT
+	netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking, often used for internal networks
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which reaches any IPv4 address through the gateway
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64
+}
+
+// NewHTTPClient creates the client webhooks are delivered with. Redirects are not followed, and
+// unless private networks are allowed, connections to non-public addresses are refused. The address
+// is checked when dialing, after DNS resolution, so a hostname can't be pointed at the internal
+// network once the webhook is registered.
+func NewHTTPClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublic(addrPort.Addr()) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would be dialed instead of the webhook, bypassing the address check
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
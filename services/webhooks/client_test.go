@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.215.14", true},
+		{"8.8.8.8", true},
+		{"2606:4700::6810:85e5", true},
+		{"::ffff:93.184.215.14", true},
+
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false}, // Cloud metadata endpoints
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.1.2.3", false},
+
+		// Carrier-grade NAT
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+
+		// Benchmarking
+		{"198.18.0.1", false},
+		{"198.19.255.254", false},
+		{"198.17.255.254", true},
+		{"198.20.0.1", true},
+
+		// NAT64 reaches the embedded IPv4 address, public or not
+		{"64:ff9b::7f00:1", false},
+		{"64:ff9b::5db8:d70e", false},
+		{"64:ff9b:1::a01:203", false},
+		{"64:ff9c::1", true},
+	}
+
+	for _, tt := range tests {
+		if got := isPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("isPublic(%s): got %t, want %t", tt.addr, got, tt.want)
+		}
+	}
+}
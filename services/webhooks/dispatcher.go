@@ -0,0 +1,234 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/OmprakashD20/refero-api/events"
+	"github.com/OmprakashD20/refero-api/metrics"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+const (
+	// maxResponseBody bounds the part of the response kept in the delivery log
+	maxResponseBody = 1024
+	// purgeInterval is how often the delivery log is trimmed
+	purgeInterval = time.Hour
+)
+
+// Queue is the part of the store the dispatcher works through.
+type Queue interface {
+	ClaimDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]repository.ClaimWebhookDeliveriesRow, error)
+	RecordAttempt(ctx context.Context, attempt Attempt, maxFailures int) (disabled bool, err error)
+	PurgeDeliveries(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// Attempt is the outcome of a delivery attempt.
+type Attempt struct {
+	DeliveryID     pgtype.UUID
+	WebhookID      pgtype.UUID
+	Status         string
+	RetryIn        time.Duration // Until the next attempt, if the delivery is still pending
+	ResponseStatus *int32
+	ResponseBody   *string
+	Error          *string
+}
+
+type Options struct {
+	Workers        int
+	Timeout        time.Duration
+	PollInterval   time.Duration
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	DisableAfter   int // Consecutive failed attempts before a webhook is disabled
+	LogRetention   time.Duration
+}
+
+// Dispatcher delivers the queued webhook deliveries. Deliveries are leased from the queue,
+// so several instances can run a dispatcher without attempting the same delivery twice.
+type Dispatcher struct {
+	queue  Queue
+	client *http.Client
+	opts   Options
+	wake   chan struct{}
+}
+
+func NewDispatcher(queue Queue, client *http.Client, opts Options) *Dispatcher {
+	return &Dispatcher{queue: queue, client: client, opts: opts, wake: make(chan struct{}, opts.Workers)}
+}
+
+// Wake makes idle workers look for due deliveries right away, instead of on their next poll.
+// It is subscribed to the events, which queue deliveries in the transaction that publishes them.
+func (d *Dispatcher) Wake(ctx context.Context, event events.Envelope) {
+	for range d.opts.Workers {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+			return
+		}
+	}
+}
+
+// Run delivers webhooks until the context is cancelled. Attempts in progress are completed.
+func (d *Dispatcher) Run(ctx context.Context) {
+	done := make(chan struct{})
+	for range d.opts.Workers {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			d.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for running := d.opts.Workers; running > 0; {
+		select {
+		case <-done:
+			running--
+		case <-ticker.C:
+			if purged, err := d.queue.PurgeDeliveries(ctx, d.opts.LogRetention); err != nil {
+				slog.ErrorContext(ctx, "failed to purge webhook deliveries", "error", err)
+			} else if purged > 0 {
+				slog.InfoContext(ctx, "purged webhook deliveries", "count", purged)
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	// Attempts that outlast the lease could be repeated by another instance
+	lease := 2*d.opts.Timeout + time.Minute
+
+	for {
+		deliveries, err := d.queue.ClaimDeliveries(ctx, 1, lease)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to claim webhook deliveries", "error", err)
+		}
+
+		if len(deliveries) > 0 {
+			// Let the attempt finish on shutdown, so it is recorded
+			d.deliver(context.WithoutCancel(ctx), deliveries[0])
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-time.After(d.opts.PollInterval):
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery repository.ClaimWebhookDeliveriesRow) {
+	attempt := d.send(ctx, delivery)
+
+	attempts := int(delivery.Attempts) + 1
+	switch {
+	case attempt.Status == StatusSucceeded:
+		metrics.WebhookDeliveries.WithLabelValues(StatusSucceeded).Inc()
+	case attempts >= d.opts.MaxAttempts:
+		attempt.Status = StatusFailed
+		metrics.WebhookDeliveries.WithLabelValues(StatusFailed).Inc()
+	default:
+		attempt.Status = StatusPending
+		attempt.RetryIn = d.backoff(attempts)
+		metrics.WebhookDeliveries.WithLabelValues("retrying").Inc()
+	}
+
+	log := slog.With("webhook_id", delivery.WebhookID.String(), "delivery_id", delivery.ID.String(), "attempt", attempts)
+	if attempt.Error != nil {
+		log.WarnContext(ctx, "webhook delivery failed", "status", attempt.Status, "retry_in", attempt.RetryIn, "error", *attempt.Error)
+	}
+
+	disabled, err := d.queue.RecordAttempt(ctx, attempt, d.opts.DisableAfter)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to record webhook delivery", "error", err)
+		return
+	}
+	if disabled {
+		log.WarnContext(ctx, "webhook disabled after repeated failures", "failures", d.opts.DisableAfter)
+	}
+}
+
+// send posts the signed event to the webhook. The attempt succeeds on any 2xx response.
+func (d *Dispatcher) send(ctx context.Context, delivery repository.ClaimWebhookDeliveriesRow) Attempt {
+	attempt := Attempt{DeliveryID: delivery.ID, WebhookID: delivery.WebhookID}
+	fail := func(err error) Attempt {
+		msg := err.Error()
+		attempt.Error = &msg
+		return attempt
+	}
+
+	body, err := json.Marshal(types.WebhookEventDTO{
+		ID:         delivery.EventID,
+		Type:       delivery.EventType,
+		DeliveryID: delivery.ID.String(),
+		CreatedAt:  delivery.CreatedAt.Time,
+		Data:       delivery.Payload,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return fail(err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Refero-Webhooks")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	status := int32(resp.StatusCode)
+	// Postgres text must be valid UTF-8 without NUL bytes
+	text := strings.ReplaceAll(strings.ToValidUTF8(string(responseBody), "\uFFFD"), "\x00", "")
+	attempt.ResponseStatus, attempt.ResponseBody = &status, &text
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fail(fmt.Errorf("webhook responded with %s", resp.Status))
+	}
+
+	attempt.Status = StatusSucceeded
+	return attempt
+}
+
+// backoff doubles the delay after every failed attempt, up to the maximum. Half of it is
+// randomized, so that deliveries failing together don't all retry at the same moment.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.InitialBackoff
+	for i := 1; i < attempts && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, d.opts.MaxBackoff)
+
+	return delay/2 + rand.N(delay/2+1)
+}
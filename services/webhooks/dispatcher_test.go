@@ -0,0 +1,212 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+)
+
+// fakeQueue records the attempts of the dispatcher.
+type fakeQueue struct {
+	mu       sync.Mutex
+	attempts []Attempt
+}
+
+func (q *fakeQueue) ClaimDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]repository.ClaimWebhookDeliveriesRow, error) {
+	return nil, nil
+}
+
+func (q *fakeQueue) RecordAttempt(ctx context.Context, attempt Attempt, maxFailures int) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.attempts = append(q.attempts, attempt)
+	return false, nil
+}
+
+func (q *fakeQueue) PurgeDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	return 0, nil
+}
+
+var testOptions = Options{
+	Workers:        1,
+	Timeout:        5 * time.Second,
+	PollInterval:   time.Second,
+	MaxAttempts:    3,
+	InitialBackoff: time.Minute,
+	MaxBackoff:     time.Hour,
+	DisableAfter:   10,
+	LogRetention:   time.Hour,
+}
+
+func testDelivery(t *testing.T, url string, attempts int32) repository.ClaimWebhookDeliveriesRow {
+	t.Helper()
+
+	var id, webhookID pgtype.UUID
+	if err := id.Scan("6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f"); err != nil {
+		t.Fatal(err)
+	}
+	if err := webhookID.Scan("0a1b2c3d-4e5f-4a6b-8c7d-8e9f0a1b2c3d"); err != nil {
+		t.Fatal(err)
+	}
+
+	return repository.ClaimWebhookDeliveriesRow{
+		ID:        id,
+		WebhookID: webhookID,
+		EventID:   42,
+		EventType: "link.created",
+		Payload:   []byte(`{"id":"link-1","shortUrl":"golang"}`),
+		Attempts:  attempts,
+		CreatedAt: pgtype.Timestamp{Time: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		Url:       url,
+		Secret:    "whsec_test",
+	}
+}
+
+// deliverTo delivers a test delivery to the handler and returns the recorded attempt.
+func deliverTo(t *testing.T, handler http.HandlerFunc, client *http.Client, attempts int32) Attempt {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	if client == nil {
+		client = srv.Client()
+	}
+
+	queue := &fakeQueue{}
+	NewDispatcher(queue, client, testOptions).deliver(context.Background(), testDelivery(t, srv.URL+"/hook", attempts))
+
+	if len(queue.attempts) != 1 {
+		t.Fatalf("got %d recorded attempts, want 1", len(queue.attempts))
+	}
+	return queue.attempts[0]
+}
+
+func TestDispatcherDelivery(t *testing.T) {
+	var request *http.Request
+	var body []byte
+	attempt := deliverTo(t, func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			t.Error(err)
+		}
+		request = r.Clone(context.Background())
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, "queued")
+	}, nil, 0)
+
+	if attempt.Status != StatusSucceeded || attempt.Error != nil || attempt.RetryIn != 0 {
+		t.Errorf("got attempt %+v, want a success", attempt)
+	}
+	if attempt.ResponseStatus == nil || *attempt.ResponseStatus != http.StatusAccepted || attempt.ResponseBody == nil || *attempt.ResponseBody != "queued" {
+		t.Errorf("got response %v %v, want 202 queued", attempt.ResponseStatus, attempt.ResponseBody)
+	}
+	if attempt.DeliveryID.String() != "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f" || attempt.WebhookID.String() != "0a1b2c3d-4e5f-4a6b-8c7d-8e9f0a1b2c3d" {
+		t.Errorf("got attempt of delivery %s of webhook %s", attempt.DeliveryID, attempt.WebhookID)
+	}
+
+	// The request carries the event and is signed with the secret of the webhook
+	if request.Method != http.MethodPost || request.URL.Path != "/hook" {
+		t.Errorf("got request %s %s", request.Method, request.URL.Path)
+	}
+	for name, want := range map[string]string{
+		"Content-Type": "application/json",
+		HeaderEvent:    "link.created",
+		HeaderDelivery: "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f",
+	} {
+		if got := request.Header.Get(name); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+	timestamp, err := strconv.ParseInt(request.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil || time.Since(time.Unix(timestamp, 0)).Abs() > time.Minute {
+		t.Errorf("%s: got %q", HeaderTimestamp, request.Header.Get(HeaderTimestamp))
+	}
+	if !Verify("whsec_test", request.Header.Get(HeaderSignature), timestamp, body) {
+		t.Errorf("%s: got %q, which does not match the body", HeaderSignature, request.Header.Get(HeaderSignature))
+	}
+
+	var event types.WebhookEventDTO
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != 42 || event.Type != "link.created" || event.DeliveryID != "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f" || string(event.Data) != `{"id":"link-1","shortUrl":"golang"}` {
+		t.Errorf("got event %+v", event)
+	}
+}
+
+func TestDispatcherFailures(t *testing.T) {
+	failing := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, strings.Repeat("x", 2*maxResponseBody)+"\x00")
+	}
+
+	// Failed attempts are retried after a backoff
+	attempt := deliverTo(t, failing, nil, 0)
+	if attempt.Status != StatusPending || attempt.RetryIn < 30*time.Second || attempt.RetryIn > time.Minute {
+		t.Errorf("got attempt %+v, want pending with a retry within a minute", attempt)
+	}
+	if attempt.Error == nil || !strings.Contains(*attempt.Error, "500 Internal Server Error") {
+		t.Errorf("got error %v, want the status of the response", attempt.Error)
+	}
+	if attempt.ResponseBody == nil || len(*attempt.ResponseBody) != maxResponseBody {
+		t.Errorf("got a response body of %d bytes, want %d", len(*attempt.ResponseBody), maxResponseBody)
+	}
+
+	// Until the last attempt
+	if attempt := deliverTo(t, failing, nil, int32(testOptions.MaxAttempts-1)); attempt.Status != StatusFailed || attempt.RetryIn != 0 {
+		t.Errorf("got last attempt %+v, want failed", attempt)
+	}
+
+	// Redirects are not followed
+	attempt = deliverTo(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}, NewHTTPClient(time.Second, true), 0)
+	if attempt.Status != StatusPending || attempt.ResponseStatus == nil || *attempt.ResponseStatus != http.StatusFound {
+		t.Errorf("got attempt %+v, want the redirect to fail", attempt)
+	}
+
+	// Loopback addresses are refused unless private networks are allowed
+	attempt = deliverTo(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the private address was dialed")
+	}, NewHTTPClient(time.Second, false), 0)
+	if attempt.Status != StatusPending || attempt.Error == nil || !strings.Contains(*attempt.Error, errPrivateAddress.Error()) {
+		t.Errorf("got attempt %+v, want the address to be refused", attempt)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(&fakeQueue{}, nil, testOptions)
+
+	// The delay doubles from a minute, up to an hour, and the first half of it is fixed
+	for _, tt := range []struct {
+		attempts int
+		max      time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{20, time.Hour},
+		{1000, time.Hour},
+	} {
+		for range 20 {
+			if got := d.backoff(tt.attempts); got < tt.max/2 || got > tt.max {
+				t.Errorf("backoff(%d): got %v, want between %v and %v", tt.attempts, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/OmprakashD20/refero-api/openapi"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// Docs describes the webhook routes for the OpenAPI document.
+var Docs = []openapi.Route{
+	{
+		Method:      http.MethodPost,
+		Path:        "/",
+		OperationID: "createWebhook",
+		Summary:     "Register a webhook",
+		Description: "A secret is generated when none is given. The response is the only one including it.",
+		Body:        validator.CreateWebhookPayload{},
+		Responses:   []openapi.Response{{Status: http.StatusCreated, Body: types.WebhookDTO{}}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/",
+		OperationID: "listWebhooks",
+		Summary:     "List the webhooks",
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: []types.WebhookDTO{}}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/:id",
+		OperationID: "getWebhook",
+		Summary:     "Get a webhook",
+		Params:      validator.GetWebhookByIDParam{},
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: types.WebhookDTO{}}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/:id/deliveries",
+		OperationID: "listWebhookDeliveries",
+		Summary:     "List the latest deliveries of a webhook",
+		Params:      validator.GetWebhookDeliveriesParam{},
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: []types.WebhookDeliveryDTO{}}},
+	},
+	{
+		Method:      http.MethodPut,
+		Path:        "/:id",
+		OperationID: "updateWebhook",
+		Summary:     "Replace a webhook",
+		Description: "Enabling a disabled webhook resets its failure count.",
+		Params:      validator.UpdateWebhookByIDParam{},
+		Body:        validator.UpdateWebhookPayload{},
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: types.WebhookDTO{}}},
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/:id/deliveries/:deliveryId/redeliver",
+		OperationID: "redeliverWebhook",
+		Summary:     "Queue the event of a delivery once more",
+		Params:      validator.RedeliverWebhookParams{},
+		Responses:   []openapi.Response{{Status: http.StatusAccepted, Description: "The new delivery", Body: types.WebhookDeliveryDTO{}}},
+	},
+	{
+		Method:      http.MethodDelete,
+		Path:        "/:id",
+		OperationID: "deleteWebhook",
+		Summary:     "Delete a webhook",
+		Params:      validator.DeleteWebhookByIDParam{},
+		Responses:   []openapi.Response{{Status: http.StatusOK}},
+	},
+}
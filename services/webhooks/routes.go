@@ -0,0 +1,215 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deliveryLogSize is the number of latest deliveries listed per webhook
+const deliveryLogSize = 100
+
+type WebhookService struct {
+	store types.WebhookStore
+}
+
+func NewService(store types.WebhookStore) *WebhookService {
+	return &WebhookService{store}
+}
+
+func (s *WebhookService) SetupWebhookRoutes(api *gin.RouterGroup) {
+	api.POST("/", validator.ValidateBody[validator.CreateWebhookPayload](), s.CreateWebhookHandler)
+
+	api.GET("/", s.GetWebhooksHandler)
+	api.GET("/:id", validator.ValidateParams[validator.GetWebhookByIDParam](), s.GetWebhookByIDHandler)
+	api.GET("/:id/deliveries", validator.ValidateParams[validator.GetWebhookDeliveriesParam](), s.GetWebhookDeliveriesHandler)
+
+	api.PUT("/:id", validator.ValidateParams[validator.UpdateWebhookByIDParam](), validator.ValidateBody[validator.UpdateWebhookPayload](), s.UpdateWebhookByIDHandler)
+	api.POST("/:id/deliveries/:deliveryId/redeliver", validator.ValidateParams[validator.RedeliverWebhookParams](), s.RedeliverWebhookHandler)
+
+	api.DELETE("/:id", validator.ValidateParams[validator.DeleteWebhookByIDParam](), s.DeleteWebhookByIDHandler)
+}
+
+// CreateWebhookHandler registers a webhook. The response is the only one including its secret.
+func (s *WebhookService) CreateWebhookHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	webhook, ok := validator.GetValidatedData[validator.CreateWebhookPayload](c, validator.ValidatedBodyKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	secret := webhook.Secret
+	if secret == "" {
+		secret = GenerateSecret()
+	}
+
+	created, err := s.store.CreateWebhook(ctx, webhook, secret)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToCreateWebhook), errs.WithCause(err)))
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (s *WebhookService) GetWebhooksHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	webhooks, err := s.store.GetAllWebhooks(ctx)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+
+	// No webhooks registered
+	if webhooks == nil {
+		c.JSON(http.StatusOK, []types.WebhookDTO{})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func (s *WebhookService) GetWebhookByIDHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, ok := validator.GetValidatedData[validator.GetWebhookByIDParam](c, validator.ValidatedParamKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	webhook, err := s.store.GetWebhookByID(ctx, params.ID)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+
+	// No webhook found with the Params ID
+	if webhook == nil {
+		c.Error(errs.NotFound(errs.ErrWebhookNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (s *WebhookService) UpdateWebhookByIDHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, ok := validator.GetValidatedData[validator.UpdateWebhookByIDParam](c, validator.ValidatedParamKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	webhook, ok := validator.GetValidatedData[validator.UpdateWebhookPayload](c, validator.ValidatedBodyKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	updated, err := s.store.UpdateWebhookByID(ctx, params.ID, webhook)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToUpdateWebhook), errs.WithCause(err)))
+		return
+	}
+
+	// No webhook found with the Params ID
+	if updated == nil {
+		c.Error(errs.NotFound(errs.ErrWebhookNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (s *WebhookService) DeleteWebhookByIDHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, ok := validator.GetValidatedData[validator.DeleteWebhookByIDParam](c, validator.ValidatedParamKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	if err := s.store.DeleteWebhookByID(ctx, params.ID); err != nil {
+		// If webhook doesn't exists
+		if errors.Is(err, errs.ErrWebhookNotFound) {
+			c.Error(errs.NotFound(errs.ErrWebhookNotFound))
+			return
+		}
+
+		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToDeleteWebhook), errs.WithCause(err)))
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// GetWebhookDeliveriesHandler lists the latest deliveries of the webhook, newest first.
+func (s *WebhookService) GetWebhookDeliveriesHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, ok := validator.GetValidatedData[validator.GetWebhookDeliveriesParam](c, validator.ValidatedParamKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	webhook, err := s.store.GetWebhookByID(ctx, params.ID)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+	if webhook == nil {
+		c.Error(errs.NotFound(errs.ErrWebhookNotFound))
+		return
+	}
+
+	deliveries, err := s.store.GetWebhookDeliveries(ctx, params.ID, deliveryLogSize)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithCause(err)))
+		return
+	}
+
+	// Nothing was delivered yet
+	if deliveries == nil {
+		c.JSON(http.StatusOK, []types.WebhookDeliveryDTO{})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverWebhookHandler queues the event of a delivery once more, as a new delivery.
+func (s *WebhookService) RedeliverWebhookHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params, ok := validator.GetValidatedData[validator.RedeliverWebhookParams](c, validator.ValidatedParamKey)
+	if !ok {
+		c.Error(errs.BadRequest(errs.ErrInvalidPayload))
+		return
+	}
+
+	delivery, err := s.store.RedeliverWebhookDelivery(ctx, params.ID, params.DeliveryID)
+	if err != nil {
+		c.Error(errs.InternalServerError(errs.WithError(errs.ErrFailedToRedeliver), errs.WithCause(err)))
+		return
+	}
+
+	// No delivery found for the webhook
+	if delivery == nil {
+		c.Error(errs.NotFound(errs.ErrWebhookDeliveryNotFound))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
@@ -0,0 +1,41 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers of the requests sent to webhooks
+const (
+	HeaderEvent     = "X-Refero-Event"
+	HeaderDelivery  = "X-Refero-Delivery"
+	HeaderTimestamp = "X-Refero-Timestamp"
+	HeaderSignature = "X-Refero-Signature"
+)
+
+// Sign computes the signature sent in the X-Refero-Signature header, an HMAC-SHA256 of the
+// timestamp and the body. Receivers recompute it over the X-Refero-Timestamp header and the
+// raw body, and should reject old timestamps so that captured requests can't be replayed.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature in constant time.
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// GenerateSecret returns a random key for signing the payloads of a webhook.
+func GenerateSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	// Computed with: printf '1700000000.{"id":1}' | openssl dgst -sha256 -hmac whsec_test
+	const want = "sha256=2f441ba4b3b2d50d28a9ab9d9fd8880376ecd1eb5d0435401553f5d8d0a5dcf8"
+
+	got := Sign("whsec_test", 1700000000, []byte(`{"id":1}`))
+	if got != want {
+		t.Errorf("Sign: got %q, want %q", got, want)
+	}
+
+	if !Verify("whsec_test", got, 1700000000, []byte(`{"id":1}`)) {
+		t.Error("Verify rejected the signature")
+	}
+
+	// The signature covers the secret, the timestamp and the body
+	for _, tt := range []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+	}{
+		{"secret", "whsec_other", 1700000000, `{"id":1}`},
+		{"timestamp", "whsec_test", 1700000001, `{"id":1}`},
+		{"body", "whsec_test", 1700000000, `{"id":2}`},
+	} {
+		if Verify(tt.secret, got, tt.timestamp, []byte(tt.body)) {
+			t.Errorf("Verify accepted the signature with another %s", tt.name)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, b := GenerateSecret(), GenerateSecret()
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 {
+		t.Errorf("GenerateSecret: got %q", a)
+	}
+	if a == b {
+		t.Error("GenerateSecret returned the same secret twice")
+	}
+}
@@ -0,0 +1,212 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/database"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+	"github.com/OmprakashD20/refero-api/utils"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+type Store struct {
+	conn *pgxpool.Pool
+	db   *repository.Queries
+}
+
+func NewStore(conn *pgxpool.Pool) *Store {
+	return &Store{conn: conn, db: repository.New(conn)}
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, webhook validator.CreateWebhookPayload, secret string) (*types.WebhookDTO, error) {
+	args := repository.CreateWebhookParams{
+		Url:         webhook.URL,
+		Description: webhook.Description,
+		Secret:      secret,
+		EventTypes:  webhook.EventTypes,
+	}
+
+	data, err := s.db.CreateWebhook(ctx, args)
+	if err != nil {
+		return nil, errs.TranslatePgError(err)
+	}
+
+	created := toWebhookDTO(repository.GetWebhookByIDRow(data))
+	created.Secret = secret
+	return created, nil
+}
+
+func (s *Store) GetAllWebhooks(ctx context.Context) ([]types.WebhookDTO, error) {
+	data, err := s.db.GetAllWebhooks(ctx)
+	if err != nil {
+		return errs.IsErrNoRows[[]types.WebhookDTO](err, nil)
+	}
+
+	webhooks := make([]types.WebhookDTO, len(data))
+	for i, webhook := range data {
+		webhooks[i] = *toWebhookDTO(repository.GetWebhookByIDRow(webhook))
+	}
+
+	return webhooks, nil
+}
+
+func (s *Store) GetWebhookByID(ctx context.Context, id string) (*types.WebhookDTO, error) {
+	data, err := s.db.GetWebhookByID(ctx, utils.ToPgUUID(id))
+	if err != nil {
+		return errs.IsErrNoRows[*types.WebhookDTO](err, nil)
+	}
+
+	return toWebhookDTO(data), nil
+}
+
+func (s *Store) UpdateWebhookByID(ctx context.Context, id string, webhook validator.UpdateWebhookPayload) (*types.WebhookDTO, error) {
+	args := repository.UpdateWebhookParams{
+		ID:          utils.ToPgUUID(id),
+		Url:         webhook.URL,
+		Description: webhook.Description,
+		EventTypes:  webhook.EventTypes,
+		Enabled:     *webhook.Enabled,
+	}
+
+	data, err := s.db.UpdateWebhook(ctx, args)
+	if err != nil {
+		// Webhook does not exists in the database
+		return errs.IsErrNoRows[*types.WebhookDTO](err, nil)
+	}
+
+	return toWebhookDTO(repository.GetWebhookByIDRow(data)), nil
+}
+
+func (s *Store) DeleteWebhookByID(ctx context.Context, id string) error {
+	rows, err := s.db.DeleteWebhook(ctx, utils.ToPgUUID(id))
+	if rows == 0 && err == nil {
+		// Webhook does not exists in the database
+		return errs.ErrWebhookNotFound
+	}
+
+	return errs.TranslatePgError(err)
+}
+
+func (s *Store) GetWebhookDeliveries(ctx context.Context, id string, limit int32) ([]types.WebhookDeliveryDTO, error) {
+	data, err := s.db.GetWebhookDeliveries(ctx, repository.GetWebhookDeliveriesParams{
+		WebhookID:     utils.ToPgUUID(id),
+		MaxDeliveries: limit,
+	})
+	if err != nil {
+		return errs.IsErrNoRows[[]types.WebhookDeliveryDTO](err, nil)
+	}
+
+	deliveries := make([]types.WebhookDeliveryDTO, len(data))
+	for i, delivery := range data {
+		deliveries[i] = *toDeliveryDTO(delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (s *Store) RedeliverWebhookDelivery(ctx context.Context, id, deliveryID string) (*types.WebhookDeliveryDTO, error) {
+	data, err := s.db.RedeliverWebhookDelivery(ctx, repository.RedeliverWebhookDeliveryParams{
+		ID:        utils.ToPgUUID(deliveryID),
+		WebhookID: utils.ToPgUUID(id),
+	})
+	if err != nil {
+		// Delivery does not exists for the webhook
+		return errs.IsErrNoRows[*types.WebhookDeliveryDTO](err, nil)
+	}
+
+	return toDeliveryDTO(repository.GetWebhookDeliveriesRow(data)), nil
+}
+
+// ClaimDeliveries leases up to limit due deliveries, which are not attempted by other instances
+// until the lease expires or the attempt is recorded.
+func (s *Store) ClaimDeliveries(ctx context.Context, limit int32, lease time.Duration) ([]repository.ClaimWebhookDeliveriesRow, error) {
+	return s.db.ClaimWebhookDeliveries(ctx, repository.ClaimWebhookDeliveriesParams{
+		MaxDeliveries: limit,
+		LeaseSeconds:  int64(lease.Seconds()),
+	})
+}
+
+// RecordAttempt stores the outcome of a delivery attempt, and updates the consecutive failures of
+// the webhook. It reports whether the webhook was disabled by this failure.
+func (s *Store) RecordAttempt(ctx context.Context, attempt Attempt, maxFailures int) (disabled bool, err error) {
+	err = database.NewTransactionStore(s.conn).Exec(ctx, func(ctx context.Context, q *repository.Queries) error {
+		err := q.RecordWebhookAttempt(ctx, repository.RecordWebhookAttemptParams{
+			ID:             attempt.DeliveryID,
+			Status:         attempt.Status,
+			RetryInSeconds: int64(attempt.RetryIn.Seconds()),
+			ResponseStatus: attempt.ResponseStatus,
+			ResponseBody:   attempt.ResponseBody,
+			Error:          attempt.Error,
+		})
+		if err != nil {
+			return err
+		}
+
+		if attempt.Status == StatusSucceeded {
+			return q.ResetWebhookFailures(ctx, attempt.WebhookID)
+		}
+
+		enabled, err := q.RecordWebhookFailure(ctx, repository.RecordWebhookFailureParams{
+			ID:          attempt.WebhookID,
+			MaxFailures: int32(maxFailures),
+		})
+		disabled = !enabled
+		return err
+	})
+
+	return disabled, err
+}
+
+// PurgeDeliveries deletes the completed deliveries older than the retention period.
+func (s *Store) PurgeDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.db.PurgeWebhookDeliveries(ctx, int64(retention.Seconds()))
+}
+
+func toWebhookDTO(data repository.GetWebhookByIDRow) *types.WebhookDTO {
+	return &types.WebhookDTO{
+		ID:           data.ID.String(),
+		URL:          data.Url,
+		Description:  data.Description,
+		EventTypes:   data.EventTypes,
+		Enabled:      data.Enabled,
+		FailureCount: data.FailureCount,
+		DisabledAt:   utils.PgTimestampToTimePtr(data.DisabledAt),
+		CreatedAt:    &data.CreatedAt.Time,
+		UpdatedAt:    &data.UpdatedAt.Time,
+	}
+}
+
+func toDeliveryDTO(data repository.GetWebhookDeliveriesRow) *types.WebhookDeliveryDTO {
+	delivery := &types.WebhookDeliveryDTO{
+		ID:             data.ID.String(),
+		WebhookID:      data.WebhookID.String(),
+		EventID:        data.EventID,
+		EventType:      data.EventType,
+		Status:         data.Status,
+		Attempts:       data.Attempts,
+		LastAttemptAt:  utils.PgTimestampToTimePtr(data.LastAttemptAt),
+		ResponseStatus: data.ResponseStatus,
+		ResponseBody:   data.ResponseBody,
+		Error:          data.Error,
+		CreatedAt:      &data.CreatedAt.Time,
+	}
+
+	// Only pending deliveries are attempted again
+	if data.Status == StatusPending {
+		delivery.NextAttemptAt = &data.NextAttemptAt.Time
+	}
+
+	return delivery
+}
@@ -0,0 +1,213 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/OmprakashD20/refero-api/apitest"
+	"github.com/OmprakashD20/refero-api/database/dbtest"
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/repository"
+	"github.com/OmprakashD20/refero-api/types"
+	validator "github.com/OmprakashD20/refero-api/validations"
+)
+
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
+
+func createWebhook(t *testing.T, store *Store, url string, eventTypes ...string) *types.WebhookDTO {
+	t.Helper()
+
+	payload := validator.CreateWebhookPayload{WebhookPayload: validator.WebhookPayload{URL: url, EventTypes: eventTypes}}
+	webhook, err := store.CreateWebhook(context.Background(), payload, "0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return webhook
+}
+
+// enqueue queues a delivery of an event of the given type, the way publishing the event does.
+func enqueue(t *testing.T, pool *pgxpool.Pool, eventID int64, eventType string) {
+	t.Helper()
+
+	_, err := repository.New(pool).EnqueueWebhookDeliveries(context.Background(), repository.EnqueueWebhookDeliveriesParams{
+		EventID:   eventID,
+		EventType: eventType,
+		Payload:   []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoreWebhooks(t *testing.T) {
+	store, _ := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	created := createWebhook(t, store, "https://hooks.example.com/links", "link.*")
+	if created.Secret == "" || !created.Enabled || created.ID == "" {
+		t.Errorf("CreateWebhook: got %+v", created)
+	}
+	createWebhook(t, store, "https://hooks.example.com/categories", "category.created")
+
+	webhook, err := store.GetWebhookByID(ctx, created.ID)
+	if err != nil || webhook == nil || webhook.URL != created.URL || webhook.Secret != "" {
+		t.Errorf("GetWebhookByID: got %+v, %v", webhook, err)
+	}
+	if webhook, err := store.GetWebhookByID(ctx, apitest.UnknownID); err != nil || webhook != nil {
+		t.Errorf("GetWebhookByID of a missing webhook: got %+v, %v", webhook, err)
+	}
+
+	all, err := store.GetAllWebhooks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].ID != created.ID {
+		t.Errorf("GetAllWebhooks: got %+v, want the oldest first", all)
+	}
+
+	enabled := false
+	update := validator.UpdateWebhookPayload{
+		WebhookPayload: validator.WebhookPayload{URL: "https://hooks.example.com/all", EventTypes: []string{"*"}},
+		Enabled:        &enabled,
+	}
+	updated, err := store.UpdateWebhookByID(ctx, created.ID, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated == nil || updated.URL != "https://hooks.example.com/all" || updated.Enabled {
+		t.Errorf("UpdateWebhookByID: got %+v", updated)
+	}
+	if updated, err := store.UpdateWebhookByID(ctx, apitest.UnknownID, update); err != nil || updated != nil {
+		t.Errorf("UpdateWebhookByID of a missing webhook: got %+v, %v", updated, err)
+	}
+
+	if err := store.DeleteWebhookByID(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteWebhookByID(ctx, created.ID); !errors.Is(err, errs.ErrWebhookNotFound) {
+		t.Errorf("DeleteWebhookByID of a deleted webhook: got %v, want %v", err, errs.ErrWebhookNotFound)
+	}
+}
+
+func TestStoreDeliveries(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	webhook := createWebhook(t, store, "https://hooks.example.com/links", "link.*")
+	other := createWebhook(t, store, "https://hooks.example.com/categories", "category.*")
+	enqueue(t, pool, 1, "link.created")
+	enqueue(t, pool, 2, "link.deleted")
+
+	claimed, err := store.ClaimDeliveries(ctx, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].WebhookID.String() != webhook.ID || claimed[0].Url != webhook.URL {
+		t.Fatalf("ClaimDeliveries: got %+v", claimed)
+	}
+
+	// Leased deliveries are not claimed again until the lease expires
+	rest, err := store.ClaimDeliveries(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 || rest[0].ID == claimed[0].ID {
+		t.Fatalf("ClaimDeliveries of the remaining deliveries: got %+v", rest)
+	}
+
+	status := int32(503)
+	disabled, err := store.RecordAttempt(ctx, Attempt{
+		DeliveryID:     claimed[0].ID,
+		WebhookID:      claimed[0].WebhookID,
+		Status:         StatusFailed,
+		ResponseStatus: &status,
+	}, 2)
+	if err != nil || disabled {
+		t.Errorf("RecordAttempt of the first failure: got disabled %v, %v", disabled, err)
+	}
+	disabled, err = store.RecordAttempt(ctx, Attempt{DeliveryID: rest[0].ID, WebhookID: rest[0].WebhookID, Status: StatusSucceeded}, 2)
+	if err != nil || disabled {
+		t.Errorf("RecordAttempt of a success: got disabled %v, %v", disabled, err)
+	}
+
+	deliveries, err := store.GetWebhookDeliveries(ctx, webhook.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := map[string]string{}
+	for _, delivery := range deliveries {
+		statuses[delivery.ID] = delivery.Status
+	}
+	if len(deliveries) != 2 || statuses[claimed[0].ID.String()] != StatusFailed || statuses[rest[0].ID.String()] != StatusSucceeded {
+		t.Errorf("GetWebhookDeliveries: got %+v", deliveries)
+	}
+	if got, err := store.GetWebhookByID(ctx, webhook.ID); err != nil || got.FailureCount != 0 {
+		t.Errorf("GetWebhookByID after a success: got %+v, %v, want the failures reset", got, err)
+	}
+
+	redelivery, err := store.RedeliverWebhookDelivery(ctx, webhook.ID, claimed[0].ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redelivery == nil || redelivery.Status != StatusPending || redelivery.EventID != claimed[0].EventID {
+		t.Errorf("RedeliverWebhookDelivery: got %+v", redelivery)
+	}
+	if redelivery, err := store.RedeliverWebhookDelivery(ctx, other.ID, claimed[0].ID.String()); err != nil || redelivery != nil {
+		t.Errorf("RedeliverWebhookDelivery of another webhook: got %+v, %v", redelivery, err)
+	}
+
+	// Only completed deliveries are purged
+	purged, err := store.PurgeDeliveries(ctx, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 2 {
+		t.Errorf("PurgeDeliveries purged %d deliveries, want 2", purged)
+	}
+}
+
+func TestStoreDisablesFailingWebhook(t *testing.T) {
+	store, pool := dbtest.Store(t, NewStore)
+	ctx := context.Background()
+
+	webhook := createWebhook(t, store, "https://hooks.example.com/links", "link.created")
+	enqueue(t, pool, 1, "link.created")
+	enqueue(t, pool, 2, "link.created")
+
+	claimed, err := store.ClaimDeliveries(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 2 {
+		t.Fatalf("ClaimDeliveries: got %d deliveries, want 2", len(claimed))
+	}
+
+	var disabled bool
+	for _, delivery := range claimed {
+		message := "connection refused"
+		disabled, err = store.RecordAttempt(ctx, Attempt{DeliveryID: delivery.ID, WebhookID: delivery.WebhookID, Status: StatusFailed, Error: &message}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !disabled {
+		t.Error("RecordAttempt didn't disable the webhook after failing twice in a row")
+	}
+
+	got, err := store.GetWebhookByID(ctx, webhook.ID)
+	if err != nil || got == nil || got.Enabled || got.DisabledAt == nil || got.FailureCount != 2 {
+		t.Errorf("GetWebhookByID of a disabled webhook: got %+v, %v", got, err)
+	}
+
+	// Disabled webhooks are not delivered to
+	enqueue(t, pool, 3, "link.created")
+	if deliveries, err := store.GetWebhookDeliveries(ctx, webhook.ID, 10); err != nil || len(deliveries) != 2 {
+		t.Errorf("GetWebhookDeliveries after disabling the webhook: got %d deliveries, %v", len(deliveries), err)
+	}
+}
@@ -2,8 +2,10 @@ package types
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	"github.com/OmprakashD20/refero-api/events"
 	"github.com/OmprakashD20/refero-api/repository"
 	validator "github.com/OmprakashD20/refero-api/validations"
 )
@@ -14,26 +16,58 @@ type CategoryStore interface {
 	CreateCategory(ctx context.Context, category validator.CreateCategoryPayload) error
 	GetAllCategories(ctx context.Context) ([]CategoryDTO, error)
 	GetCategoryByID(ctx context.Context, id string) (*CategoryDTO, error)
-	UpdateCategoryByID(ctx context.Context, id string, category validator.UpdateCategoryPayload) error
-	DeleteCategoryByID(ctx context.Context, id string) error
+	GetCategoryByName(ctx context.Context, name string) (*CategoryDTO, error)
+	UpdateCategoryByID(ctx context.Context, id string, category validator.UpdateCategoryPayload, version *int32) error
+	DeleteCategoryByID(ctx context.Context, id string, version *int32) error
 	GetLinksForCategory(ctx context.Context, id string) ([]LinkDTO, error)
+	GetCategoriesByIDs(ctx context.Context, ids []string) ([]CategoryDTO, error)
+	GetSubcategoriesOfCategories(ctx context.Context, ids []string) (map[string][]CategoryDTO, error)
+	GetLinksForCategories(ctx context.Context, ids []string) (map[string][]LinkDTO, error)
 }
 
 type LinkStore interface {
 	AddLinkToCategory(ctx context.Context, mappings []LinkCategoryDTO, txn *repository.Queries) error
+	AddCategoriesToLink(ctx context.Context, id string, categoryIDs []string, txn *repository.Queries) error
 	RemoveLinkFromCategory(ctx context.Context, mappings []LinkCategoryDTO, txn *repository.Queries) error
 	CheckIfLinkExistsByURL(ctx context.Context, url string, txn *repository.Queries) (*string, error)
 	CreateLink(ctx context.Context, link validator.CreateLinkPayload, shortUrl string, txn *repository.Queries) (*string, error)
 	GetAllLinks(ctx context.Context) ([]LinkDTO, error)
+	SearchLinks(ctx context.Context, search string, limit, offset int32) ([]LinkDTO, error)
 	GetLinkByID(ctx context.Context, id string) (*LinkDTO, error)
 	GetLinkByShortURL(ctx context.Context, shortUrl string, txn *repository.Queries) (*LinkDTO, error)
 	GetCategoriesForLink(ctx context.Context, id string, txn *repository.Queries) ([]string, error)
-	UpdateLinkByID(ctx context.Context, id string, link validator.UpdateLinkPayload, txn *repository.Queries) error
-	DeleteLinkByID(ctx context.Context, id string, txn *repository.Queries) error
+	GetLinksByIDs(ctx context.Context, ids []string) ([]LinkDTO, error)
+	GetCategoriesForLinks(ctx context.Context, ids []string) (map[string][]CategoryDTO, error)
+	UpdateLinkByID(ctx context.Context, id string, link validator.UpdateLinkPayload, version *int32, txn *repository.Queries) error
+	DeleteLinkByID(ctx context.Context, id string, version *int32, txn *repository.Queries) error
+}
+
+type TrashStore interface {
+	GetTrashedLinks(ctx context.Context) ([]LinkDTO, error)
+	GetTrashedCategories(ctx context.Context) ([]CategoryDTO, error)
+	RestoreLink(ctx context.Context, id string) error
+	RestoreCategory(ctx context.Context, id string) error
+	PurgeTrash(ctx context.Context, retention time.Duration, txn *repository.Queries) (int64, error)
+}
+
+type EventStore interface {
+	ListEventsAfter(ctx context.Context, afterID int64, limit int32) ([]events.Envelope, error)
+	GetLatestEventID(ctx context.Context) (int64, error)
+	PurgeEvents(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type WebhookStore interface {
+	CreateWebhook(ctx context.Context, webhook validator.CreateWebhookPayload, secret string) (*WebhookDTO, error)
+	GetAllWebhooks(ctx context.Context) ([]WebhookDTO, error)
+	GetWebhookByID(ctx context.Context, id string) (*WebhookDTO, error)
+	UpdateWebhookByID(ctx context.Context, id string, webhook validator.UpdateWebhookPayload) (*WebhookDTO, error)
+	DeleteWebhookByID(ctx context.Context, id string) error
+	GetWebhookDeliveries(ctx context.Context, id string, limit int32) ([]WebhookDeliveryDTO, error)
+	RedeliverWebhookDelivery(ctx context.Context, id, deliveryID string) (*WebhookDeliveryDTO, error)
 }
 
 type TransactionStore interface {
-	Exec(ctx context.Context, fn func(q *repository.Queries) error) error
+	Exec(ctx context.Context, fn func(ctx context.Context, q *repository.Queries) error) error
 }
 
 type CategoryDTO struct {
@@ -41,8 +75,10 @@ type CategoryDTO struct {
 	Name        string     `json:"name"`
 	ParentID    *string    `json:"parentId"`
 	Description *string    `json:"description"`
+	Version     int32      `json:"version"`
 	CreatedAt   *time.Time `json:"createdAt,omitempty"`
 	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
 }
 
 type LinkDTO struct {
@@ -51,11 +87,73 @@ type LinkDTO struct {
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
 	ShortUrl    string     `json:"shortUrl"`
+	Version     int32      `json:"version"`
 	CreatedAt   *time.Time `json:"createdAt,omitempty"`
 	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
 }
 
 type LinkCategoryDTO struct {
 	LinkID     string `json:"linkId"`
 	CategoryID string `json:"categoryId"`
 }
+
+// EventDTO is a change delivered by the change feed.
+type EventDTO struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Categories []string        `json:"categories,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type WebhookDTO struct {
+	ID           string     `json:"id"`
+	URL          string     `json:"url"`
+	Description  string     `json:"description"`
+	EventTypes   []string   `json:"eventTypes"`
+	Enabled      bool       `json:"enabled"`
+	FailureCount int32      `json:"failureCount"`
+	Secret       string     `json:"secret,omitempty"` // Only returned when the webhook is created
+	DisabledAt   *time.Time `json:"disabledAt,omitempty"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
+}
+
+type WebhookDeliveryDTO struct {
+	ID             string     `json:"id"`
+	WebhookID      string     `json:"webhookId"`
+	EventID        int64      `json:"eventId"`
+	EventType      string     `json:"eventType"`
+	Status         string     `json:"status"` // "pending", "succeeded" or "failed"
+	Attempts       int32      `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt,omitempty"`
+	ResponseStatus *int32     `json:"responseStatus,omitempty"`
+	ResponseBody   *string    `json:"responseBody,omitempty"`
+	Error          *string    `json:"error,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+}
+
+// WebhookEventDTO is the body of the requests sent to webhooks.
+type WebhookEventDTO struct {
+	ID         int64           `json:"id"` // ID of the event, the same across redeliveries
+	Type       string          `json:"type"`
+	DeliveryID string          `json:"deliveryId"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type TrashDTO struct {
+	Links      []LinkDTO     `json:"links"`
+	Categories []CategoryDTO `json:"categories"`
+}
+
+type ExportDTO struct {
+	Categories []CategoryDTO   `json:"categories"`
+	Links      []LinkExportDTO `json:"links"`
+}
+
+type LinkExportDTO struct {
+	LinkDTO
+	CategoryIDs []string `json:"categoryIds"`
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatETag builds the entity tag for the given row version.
+func FormatETag(version int32) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// MatchETag reports whether the etag is listed in an If-None-Match header value.
+// Weak validators are compared by their opaque tag only.
+func MatchETag(header string, etag string) bool {
+	return matchETag(header, etag, false)
+}
+
+// MatchStrongETag reports whether the etag is listed in an If-Match header value.
+// The comparison is strong, so weak validators never match.
+func MatchStrongETag(header string, etag string) bool {
+	return matchETag(header, etag, true)
+}
+
+func matchETag(header string, etag string, strong bool) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	if strong && strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strong && strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		if strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
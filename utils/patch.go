@@ -0,0 +1,323 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrPatchPathNotFound = errors.New("path not found")
+	ErrPatchInvalidPath  = errors.New("invalid path")
+	ErrPatchTestFailed   = errors.New("test operation failed")
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) to the given document.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, p any
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergePatch(target, p))
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+		t[key] = mergePatch(t[key], value)
+	}
+
+	return t
+}
+
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// JSONPatch applies a JSON Patch (RFC 6902) to the given document.
+// Operations are applied in order and the patch fails as a whole if any of them fails.
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	var ops []jsonPatchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, err
+	}
+
+	var target any
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, op := range ops {
+		var err error
+		if target, err = applyOperation(target, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func applyOperation(doc any, op jsonPatchOperation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("missing value for %q operation", op.Op)
+		}
+
+		var value any
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, err
+		}
+
+		switch op.Op {
+		case "add":
+			return pointerAdd(doc, path, value)
+		case "replace":
+			if doc, _, err = pointerRemove(doc, path); err != nil {
+				return nil, err
+			}
+			return pointerAdd(doc, path, value)
+		default:
+			current, err := pointerGet(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrPatchTestFailed
+			}
+			return doc, nil
+		}
+	case "remove":
+		doc, _, err = pointerRemove(doc, path)
+		return doc, err
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		var value any
+		if op.Op == "move" {
+			// A location cannot be moved into one of its children
+			if len(path) > len(from) && slices.Equal(path[:len(from)], from) {
+				return nil, ErrPatchInvalidPath
+			}
+			if doc, value, err = pointerRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			if value, err = pointerGet(doc, from); err != nil {
+				return nil, err
+			}
+			if value, err = deepCopy(value); err != nil {
+				return nil, err
+			}
+		}
+
+		return pointerAdd(doc, path, value)
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, ErrPatchInvalidPath
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func arrayIndex(token string, length int) (int, error) {
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || idx > length || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPatchPathNotFound
+	}
+	return idx, nil
+}
+
+func pointerGet(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, ErrPatchPathNotFound
+			}
+			doc = value
+		case []any:
+			idx, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[idx]
+		default:
+			return nil, ErrPatchPathNotFound
+		}
+	}
+
+	return doc, nil
+}
+
+func pointerAdd(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token, rest := path[0], path[1:]
+
+	switch node := doc.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			node[token] = value
+			return node, nil
+		}
+
+		child, ok := node[token]
+		if !ok {
+			return nil, ErrPatchPathNotFound
+		}
+
+		child, err := pointerAdd(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = child
+
+		return node, nil
+	case []any:
+		if len(rest) == 0 {
+			if token == "-" {
+				return append(node, value), nil
+			}
+
+			idx, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+
+			node = append(node, nil)
+			copy(node[idx+1:], node[idx:])
+			node[idx] = value
+
+			return node, nil
+		}
+
+		idx, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		child, err := pointerAdd(node[idx], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		node[idx] = child
+
+		return node, nil
+	}
+
+	return nil, ErrPatchPathNotFound
+}
+
+func pointerRemove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+
+	token, rest := path[0], path[1:]
+
+	switch node := doc.(type) {
+	case map[string]any:
+		child, ok := node[token]
+		if !ok {
+			return nil, nil, ErrPatchPathNotFound
+		}
+
+		if len(rest) == 0 {
+			delete(node, token)
+			return node, child, nil
+		}
+
+		child, removed, err := pointerRemove(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		node[token] = child
+
+		return node, removed, nil
+	case []any:
+		idx, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(rest) == 0 {
+			removed := node[idx]
+			return append(node[:idx], node[idx+1:]...), removed, nil
+		}
+
+		child, removed, err := pointerRemove(node[idx], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		node[idx] = child
+
+		return node, removed, nil
+	}
+
+	return nil, nil, ErrPatchPathNotFound
+}
+
+func deepCopy(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
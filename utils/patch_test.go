@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// equalJSON reports whether the documents hold the same value, regardless of key order and spacing.
+func equalJSON(t *testing.T, a, b []byte) bool {
+	t.Helper()
+
+	var x, y any
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatalf("decoding %s: %v", a, err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatalf("decoding %s: %v", b, err)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func TestJSONPatch(t *testing.T) {
+	const doc = `{"title": "Go", "tags": ["a", "b"], "meta": {"a/b": 1, "m~n": 2}}`
+
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+		err   error // Expected error, if any; nil with an empty want only checks that patching fails
+	}{
+		{
+			name:  "add member",
+			patch: `[{"op": "add", "path": "/description", "value": "The Go language"}]`,
+			want:  `{"title": "Go", "description": "The Go language", "tags": ["a", "b"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "add replaces existing member",
+			patch: `[{"op": "add", "path": "/title", "value": "Golang"}]`,
+			want:  `{"title": "Golang", "tags": ["a", "b"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "add inserts into array",
+			patch: `[{"op": "add", "path": "/tags/1", "value": "x"}]`,
+			want:  `{"title": "Go", "tags": ["a", "x", "b"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "add at array length",
+			patch: `[{"op": "add", "path": "/tags/2", "value": "x"}]`,
+			want:  `{"title": "Go", "tags": ["a", "b", "x"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "add appends with dash",
+			patch: `[{"op": "add", "path": "/tags/-", "value": "x"}]`,
+			want:  `{"title": "Go", "tags": ["a", "b", "x"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "add past array length",
+			patch: `[{"op": "add", "path": "/tags/3", "value": "x"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "add with leading zero index",
+			patch: `[{"op": "add", "path": "/tags/01", "value": "x"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "add to missing parent",
+			patch: `[{"op": "add", "path": "/missing/child", "value": "x"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "add replaces document",
+			patch: `[{"op": "add", "path": "", "value": {"title": "Rust"}}]`,
+			want:  `{"title": "Rust"}`,
+		},
+		{
+			name:  "add without value",
+			patch: `[{"op": "add", "path": "/title"}]`,
+		},
+		{
+			name:  "remove member",
+			patch: `[{"op": "remove", "path": "/title"}]`,
+			want:  `{"tags": ["a", "b"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "remove array element",
+			patch: `[{"op": "remove", "path": "/tags/0"}]`,
+			want:  `{"title": "Go", "tags": ["b"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "remove past array end",
+			patch: `[{"op": "remove", "path": "/tags/2"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "remove dash",
+			patch: `[{"op": "remove", "path": "/tags/-"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "remove missing member",
+			patch: `[{"op": "remove", "path": "/description"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "replace member",
+			patch: `[{"op": "replace", "path": "/title", "value": "Golang"}]`,
+			want:  `{"title": "Golang", "tags": ["a", "b"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "replace array element",
+			patch: `[{"op": "replace", "path": "/tags/1", "value": "x"}]`,
+			want:  `{"title": "Go", "tags": ["a", "x"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "replace missing member",
+			patch: `[{"op": "replace", "path": "/description", "value": "x"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "move member",
+			patch: `[{"op": "move", "from": "/title", "path": "/name"}]`,
+			want:  `{"name": "Go", "tags": ["a", "b"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "move array element",
+			patch: `[{"op": "move", "from": "/tags/0", "path": "/tags/-"}]`,
+			want:  `{"title": "Go", "tags": ["b", "a"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "move to itself",
+			patch: `[{"op": "move", "from": "/meta", "path": "/meta"}]`,
+			want:  doc,
+		},
+		{
+			name:  "move into own child",
+			patch: `[{"op": "move", "from": "/meta", "path": "/meta/child"}]`,
+			err:   ErrPatchInvalidPath,
+		},
+		{
+			name:  "move from missing member",
+			patch: `[{"op": "move", "from": "/description", "path": "/title"}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "copy member",
+			patch: `[{"op": "copy", "from": "/tags", "path": "/labels"}]`,
+			want:  `{"title": "Go", "tags": ["a", "b"], "labels": ["a", "b"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "copy is independent of its source",
+			patch: `[{"op": "copy", "from": "/tags", "path": "/labels"}, {"op": "add", "path": "/labels/-", "value": "c"}]`,
+			want:  `{"title": "Go", "tags": ["a", "b"], "labels": ["a", "b", "c"], "meta": {"a/b": 1, "m~n": 2}}`,
+		},
+		{
+			name:  "test passes",
+			patch: `[{"op": "test", "path": "/meta", "value": {"m~n": 2, "a/b": 1}}, {"op": "test", "path": "/tags/1", "value": "b"}]`,
+			want:  doc,
+		},
+		{
+			name:  "test fails",
+			patch: `[{"op": "test", "path": "/title", "value": "Rust"}]`,
+			err:   ErrPatchTestFailed,
+		},
+		{
+			name:  "test compares types",
+			patch: `[{"op": "test", "path": "/meta/a~1b", "value": "1"}]`,
+			err:   ErrPatchTestFailed,
+		},
+		{
+			name:  "failed test discards earlier operations",
+			patch: `[{"op": "replace", "path": "/title", "value": "Rust"}, {"op": "test", "path": "/title", "value": "Go"}]`,
+			err:   ErrPatchTestFailed,
+		},
+		{
+			name:  "test of missing member",
+			patch: `[{"op": "test", "path": "/description", "value": null}]`,
+			err:   ErrPatchPathNotFound,
+		},
+		{
+			name:  "escaped slash",
+			patch: `[{"op": "replace", "path": "/meta/a~1b", "value": 3}]`,
+			want:  `{"title": "Go", "tags": ["a", "b"], "meta": {"a/b": 3, "m~n": 2}}`,
+		},
+		{
+			name:  "escaped tilde",
+			patch: `[{"op": "remove", "path": "/meta/m~0n"}]`,
+			want:  `{"title": "Go", "tags": ["a", "b"], "meta": {"a/b": 1}}`,
+		},
+		{
+			name:  "tilde escapes are not applied twice",
+			doc:   `{"~1": "tilde one", "/": "slash"}`,
+			patch: `[{"op": "remove", "path": "/~01"}]`,
+			want:  `{"/": "slash"}`,
+		},
+		{
+			name:  "path without leading slash",
+			patch: `[{"op": "add", "path": "title", "value": "x"}]`,
+			err:   ErrPatchInvalidPath,
+		},
+		{
+			name:  "unknown operation",
+			patch: `[{"op": "merge", "path": "/title", "value": "x"}]`,
+		},
+		{
+			name:  "patch is not an array",
+			patch: `{"op": "add", "path": "/title", "value": "x"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.doc
+			if source == "" {
+				source = doc
+			}
+
+			got, err := JSONPatch([]byte(source), []byte(tt.patch))
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("got %s, want an error", got)
+				}
+				if tt.err != nil && !errors.Is(err, tt.err) {
+					t.Errorf("got error %v, want %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalJSON(t, got, []byte(tt.want)) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"replace member", `{"a": "b"}`, `{"a": "c"}`, `{"a": "c"}`},
+		{"add member", `{"a": "b"}`, `{"b": "c"}`, `{"a": "b", "b": "c"}`},
+		{"null removes member", `{"a": "b", "b": "c"}`, `{"a": null}`, `{"b": "c"}`},
+		{"arrays are replaced", `{"a": ["b"]}`, `{"a": ["c", "d"]}`, `{"a": ["c", "d"]}`},
+		{"nested merge", `{"a": {"b": "c", "d": "e"}}`, `{"a": {"b": "f", "d": null}}`, `{"a": {"b": "f"}}`},
+		{"object replaces scalar", `{"a": "b"}`, `{"a": {"c": null, "d": "e"}}`, `{"a": {"d": "e"}}`},
+		{"non-object patch replaces document", `{"a": "b"}`, `["c"]`, `["c"]`},
+		{"empty document", ``, `{"a": "b"}`, `{"a": "b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalJSON(t, got, []byte(tt.want)) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := MergePatch([]byte(`{}`), []byte(`{`)); err == nil {
+		t.Error("MergePatch of an invalid patch should fail")
+	}
+}
@@ -31,6 +31,14 @@ func ToPgUUID(uuidStr string) pgtype.UUID {
 	return uuid
 }
 
+func ToPgUUIDs(uuidStrs []string) []pgtype.UUID {
+	uuids := make([]pgtype.UUID, len(uuidStrs))
+	for i, uuidStr := range uuidStrs {
+		uuids[i] = ToPgUUID(uuidStr)
+	}
+	return uuids
+}
+
 func PgUUIDToStringPtr(uuid pgtype.UUID) *string {
 	if uuid.Valid {
 		str := uuid.String()
@@ -39,6 +47,13 @@ func PgUUIDToStringPtr(uuid pgtype.UUID) *string {
 	return nil
 }
 
+func PgTimestampToTimePtr(ts pgtype.Timestamp) *time.Time {
+	if ts.Valid {
+		return &ts.Time
+	}
+	return nil
+}
+
 func GenerateShortURL(url string) string {
 	hash := sha256.Sum256([]byte(url + time.Now().String()))
 	encoded := base64.URLEncoding.EncodeToString(hash[:])
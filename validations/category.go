@@ -18,6 +18,7 @@ type CategoryParams struct {
 type (
 	GetCategoryByIDParam      = CategoryParams
 	UpdateCategoryByIDParam   = CategoryParams
+	PatchCategoryByIDParam    = CategoryParams
 	DeleteCategoryByIDParam   = CategoryParams
 	GetLinksForCategoryParams = CategoryParams
 )
@@ -0,0 +1,7 @@
+package validator
+
+type EventsQuery struct {
+	CategoryIDs []string `form:"category" binding:"omitempty,dive,uuid"`
+	// Browsers resume with the Last-Event-ID header, other clients may use the query instead
+	LastEventID *int64 `form:"lastEventId" binding:"omitempty,gte=0"`
+}
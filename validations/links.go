@@ -19,8 +19,16 @@ type LinkParams struct {
 type (
 	GetLinkByIDParam    = LinkParams
 	UpdateLinkByIDParam = LinkParams
+	PatchLinkByIDParam  = LinkParams
 	DeleteLinkByIDParam = LinkParams
 	RedirectLinkParams  struct {
 		ShortURL string `uri:"shortUrl" binding:"required"`
 	}
 )
+
+// ListLinksQuery filters and pages the links. Without a limit, every matching link is listed.
+type ListLinksQuery struct {
+	Search string `form:"search" binding:"omitempty,max=256"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int32  `form:"offset" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"encoding/json"
+
+	errs "github.com/OmprakashD20/refero-api/errors"
+	"github.com/OmprakashD20/refero-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	MergePatchMediaType = "application/merge-patch+json"
+	JSONPatchMediaType  = "application/json-patch+json"
+)
+
+// PatchPayload holds a raw patch document along with the media type it was sent with.
+type PatchPayload struct {
+	MediaType string
+	Document  []byte
+}
+
+// ValidatePatchBody reads the raw patch document from the request body.
+// JSON Merge Patch (RFC 7396) is used for application/merge-patch+json and plain
+// application/json bodies, and JSON Patch (RFC 6902) for application/json-patch+json.
+func ValidatePatchBody() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mediaType := c.ContentType()
+		switch mediaType {
+		case MergePatchMediaType, binding.MIMEJSON:
+			mediaType = MergePatchMediaType
+		case JSONPatchMediaType:
+		default:
+			c.Error(errs.UnsupportedMediaType(errs.ErrUnsupportedMediaType))
+			c.Abort()
+			return
+		}
+
+		document, err := c.GetRawData()
+		if err != nil {
+			c.Error(errs.InternalServerError(errs.WithCause(err)))
+			c.Abort()
+			return
+		}
+		if !json.Valid(document) {
+			c.Error(errs.BadRequest(errs.ErrInvalidPatch))
+			c.Abort()
+			return
+		}
+
+		c.Set(ValidatedBodyKey, PatchPayload{MediaType: mediaType, Document: document})
+		c.Next()
+	}
+}
+
+// ApplyPatch applies the patch to the current state of a resource and validates the
+// merged result against the binding rules of T.
+func ApplyPatch[T any](current T, patch PatchPayload) (T, error) {
+	var patched T
+
+	document, err := json.Marshal(current)
+	if err != nil {
+		return patched, errs.InternalServerError(errs.WithCause(err))
+	}
+
+	if patch.MediaType == JSONPatchMediaType {
+		document, err = utils.JSONPatch(document, patch.Document)
+	} else {
+		document, err = utils.MergePatch(document, patch.Document)
+	}
+	if err != nil {
+		return patched, errs.Validation(errs.ErrInvalidPatch, errs.WithCause(err))
+	}
+
+	if err := json.Unmarshal(document, &patched); err != nil {
+		return patched, errs.Validation(errs.ErrInvalidPatch, errs.WithCause(err))
+	}
+
+	if err := binding.Validator.ValidateStruct(&patched); err != nil {
+		return patched, ValidationError(err)
+	}
+
+	return patched, nil
+}
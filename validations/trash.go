@@ -0,0 +1,9 @@
+package validator
+
+type TrashParams struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type (
+	RestoreTrashItemParam = TrashParams
+)
@@ -3,21 +3,43 @@ package validator
 import (
 	"errors"
 	"fmt"
-	"net/http"
+	"reflect"
+	"strings"
 
+	errs "github.com/OmprakashD20/refero-api/errors"
 	"github.com/OmprakashD20/refero-api/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
 const (
 	ValidatedBodyKey  = "validatedBody"
 	ValidatedParamKey = "validatedParam"
+	ValidatedQueryKey = "validatedQuery"
 )
 
+func init() {
+	// Report fields by the name clients send them with, instead of the Go struct field name
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "uri", "form"} {
+				name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	}
+}
+
 func GetErrorMsg(fe validator.FieldError) string {
-	field := utils.FormatFieldName(fe.Field())
+	field := utils.FormatFieldName(fe.StructField())
 	constraint := fe.Param()
 
 	switch fe.Tag() {
@@ -28,13 +50,15 @@ func GetErrorMsg(fe validator.FieldError) string {
 	case "gte":
 		return fmt.Sprintf("%s should be greater than or equal to %s", field, constraint)
 	case "min":
-		return fmt.Sprintf("%s should have at least %s characters", field, constraint)
+		return fmt.Sprintf("%s should have at least %s %s", field, constraint, lengthUnit(fe))
 	case "max":
-		return fmt.Sprintf("%s should have at most %s characters", field, constraint)
+		return fmt.Sprintf("%s should have at most %s %s", field, constraint, lengthUnit(fe))
 	case "email":
 		return fmt.Sprintf("%s must be a valid email", field)
 	case "uuid":
 		return fmt.Sprintf("%s must be a valid ID", field)
+	case "url", "http_url":
+		return fmt.Sprintf("%s must be a valid URL", field)
 	case "oneof":
 		return fmt.Sprintf("%s must be one of: %s", field, constraint)
 	}
@@ -42,17 +66,64 @@ func GetErrorMsg(fe validator.FieldError) string {
 	return fmt.Sprintf("%s has an invalid value", field)
 }
 
+// lengthUnit names what the length constraint of the field counts.
+func lengthUnit(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	default:
+		return "characters"
+	}
+}
+
+// ValidationError converts a binding error into an HTTPError listing every field that failed validation.
+// Errors that are not validation errors, such as malformed JSON, are reported as a bad request.
+func ValidationError(err error) *errs.HTTPError {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return errs.BadRequest(errs.ErrInvalidPayload, errs.WithCause(err))
+	}
+
+	fields := make([]errs.FieldError, len(ve))
+	for i, fe := range ve {
+		fields[i] = errs.FieldError{
+			Field:      fieldPath(fe),
+			Constraint: fe.Tag(),
+			Param:      fe.Param(),
+			Message:    GetErrorMsg(fe),
+		}
+	}
+
+	// The first failing field doubles as the error message
+	return errs.Validation(errs.ErrValidationFailed, errs.WithError(fields[0].Message), errs.WithFields(fields...))
+}
+
+// Validate checks values built outside of gin, such as GraphQL or RPC arguments,
+// with the same rules as the payloads and parameters bound from requests.
+func Validate(values ...any) error {
+	for _, value := range values {
+		if err := binding.Validator.ValidateStruct(value); err != nil {
+			return ValidationError(err)
+		}
+	}
+	return nil
+}
+
+// fieldPath returns the path of the field relative to the payload, e.g. "categoryIds[0]".
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
 func ValidateBody[T any]() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var body T
 		if err := c.ShouldBindJSON(&body); err != nil {
-			var ve validator.ValidationErrors
-			if errors.As(err, &ve) {
-				fe := ve[0]
-				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": GetErrorMsg(fe)})
-				return
-			}
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.Error(ValidationError(err))
+			c.Abort()
 			return
 		}
 
@@ -65,13 +136,8 @@ func ValidateParams[T any]() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var uriParams T
 		if err := c.ShouldBindUri(&uriParams); err != nil {
-			var ve validator.ValidationErrors
-			if errors.As(err, &ve) {
-				fe := ve[0]
-				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": GetErrorMsg(fe)})
-				return
-			}
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.Error(ValidationError(err))
+			c.Abort()
 			return
 		}
 
@@ -80,6 +146,20 @@ func ValidateParams[T any]() gin.HandlerFunc {
 	}
 }
 
+func ValidateQuery[T any]() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query T
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.Error(ValidationError(err))
+			c.Abort()
+			return
+		}
+
+		c.Set(ValidatedQueryKey, query)
+		c.Next()
+	}
+}
+
 func GetValidatedData[T any](c *gin.Context, key string) (T, bool) {
 	val, exists := c.Get(key)
 	if !exists {
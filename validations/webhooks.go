@@ -0,0 +1,34 @@
+package validator
+
+type WebhookPayload struct {
+	URL         string   `json:"url" binding:"required,http_url"`
+	Description string   `json:"description" binding:"max=256"`
+	EventTypes  []string `json:"eventTypes" binding:"required,min=1,dive,oneof=* link.* link.created link.updated link.deleted category.* category.created category.updated category.deleted"`
+}
+
+type CreateWebhookPayload struct {
+	WebhookPayload
+	// Key of the payload signatures, generated if left empty
+	Secret string `json:"secret" binding:"omitempty,min=16,max=256"`
+}
+
+type UpdateWebhookPayload struct {
+	WebhookPayload
+	// Enabling a webhook that was disabled after repeated failures resumes its deliveries
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type WebhookParams struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type (
+	GetWebhookByIDParam       = WebhookParams
+	UpdateWebhookByIDParam    = WebhookParams
+	DeleteWebhookByIDParam    = WebhookParams
+	GetWebhookDeliveriesParam = WebhookParams
+	RedeliverWebhookParams    struct {
+		ID         string `uri:"id" binding:"required,uuid"`
+		DeliveryID string `uri:"deliveryId" binding:"required,uuid"`
+	}
+)